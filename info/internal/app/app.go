@@ -3,18 +3,26 @@ package app
 import (
 	"context"
 	"errors"
+	"info/internal/domain/alert"
+	"info/internal/domain/backtest"
 	"info/internal/domain/concentration"
+	"info/internal/domain/discovery"
+	"info/internal/domain/export"
+	"info/internal/domain/import_run"
 	"info/internal/domain/oracul_analytics"
 	"info/internal/domain/oracul_daily_balance_stats"
 	"info/internal/domain/oracul_holder_stats"
 	"info/internal/domain/oracul_speedometers"
+	"info/internal/domain/portfolio"
 	"info/internal/domain/portfolio_item"
 	"info/internal/domain/price_and_cap"
+	"info/internal/domain/screener"
 	"info/internal/integration"
 	"info/internal/pkg/config"
 	"log"
 
 	"info/internal/domain/currency"
+	"info/internal/domain/data_audit"
 	"info/internal/infrastructure"
 	"info/internal/infrastructure/repository/tsdb_cluster"
 )
@@ -31,10 +39,18 @@ type Domain struct {
 	PriceAndCap             *price_and_cap.Service
 	Concentration           *concentration.Service
 	PortfolioItem           *portfolio_item.Service
+	Portfolio               *portfolio.Service
 	OraculAnalytics         *oracul_analytics.Service
 	OraculDailyBalanceStats *oracul_daily_balance_stats.Service
 	OraculHolderStats       *oracul_holder_stats.Service
 	OraculSpeedometers      *oracul_speedometers.Service
+	ImportRun               *import_run.Service
+	Discovery               *discovery.Service
+	Alert                   *alert.Service
+	Backtest                *backtest.Service
+	Screener                *screener.Service
+	DataAudit               *data_audit.Service
+	Export                  *export.Service
 }
 
 // New func is a constructor for the App
@@ -72,15 +88,23 @@ func New(ctx context.Context, cfg *config.Configuration) *App {
 
 func (app *App) SetupServices() {
 	app.Domain = &Domain{
+		ImportRun:               import_run.NewService(tsdb_cluster.NewImportRunReplicaSet(app.Infra.TsDB)),
 		PriceAndCap:             price_and_cap.NewService(tsdb_cluster.NewPriceAndCapReplicaSet(app.Infra.TsDB), app.Integration.CmcAPI),
 		Concentration:           concentration.NewService(tsdb_cluster.NewConcentrationReplicaSet(app.Infra.TsDB), app.Integration.CmcAPI),
-		PortfolioItem:           portfolio_item.NewService(tsdb_cluster.NewPortfolioItemReplicaSet(app.Infra.TsDB), app.Integration.CmcAPI),
 		OraculDailyBalanceStats: oracul_daily_balance_stats.NewService(tsdb_cluster.NewOraculDailyBalanceStatsReplicaSet(app.Infra.TsDB)),
 		OraculHolderStats:       oracul_holder_stats.NewService(tsdb_cluster.NewOraculHolderStatsReplicaSet(app.Infra.TsDB)),
 		OraculSpeedometers:      oracul_speedometers.NewService(tsdb_cluster.NewOraculSpeedometersReplicaSet(app.Infra.TsDB)),
 	}
-	app.Domain.OraculAnalytics = oracul_analytics.NewService(tsdb_cluster.NewOraculAnalyticsReplicaSet(app.Infra.TsDB), app.Integration.OraculAnalyticsAPI, app.Domain.OraculSpeedometers, app.Domain.OraculHolderStats, app.Domain.OraculDailyBalanceStats)
-	app.Domain.Currency = currency.NewService(tsdb_cluster.NewCurrencyReplicaSet(app.Infra.TsDB), app.Domain.PriceAndCap, app.Domain.Concentration, app.Domain.OraculAnalytics, app.Integration.CmcAPI, app.Integration.CmcProAPI)
+	app.Domain.PortfolioItem = portfolio_item.NewService(tsdb_cluster.NewPortfolioItemReplicaSet(app.Infra.TsDB), app.Domain.ImportRun, app.Integration.CmcAPI, app.Integration.CmcAPI.Concurrency())
+	app.Domain.OraculAnalytics = oracul_analytics.NewService(tsdb_cluster.NewOraculAnalyticsReplicaSet(app.Infra.TsDB), app.Integration.OraculAnalyticsAPI, app.Domain.OraculSpeedometers, app.Domain.OraculHolderStats, app.Domain.OraculDailyBalanceStats, app.Domain.ImportRun, app.Integration.OraculAnalyticsAPI.Concurrency())
+	app.Domain.Currency = currency.NewService(tsdb_cluster.NewCurrencyReplicaSet(app.Infra.TsDB), app.Domain.PriceAndCap, app.Domain.Concentration, app.Domain.OraculAnalytics, app.Domain.ImportRun, app.Integration.CmcAPI, app.Integration.CmcProAPI, app.Integration.CmcAPI.Concurrency())
+	app.Domain.Portfolio = portfolio.NewService(app.Domain.PortfolioItem, app.Domain.Currency, app.Domain.PriceAndCap)
+	app.Domain.Discovery = discovery.NewService(tsdb_cluster.NewDiscoveryReplicaSet(app.Infra.TsDB), app.Domain.Currency, app.Integration.CmcProAPI)
+	app.Domain.Alert = alert.NewService(tsdb_cluster.NewAlertReplicaSet(app.Infra.TsDB), app.Domain.Currency, app.Domain.Concentration, app.Domain.PriceAndCap, app.Domain.OraculAnalytics, app.Integration.AlertNotifiers...)
+	app.Domain.Backtest = backtest.NewService(app.Domain.Currency, app.Domain.Concentration, app.Domain.PriceAndCap)
+	app.Domain.Screener = screener.NewService(tsdb_cluster.NewScreenerReplicaSet(app.Infra.TsDB), app.Domain.Currency, app.Domain.Concentration, app.Domain.PriceAndCap, app.Domain.OraculAnalytics, app.Domain.OraculSpeedometers)
+	app.Domain.DataAudit = data_audit.NewService(tsdb_cluster.NewDataAuditReplicaSet(app.Infra.TsDB), app.Domain.Currency)
+	app.Domain.Export = export.NewService(tsdb_cluster.NewExportReplicaSet(app.Infra.TsDB), app.Domain.Currency)
 }
 
 func (app *App) Run() error {
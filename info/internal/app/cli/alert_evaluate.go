@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/alert"
+)
+
+// alertEvaluate ...
+var alertEvaluate = &cobra.Command{
+	Use:   "alert-evaluate",
+	Short: "It is the alert-evaluate command.",
+	Long:  `It is the alert-evaluate command: checks the enabled alert rules against the imported data, delivers the fired alerts through the notifiers of the rules and prints them. The collectors run it after every successful import.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.alertEvaluate(cmd, args)
+	},
+}
+
+func (app *App) alertEvaluate(cmd *cobra.Command, args []string) {
+	l, _ := app.alert_Evaluate(app.ctx)
+	if l == nil {
+		return
+	}
+	alertEvaluate_Print(l)
+}
+
+// alert_Evaluate evaluates the alert rules; the fired alerts are returned even if some rules failed.
+func (app *App) alert_Evaluate(ctx context.Context) (*alert.AlertList, bool) {
+	app.Infra.Logger.Info("Alert.Evaluate: starts evaluation...")
+
+	l, err := app.Domain.Alert.Evaluate(ctx)
+	if err != nil {
+		app.Infra.Logger.Info("Alert.Evaluate: evaluation completed with errors!", zap.Error(err))
+		return l, false
+	}
+	app.Infra.Logger.Info("Alert.Evaluate: evaluation completed successfully!", zap.Int("fired", len(*l)))
+	return l, true
+}
+
+func alertEvaluate_Print(l *alert.AlertList) {
+	if len(*l) == 0 {
+		fmt.Println("no alerts fired")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIRED AT\tRULE\tKIND\tSYMBOL\tVALUE\tNOTIFIED\tMESSAGE")
+	var item alert.Alert
+	for _, item = range *l {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%g\t%s\t%s\n", item.FiredAt.Format(timeFormat4Output), item.RuleName, item.Kind, item.Symbol, item.Value, strings.Join(item.Notified, ","), item.Message)
+	}
+	w.Flush()
+}
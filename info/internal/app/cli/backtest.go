@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/backtest"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/trend"
+)
+
+const (
+	flag_Strategies = "strategies"
+	flag_Name       = "name"
+	flag_Cohort     = "cohort"
+	flag_Direction  = "direction"
+	flag_Change     = "change"
+	flag_Lookback   = "lookback"
+	flag_Hold       = "hold"
+	flag_StopLoss   = "stop-loss"
+	flag_TakeProfit = "take-profit"
+	flag_Capital    = "capital"
+	flag_Stake      = "stake"
+	flag_Format     = "format"
+	flag_Table      = "table"
+	flag_Output     = "output"
+
+	format_JSON = "json"
+	format_CSV  = "csv"
+
+	table_Trades  = "trades"
+	table_Equity  = "equity"
+	table_Summary = "summary"
+)
+
+// backtestCmd ...
+var backtestCmd = &cobra.Command{
+	Use:   "backtest",
+	Short: "It is the backtest command.",
+	Long:  `It is the backtest command: replays the stored concentration and price history day by day with the entry and exit rules of the strategies and writes the trades, the equity curves, the hit rates, the average returns and the max drawdowns as JSON or CSV. The strategies are taken from the JSON file of --strategies or from the flags of the single strategy. It reads the database only.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.backtest(cmd, args)
+	},
+}
+
+func init() {
+	backtestCmd.Flags().StringSlice(flag_Slugs, nil, "slugs of the currencies, comma separated; all the observed currencies if both --slugs and --ids are empty")
+	backtestCmd.Flags().UintSlice(flag_IDs, nil, "IDs of the currencies, comma separated")
+	backtestCmd.Flags().String(flag_From, "", "start of the window, "+time.DateOnly+"; a year before the end if empty")
+	backtestCmd.Flags().String(flag_To, "", "end of the window (inclusive), "+time.DateOnly+"; today if empty")
+	backtestCmd.Flags().String(flag_Strategies, "", "JSON file with the list of the strategies with the fields of the strategy in any case (Name, Cohort, Direction, ChangePercent, LookbackDays, HoldDays, StopLossPercent, TakeProfitPercent); the strategy flags are ignored if it is set")
+	backtestCmd.Flags().String(flag_Name, "strategy", "name of the strategy")
+	backtestCmd.Flags().String(flag_Cohort, backtest.Cohort_Whales, "cohort of the entry signal: whales, investors or retail")
+	backtestCmd.Flags().String(flag_Direction, trend.Direction_Rise, "direction of the cohort share change: rise or fall")
+	backtestCmd.Flags().Float64(flag_Change, 0, "entry: the change of the cohort share in percents of the share")
+	backtestCmd.Flags().Uint(flag_Lookback, 0, "entry: the days over which the change is measured")
+	backtestCmd.Flags().Uint(flag_Hold, 0, "exit: the days after the entry")
+	backtestCmd.Flags().Float64(flag_StopLoss, 0, "exit: the fall of the price in percents; not applied if 0")
+	backtestCmd.Flags().Float64(flag_TakeProfit, 0, "exit: the rise of the price in percents; not applied if 0")
+	backtestCmd.Flags().Float64(flag_Capital, backtest.DefaultCapital, "initial equity")
+	backtestCmd.Flags().Float64(flag_Stake, backtest.DefaultStake, "amount invested in every trade")
+	backtestCmd.Flags().String(flag_Format, format_JSON, "output format: json or csv")
+	backtestCmd.Flags().String(flag_Table, table_Trades, "table written as csv: trades, equity or summary")
+	backtestCmd.Flags().StringP(flag_Output, "o", "", "output file; stdout if empty")
+}
+
+func (app *App) backtest(cmd *cobra.Command, args []string) {
+	params, err := backtest_Params(cmd)
+	if err != nil {
+		app.Infra.Logger.Error("backtest: parse flags error", zap.Error(err))
+		return
+	}
+	format, _ := cmd.Flags().GetString(flag_Format)
+	table, _ := cmd.Flags().GetString(flag_Table)
+	if format != format_JSON && format != format_CSV {
+		app.Infra.Logger.Error("backtest: parse flags error", zap.Error(fmt.Errorf("--%s must be %s or %s", flag_Format, format_JSON, format_CSV)))
+		return
+	}
+	if format == format_CSV && table != table_Trades && table != table_Equity && table != table_Summary {
+		app.Infra.Logger.Error("backtest: parse flags error", zap.Error(fmt.Errorf("--%s must be %s, %s or %s", flag_Table, table_Trades, table_Equity, table_Summary)))
+		return
+	}
+
+	res, err := app.Domain.Backtest.Run(app.ctx, params)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			fmt.Println("currencies not found")
+			return
+		}
+		app.Infra.Logger.Error("backtest: Backtest.Run error", zap.Error(err))
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if output, _ := cmd.Flags().GetString(flag_Output); output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			app.Infra.Logger.Error("backtest: create output file error", zap.Error(err))
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == format_JSON {
+		err = backtest_WriteJSON(w, res)
+	} else {
+		err = backtest_WriteCSV(w, res, table)
+	}
+	if err != nil {
+		app.Infra.Logger.Error("backtest: write output error", zap.Error(err))
+	}
+}
+
+func backtest_Params(cmd *cobra.Command) (*backtest.Params, error) {
+	params := &backtest.Params{}
+	var err error
+
+	if params.Slugs, err = cmd.Flags().GetStringSlice(flag_Slugs); err != nil {
+		return nil, err
+	}
+	if params.IDs, err = cmd.Flags().GetUintSlice(flag_IDs); err != nil {
+		return nil, err
+	}
+	if params.Capital, err = cmd.Flags().GetFloat64(flag_Capital); err != nil {
+		return nil, err
+	}
+	if params.Stake, err = cmd.Flags().GetFloat64(flag_Stake); err != nil {
+		return nil, err
+	}
+
+	if from, _ := cmd.Flags().GetString(flag_From); from != "" {
+		if params.From, err = time.Parse(time.DateOnly, from); err != nil {
+			return nil, fmt.Errorf("--%s parse error: %w", flag_From, err)
+		}
+	}
+	if to, _ := cmd.Flags().GetString(flag_To); to != "" {
+		if params.To, err = time.Parse(time.DateOnly, to); err != nil {
+			return nil, fmt.Errorf("--%s parse error: %w", flag_To, err)
+		}
+		// окно включает весь последний день
+		params.To = params.To.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	if file, _ := cmd.Flags().GetString(flag_Strategies); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("--%s read error: %w", flag_Strategies, err)
+		}
+		if err = json.Unmarshal(data, &params.Strategies); err != nil {
+			return nil, fmt.Errorf("--%s parse error: %w", flag_Strategies, err)
+		}
+		return params, nil
+	}
+
+	strategy := backtest.Strategy{}
+	if strategy.Name, err = cmd.Flags().GetString(flag_Name); err != nil {
+		return nil, err
+	}
+	if strategy.Cohort, err = cmd.Flags().GetString(flag_Cohort); err != nil {
+		return nil, err
+	}
+	if strategy.Direction, err = cmd.Flags().GetString(flag_Direction); err != nil {
+		return nil, err
+	}
+	if strategy.ChangePercent, err = cmd.Flags().GetFloat64(flag_Change); err != nil {
+		return nil, err
+	}
+	if strategy.LookbackDays, err = cmd.Flags().GetUint(flag_Lookback); err != nil {
+		return nil, err
+	}
+	if strategy.HoldDays, err = cmd.Flags().GetUint(flag_Hold); err != nil {
+		return nil, err
+	}
+	if strategy.StopLossPercent, err = cmd.Flags().GetFloat64(flag_StopLoss); err != nil {
+		return nil, err
+	}
+	if strategy.TakeProfitPercent, err = cmd.Flags().GetFloat64(flag_TakeProfit); err != nil {
+		return nil, err
+	}
+	params.Strategies = []backtest.Strategy{strategy}
+
+	return params, nil
+}
+
+func backtest_WriteJSON(w io.Writer, res *backtest.Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+func backtest_WriteCSV(w io.Writer, res *backtest.Result, table string) error {
+	cw := csv.NewWriter(w)
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	var item backtest.StrategyResult
+	switch table {
+	case table_Trades:
+		cw.Write([]string{"strategy", "currency_id", "symbol", "signal", "entry_at", "entry_price", "exit_at", "exit_price", "exit_reason", "hold_days", "return_percent", "profit"})
+		var trade backtest.Trade
+		for _, item = range res.Strategies {
+			for _, trade = range item.Trades {
+				cw.Write([]string{trade.Strategy, strconv.FormatUint(uint64(trade.CurrencyID), 10), trade.Symbol, f(trade.Signal), trade.EntryAt.Format(time.DateOnly), f(trade.EntryPrice), trade.ExitAt.Format(time.DateOnly), f(trade.ExitPrice), trade.ExitReason, strconv.FormatUint(uint64(trade.HoldDays), 10), f(trade.ReturnPercent), f(trade.Profit)})
+			}
+		}
+	case table_Equity:
+		cw.Write([]string{"strategy", "d", "equity", "open_trades"})
+		var point backtest.EquityPoint
+		for _, item = range res.Strategies {
+			for _, point = range item.Equity {
+				cw.Write([]string{item.Strategy.Name, point.D.Format(time.DateOnly), f(point.Equity), strconv.FormatUint(uint64(point.OpenTrades), 10)})
+			}
+		}
+	case table_Summary:
+		cw.Write([]string{"strategy", "trades_nb", "wins_nb", "hit_rate", "avg_return", "avg_hold_days", "total_return", "final_equity", "max_drawdown", "max_drawdown_peak_at", "max_drawdown_trough_at"})
+		for _, item = range res.Strategies {
+			cw.Write([]string{item.Strategy.Name, strconv.FormatUint(uint64(item.TradesNb), 10), strconv.FormatUint(uint64(item.WinsNb), 10), f(item.HitRate), f(item.AvgReturn), f(item.AvgHoldDays), f(item.TotalReturn), f(item.FinalEquity), f(item.MaxDrawdown.Percent), item.MaxDrawdown.PeakAt.Format(time.DateOnly), item.MaxDrawdown.TroughAt.Format(time.DateOnly)})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
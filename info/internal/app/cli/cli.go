@@ -68,6 +68,16 @@ func New(ctx context.Context, appName string, coreApp *app.App, apicfg *config.A
 func (app *App) init() {
 	app.rootCmd.AddCommand(
 		currencyCollector,
+		importStatus,
+		currencyBackfill,
+		watchlist,
+		oraculCollector,
+		portfolioReport,
+		alertEvaluate,
+		backtestCmd,
+		screenerCmd,
+		dataAudit,
+		exportCmd,
 	)
 	app.buildHandler()
 }
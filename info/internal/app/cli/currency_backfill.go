@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/currency"
+)
+
+const (
+	flag_Slugs   = "slugs"
+	flag_IDs     = "ids"
+	flag_From    = "from"
+	flag_To      = "to"
+	flag_Dataset = "dataset"
+	flag_DryRun  = "dry-run"
+)
+
+// currencyBackfill ...
+var currencyBackfill = &cobra.Command{
+	Use:   "currency-backfill",
+	Short: "It is the currency-backfill command.",
+	Long:  `It is the currency-backfill command: re-fetches the window [from, to] of price_and_cap and/or concentration for the currencies by slugs or IDs and upserts it. With --dry-run it only prints the planned calls of the upstream API.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.currencyBackfill(cmd, args)
+	},
+}
+
+func init() {
+	currencyBackfill.Flags().StringSlice(flag_Slugs, nil, "slugs of the currencies, comma separated")
+	currencyBackfill.Flags().UintSlice(flag_IDs, nil, "IDs of the currencies, comma separated")
+	currencyBackfill.Flags().String(flag_From, "", "start of the window, "+time.DateOnly)
+	currencyBackfill.Flags().String(flag_To, "", "end of the window (inclusive), "+time.DateOnly+"; today if empty")
+	currencyBackfill.Flags().String(flag_Dataset, currency.Dataset_All, "dataset: price_and_cap, concentration or all")
+	currencyBackfill.Flags().Bool(flag_DryRun, false, "print the planned calls without any call")
+}
+
+func (app *App) currencyBackfill(cmd *cobra.Command, args []string) {
+	params, err := currencyBackfill_Params(cmd)
+	if err != nil {
+		app.Infra.Logger.Error("currency-backfill: parse flags error", zap.Error(err))
+		return
+	}
+	isDryRun, _ := cmd.Flags().GetBool(flag_DryRun)
+
+	plan, err := app.Domain.Currency.PlanBackfill(app.ctx, params)
+	if err != nil {
+		app.Infra.Logger.Error("currency-backfill: Currency.PlanBackfill error", zap.Error(err))
+		return
+	}
+
+	if isDryRun {
+		currencyBackfill_PrintPlan(plan, false)
+		return
+	}
+
+	app.Infra.Logger.Info("currency-backfill: started...")
+	if err = app.Domain.Currency.Backfill(app.ctx, plan); err != nil {
+		app.Infra.Logger.Error("currency-backfill: completed with errors!", zap.Error(err))
+	} else {
+		app.Infra.Logger.Info("currency-backfill: completed successfully!")
+	}
+	currencyBackfill_PrintPlan(plan, true)
+}
+
+func currencyBackfill_Params(cmd *cobra.Command) (*currency.BackfillParams, error) {
+	params := &currency.BackfillParams{}
+	var err error
+
+	if params.Slugs, err = cmd.Flags().GetStringSlice(flag_Slugs); err != nil {
+		return nil, err
+	}
+	if params.IDs, err = cmd.Flags().GetUintSlice(flag_IDs); err != nil {
+		return nil, err
+	}
+	if len(params.Slugs) == 0 && len(params.IDs) == 0 {
+		return nil, fmt.Errorf("--%s or --%s is required", flag_Slugs, flag_IDs)
+	}
+	if params.Dataset, err = cmd.Flags().GetString(flag_Dataset); err != nil {
+		return nil, err
+	}
+
+	from, _ := cmd.Flags().GetString(flag_From)
+	if params.From, err = time.Parse(time.DateOnly, from); err != nil {
+		return nil, fmt.Errorf("--%s parse error: %w", flag_From, err)
+	}
+
+	to, _ := cmd.Flags().GetString(flag_To)
+	if to == "" {
+		params.To = time.Now().UTC()
+	} else {
+		if params.To, err = time.Parse(time.DateOnly, to); err != nil {
+			return nil, fmt.Errorf("--%s parse error: %w", flag_To, err)
+		}
+		// окно включает весь последний день
+		params.To = params.To.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return params, nil
+}
+
+func currencyBackfill_PrintPlan(plan *currency.BackfillPlan, isDone bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if isDone {
+		fmt.Fprintln(w, "CURRENCY\tSLUG\tDATASET\tRANGE\tFROM\tTO\tROWS")
+	} else {
+		fmt.Fprintln(w, "CURRENCY\tSLUG\tDATASET\tRANGE\tFROM\tTO")
+	}
+	var call currency.BackfillCall
+	for _, call = range *plan {
+		if isDone {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%d\n", call.CurrencyID, call.Slug, call.Dataset, call.TimeRange, call.From.Format(timeFormat4Output), call.To.Format(timeFormat4Output), call.RowsNb)
+		} else {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", call.CurrencyID, call.Slug, call.Dataset, call.TimeRange, call.From.Format(timeFormat4Output), call.To.Format(timeFormat4Output))
+		}
+	}
+	w.Flush()
+}
@@ -5,54 +5,107 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"info/internal/pkg/config"
+	"info/internal/pkg/scheduler"
+)
+
+const (
+	flag_Daemon = "daemon"
+
+	job_CurrencyImport  = "currency-import"
+	job_PortfolioImport = "portfolio-import"
+	job_Discovery       = "discovery"
 )
 
 // currencyCollector ...
 var currencyCollector = &cobra.Command{
 	Use:   "currency-collector",
 	Short: "It is the currency-collector command.",
-	Long:  `It is the currency-collector command: consumer for collecting currencies.`,
+	Long:  `It is the currency-collector command: consumer for collecting currencies. The currencies are taken from the watchlist, which is seeded from the config once, while there are no currencies in the database at all. With --daemon flag it runs the imports by the schedules from config until it is stopped.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		CliApp.currencyCollector(cmd, args)
 	},
 }
 
+func init() {
+	currencyCollector.Flags().BoolP(flag_Daemon, "d", false, "run imports by schedule until the process is stopped")
+}
+
 func (app *App) currencyCollector(cmd *cobra.Command, args []string) {
 	cfg := app.config.CurrencyCollector
 
-	//go func() {
-	//	time.Sleep(time.Duration(rand.Int63n(int64(time.Now().Second()%30)+1)) * time.Second)
-	app.currencyCollector_Exec(app.ctx, cfg)
-	//	ticker := time.NewTicker(cfg.Duration)
-	//	defer ticker.Stop()
-	//
-	//	for {
-	//		select {
-	//		case <-app.ctx.Done():
-	//			return
-	//		case <-ticker.C:
-	//			app.currencyCollector_Exec(app.ctx, cfg)
-	//		}
-	//	}
-	//}()
-
-	return
+	isDaemon, err := cmd.Flags().GetBool(flag_Daemon)
+	if err != nil {
+		app.Infra.Logger.Error("currency-collector: parse flags error", zap.Error(err))
+		return
+	}
+
+	if !isDaemon {
+		app.currencyCollector_Exec(app.ctx, cfg)
+		return
+	}
+
+	app.Infra.Logger.Info("currency-collector: daemon mode is started")
+	scheduler.New(app.Infra.Logger).
+		Add(job_CurrencyImport, cfg.CurrencySchedule, func(ctx context.Context) {
+			app.currencyCollector_ExecCurrencyImport(ctx, cfg)
+		}).
+		Add(job_PortfolioImport, cfg.PortfolioSchedule, func(ctx context.Context) {
+			app.currencyCollector_ExecPortfolioImport(ctx, cfg)
+		}).
+		Add(job_Discovery, cfg.DiscoverySchedule, func(ctx context.Context) {
+			app.currencyCollector_ExecDiscovery(ctx, cfg)
+		}).
+		Run(app.ctx)
+	app.Infra.Logger.Info("currency-collector: daemon mode is stopped")
 }
 
 func (app *App) currencyCollector_Exec(ctx context.Context, cfg *config.CurrencyCollector) {
+	if !app.currencyCollector_ExecCurrencyImport(ctx, cfg) {
+		return
+	}
+	app.currencyCollector_ExecPortfolioImport(ctx, cfg)
+}
+
+func (app *App) currencyCollector_ExecCurrencyImport(ctx context.Context, cfg *config.CurrencyCollector) bool {
 	app.Infra.Logger.Info("Currency.Import: starts iteration...")
 
-	if err := app.Domain.Currency.Import(ctx, &cfg.ListOfCurrencySlugs); err != nil {
+	slugs, err := app.Domain.Currency.WatchlistSlugs(ctx, &cfg.ListOfCurrencySlugs)
+	if err != nil {
+		app.Infra.Logger.Info("Currency.Import: getting the watchlist error!", zap.Error(err))
+		return false
+	}
+
+	if err = app.Domain.Currency.Import(ctx, slugs); err != nil {
 		app.Infra.Logger.Info("Currency.Import: iteration completed with errors!", zap.Error(err))
-		return
+		return false
 	}
 	app.Infra.Logger.Info("Currency.Import: iteration completed successfully!")
+	app.alert_Evaluate(ctx)
+	return true
+}
 
+func (app *App) currencyCollector_ExecPortfolioImport(ctx context.Context, cfg *config.CurrencyCollector) bool {
 	app.Infra.Logger.Info("Portfolio.Import: starts iteration...")
 
 	if err := app.Domain.PortfolioItem.Import(ctx, &cfg.PortfolioSourceIDs); err != nil {
 		app.Infra.Logger.Info("PortfolioItem.Import: iteration completed with errors!", zap.Error(err))
-		return
+		return false
 	}
 	app.Infra.Logger.Info("Portfolio.Import: iteration completed successfully!")
+	return true
+}
+
+func (app *App) currencyCollector_ExecDiscovery(ctx context.Context, cfg *config.CurrencyCollector) bool {
+	if cfg.Discovery == nil {
+		app.Infra.Logger.Info("Discovery.Discover: discovery is not configured")
+		return false
+	}
+	app.Infra.Logger.Info("Discovery.Discover: starts iteration...")
+
+	if _, err := app.Domain.Discovery.Discover(ctx, cfg.Discovery); err != nil {
+		app.Infra.Logger.Info("Discovery.Discover: iteration completed with errors!", zap.Error(err))
+		return false
+	}
+	app.Infra.Logger.Info("Discovery.Discover: iteration completed successfully!")
+	return true
 }
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/data_audit"
+)
+
+const (
+	flag_Series       = "series"
+	flag_Days         = "days"
+	flag_MaxPriceJump = "max-price-jump"
+	flag_MaxShareJump = "max-share-jump"
+	flag_Quarantine   = "quarantine"
+)
+
+// dataAudit ...
+var dataAudit = &cobra.Command{
+	Use:   "data-audit",
+	Short: "It is the data-audit command.",
+	Long:  `It is the data-audit command: scans the price and cap, the concentration and the Oracul series of the observed currencies for the missing days, the duplicate and the irregular timestamps, the zero and the negative values, the impossible jumps and the stale series, stores the issues and prints them. With --quarantine the points of the duplicate, non-positive and jump issues are excluded from the reads of the series, so the reports skip them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.dataAudit(cmd, args)
+	},
+}
+
+func init() {
+	dataAudit.Flags().StringSlice(flag_Series, nil, "series to audit, comma separated: cmc.price_and_cap, cmc.concentration, oracul.analytics, oracul.speedometers, oracul.holder_stats, oracul.daily_balance_stats; all if empty")
+	dataAudit.Flags().Uint(flag_Days, data_audit.DefaultDays, "days of the audit window")
+	dataAudit.Flags().Duration(flag_StaleAfter, data_audit.DefaultStaleAfter, "a series without points during this time is stale")
+	dataAudit.Flags().Float64(flag_MaxPriceJump, data_audit.DefaultMaxPriceJump, "max change of the price in a day in percents")
+	dataAudit.Flags().Float64(flag_MaxShareJump, data_audit.DefaultMaxShareJump, "max change of the whale share in a day in percents of the share")
+	dataAudit.Flags().Bool(flag_Quarantine, false, "quarantine the points of the duplicate, non-positive and jump issues")
+	dataAudit.Flags().UintP(flag_Limit, "l", 100, "number of the printed issues; 0 prints all")
+}
+
+func (app *App) dataAudit(cmd *cobra.Command, args []string) {
+	params := &data_audit.Params{}
+	params.Series, _ = cmd.Flags().GetStringSlice(flag_Series)
+	params.Days, _ = cmd.Flags().GetUint(flag_Days)
+	params.StaleAfter, _ = cmd.Flags().GetDuration(flag_StaleAfter)
+	params.MaxPriceJump, _ = cmd.Flags().GetFloat64(flag_MaxPriceJump)
+	params.MaxShareJump, _ = cmd.Flags().GetFloat64(flag_MaxShareJump)
+	params.Quarantine, _ = cmd.Flags().GetBool(flag_Quarantine)
+	limit, _ := cmd.Flags().GetUint(flag_Limit)
+
+	app.Infra.Logger.Info("DataAudit.Audit: starts audit...")
+	run, err := app.Domain.DataAudit.Audit(app.ctx, params)
+	if err != nil {
+		app.Infra.Logger.Error("data-audit: DataAudit.Audit error", zap.Error(err))
+		return
+	}
+	app.Infra.Logger.Info("DataAudit.Audit: audit completed successfully!", zap.Uint("run", run.ID), zap.Uint("issues", run.IssuesNb), zap.Uint("quarantined", run.QuarantinedNb))
+
+	dataAudit_Print(run, limit)
+}
+
+func dataAudit_Print(run *data_audit.Run, limit uint) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "AUDIT %d from %s: %d issues, %d points quarantined\n", run.ID, run.From.Format(timeFormat4Output), run.IssuesNb, run.QuarantinedNb)
+	if len(run.Issues) == 0 {
+		w.Flush()
+		return
+	}
+	fmt.Fprintln(w)
+
+	type key struct {
+		series string
+		kind   string
+	}
+	counts := make(map[key]uint)
+	keys := make([]key, 0)
+	var item data_audit.Issue
+	for _, item = range run.Issues {
+		k := key{series: item.Series, kind: item.Kind}
+		if _, ok := counts[k]; !ok {
+			keys = append(keys, k)
+		}
+		counts[k]++
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].series != keys[j].series {
+			return keys[i].series < keys[j].series
+		}
+		return keys[i].kind < keys[j].kind
+	})
+	fmt.Fprintln(w, "SERIES\tKIND\tISSUES")
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", k.series, k.kind, counts[k])
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SERIES\tSYMBOL\tKIND\tTIME\tVALUE\tQUARANTINED\tMESSAGE")
+	for i, item := range run.Issues {
+		if limit > 0 && uint(i) >= limit {
+			fmt.Fprintf(w, "... %d more\n", len(run.Issues)-i)
+			break
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%g\t%t\t%s\n", item.Series, item.Symbol, item.Kind, item.Ts.Format(timeFormat4Output), item.Value, item.IsQuarantined, item.Message)
+	}
+	w.Flush()
+}
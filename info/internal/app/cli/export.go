@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/export"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/log_key"
+)
+
+// exportCmd ...
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "It is the export command.",
+	Long:  `It is the export command: streams the rows of the dataset (price_and_cap, concentration, oracul_analytics, oracul_speedometers, oracul_holder_stats, oracul_daily_balance_stats or portfolio_history) of the currencies in the window as CSV, NDJSON or Parquet. The rows are read with the database cursor, so the export of any size takes the constant memory. It reads the database only.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.export(cmd, args)
+	},
+}
+
+func init() {
+	exportCmd.Flags().String(flag_Dataset, "", "dataset: price_and_cap, concentration, oracul_analytics, oracul_speedometers, oracul_holder_stats, oracul_daily_balance_stats or portfolio_history")
+	exportCmd.Flags().String(flag_Format, export.Format_CSV, "output format: csv, ndjson or parquet")
+	exportCmd.Flags().StringSlice(flag_Slugs, nil, "slugs of the currencies, comma separated; all the currencies if both --slugs and --ids are empty")
+	exportCmd.Flags().UintSlice(flag_IDs, nil, "IDs of the currencies, comma separated")
+	exportCmd.Flags().String(flag_From, "", "start of the window, "+time.DateOnly+" or RFC3339; from the first row if empty")
+	exportCmd.Flags().String(flag_To, "", "end of the window (inclusive), "+time.DateOnly+" or RFC3339; now if empty")
+	exportCmd.Flags().StringP(flag_Output, "o", "", "output file; stdout if empty, the log is written to stdout too")
+}
+
+func (app *App) export(cmd *cobra.Command, args []string) {
+	params, err := export_Params(cmd)
+	if err != nil {
+		app.Infra.Logger.Error("export: parse flags error", zap.Error(err))
+		return
+	}
+
+	filter, err := app.Domain.Export.Prepare(app.ctx, params)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			fmt.Fprintln(os.Stderr, "currencies not found")
+			return
+		}
+		app.Infra.Logger.Error("export: Export.Prepare error", zap.Error(err))
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if output, _ := cmd.Flags().GetString(flag_Output); output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			app.Infra.Logger.Error("export: create output file error", zap.Error(err))
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+
+	rowsNb, err := app.Domain.Export.Export(app.ctx, filter, w)
+	if err != nil {
+		app.Infra.Logger.Error("export: Export.Export error", zap.String(log_key.Dataset, filter.Dataset), zap.Uint(log_key.Rows, rowsNb), zap.Error(err))
+		return
+	}
+	if w == os.Stdout {
+		// логгер пишет в stdout: не смешиваем его с выгрузкой
+		fmt.Fprintf(os.Stderr, "export of %s completed: %d rows\n", filter.Dataset, rowsNb)
+		return
+	}
+	app.Infra.Logger.Info("Export.Export: export completed successfully!", zap.String(log_key.Dataset, filter.Dataset), zap.String(log_key.Format, filter.Format), zap.Uint(log_key.Rows, rowsNb))
+}
+
+func export_Params(cmd *cobra.Command) (*export.Params, error) {
+	params := &export.Params{}
+	var err error
+
+	if params.Dataset, err = cmd.Flags().GetString(flag_Dataset); err != nil {
+		return nil, err
+	}
+	if params.Dataset == "" {
+		return nil, fmt.Errorf("--%s is required", flag_Dataset)
+	}
+	if params.Format, err = cmd.Flags().GetString(flag_Format); err != nil {
+		return nil, err
+	}
+	if params.Slugs, err = cmd.Flags().GetStringSlice(flag_Slugs); err != nil {
+		return nil, err
+	}
+	if params.IDs, err = cmd.Flags().GetUintSlice(flag_IDs); err != nil {
+		return nil, err
+	}
+
+	if from, _ := cmd.Flags().GetString(flag_From); from != "" {
+		if params.From, err = export_ParseTime(from, false); err != nil {
+			return nil, fmt.Errorf("--%s parse error: %w", flag_From, err)
+		}
+	}
+	if to, _ := cmd.Flags().GetString(flag_To); to != "" {
+		if params.To, err = export_ParseTime(to, true); err != nil {
+			return nil, fmt.Errorf("--%s parse error: %w", flag_To, err)
+		}
+	}
+	return params, nil
+}
+
+// export_ParseTime parses the time in RFC3339 or the date; the date of the end of the window includes the whole day.
+func export_ParseTime(s string, isEnd bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return t, err
+	}
+	if isEnd {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/import_run"
+)
+
+const (
+	flag_Kind       = "kind"
+	flag_Limit      = "limit"
+	flag_StaleAfter = "stale-after"
+	flag_Problems   = "problems"
+
+	timeFormat4Output = "2006-01-02 15:04:05"
+)
+
+// importStatus ...
+var importStatus = &cobra.Command{
+	Use:   "import-status",
+	Short: "It is the import-status command.",
+	Long:  `It is the import-status command: prints the last import runs and the state of every imported currency and portfolio, so the stale and the failing ones are visible at a glance.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.importStatus(cmd, args)
+	},
+}
+
+func init() {
+	importStatus.Flags().StringP(flag_Kind, "k", "", "kind of import: currency, portfolio or oracul; all kinds if empty")
+	importStatus.Flags().UintP(flag_Limit, "l", 10, "number of the last import runs")
+	importStatus.Flags().Duration(flag_StaleAfter, import_run.DefaultStaleAfter, "an item without a successful import during this time is stale")
+	importStatus.Flags().BoolP(flag_Problems, "p", false, "print only the stale and the failing items")
+}
+
+func (app *App) importStatus(cmd *cobra.Command, args []string) {
+	kind, _ := cmd.Flags().GetString(flag_Kind)
+	limit, _ := cmd.Flags().GetUint(flag_Limit)
+	staleAfter, _ := cmd.Flags().GetDuration(flag_StaleAfter)
+	isOnlyProblems, _ := cmd.Flags().GetBool(flag_Problems)
+
+	if err := import_run.KindValidate(kind); err != nil {
+		app.Infra.Logger.Error("import-status: invalid kind", zap.Error(err))
+		return
+	}
+
+	status, err := app.Domain.ImportRun.Status(app.ctx, kind, limit, staleAfter)
+	if err != nil {
+		app.Infra.Logger.Error("import-status: ImportRun.Status error", zap.Error(err))
+		return
+	}
+	if isOnlyProblems {
+		status.Items = status.Items.Problems()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tKIND\tSTATUS\tSUCCEEDED\tFAILED\tSTARTED\tFINISHED")
+	var run import_run.ImportRun
+	for _, run = range *status.Runs {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%s\t%s\n", run.ID, run.Kind, run.Status, run.ItemsSucceeded, run.ItemsFailed, run.StartedAt.Format(timeFormat4Output), formatTimePtr(run.FinishedAt))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "KIND\tKEY\tLAST RUN\tLAST SUCCESS\tSTALE\tLAST ERROR")
+	var item import_run.ItemStatus
+	for _, item = range *status.Items {
+		lastError := ""
+		if item.LastError != nil {
+			lastError = *item.LastError
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%s\n", item.Kind, item.Key, item.LastStartedAt.Format(timeFormat4Output), formatTimePtr(item.LastSuccessAt), item.IsStale, lastError)
+	}
+	if err = w.Flush(); err != nil {
+		app.Infra.Logger.Error("import-status: output error", zap.Error(err))
+	}
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(timeFormat4Output)
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain"
+	"info/internal/pkg/log_key"
+	"info/internal/pkg/scheduler"
+)
+
+const (
+	job_OraculImport = "oracul-import"
+)
+
+// oraculCollector ...
+var oraculCollector = &cobra.Command{
+	Use:   "oracul-collector",
+	Short: "It is the oracul-collector command.",
+	Long:  `It is the oracul-collector command: syncs the token addresses of the watchlist currencies from CMC on all the chains and imports the Oracul analytics for the supported chains. With --daemon flag it runs the import by the schedule from config until it is stopped.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.oraculCollector(cmd, args)
+	},
+}
+
+func init() {
+	oraculCollector.Flags().BoolP(flag_Daemon, "d", false, "run import by schedule until the process is stopped")
+}
+
+func (app *App) oraculCollector(cmd *cobra.Command, args []string) {
+	isDaemon, err := cmd.Flags().GetBool(flag_Daemon)
+	if err != nil {
+		app.Infra.Logger.Error("oracul-collector: parse flags error", zap.Error(err))
+		return
+	}
+
+	if !isDaemon {
+		app.oraculCollector_Exec(app.ctx)
+		return
+	}
+
+	if app.config.OraculCollector == nil {
+		app.Infra.Logger.Error("oracul-collector: schedule is not configured")
+		return
+	}
+
+	app.Infra.Logger.Info("oracul-collector: daemon mode is started")
+	scheduler.New(app.Infra.Logger).
+		Add(job_OraculImport, app.config.OraculCollector.Schedule, func(ctx context.Context) {
+			app.oraculCollector_Exec(ctx)
+		}).
+		Run(app.ctx)
+	app.Infra.Logger.Info("oracul-collector: daemon mode is stopped")
+}
+
+func (app *App) oraculCollector_Exec(ctx context.Context) bool {
+	app.Infra.Logger.Info("OraculAnalytics.Import: starts iteration...")
+
+	slugs, err := app.Domain.Currency.WatchlistSlugs(ctx, nil)
+	if err != nil {
+		app.Infra.Logger.Info("OraculAnalytics.Import: getting the watchlist error!", zap.Error(err))
+		return false
+	}
+
+	if _, err = app.Domain.Currency.SyncTokenAddress(ctx, slugs); err != nil {
+		app.Infra.Logger.Info("OraculAnalytics.Import: token addresses sync error!", zap.Error(err))
+		return false
+	}
+
+	if err = app.Domain.Currency.ImportOraculAnalytics(ctx, slugs); err != nil {
+		var importErr *domain.ImportError
+		if errors.As(err, &importErr) {
+			var item domain.ImportFailure
+			for _, item = range importErr.Failed {
+				app.Infra.Logger.Error("OraculAnalytics.Import: token import error", zap.String(log_key.Token, item.Key), zap.Error(item.Err))
+			}
+		}
+		app.Infra.Logger.Info("OraculAnalytics.Import: iteration completed with errors!", zap.Error(err))
+		return false
+	}
+	app.Infra.Logger.Info("OraculAnalytics.Import: iteration completed successfully!")
+	app.alert_Evaluate(ctx)
+	return true
+}
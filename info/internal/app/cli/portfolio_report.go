@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/portfolio"
+	"info/internal/pkg/apperror"
+)
+
+const (
+	flag_Source   = "source"
+	flag_Window   = "window"
+	flag_RiskFree = "risk-free"
+)
+
+// portfolioReport ...
+var portfolioReport = &cobra.Command{
+	Use:   "portfolio-report",
+	Short: "It is the portfolio-report command.",
+	Long:  `It is the portfolio-report command: prints the risk analytics of the portfolios: the allocation by coin and by platform, the max drawdown, the volatility, the Sharpe and Sortino ratios, the correlation-weighted risk of the positions and the positions whose whale concentration is falling.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.portfolioReport(cmd, args)
+	},
+}
+
+func init() {
+	portfolioReport.Flags().StringP(flag_Source, "s", "", "portfolio source ID; all portfolios if empty")
+	portfolioReport.Flags().String(flag_From, "", "start of the window, "+time.DateOnly+"; a year before the end if empty")
+	portfolioReport.Flags().String(flag_To, "", "end of the window (inclusive), "+time.DateOnly+"; today if empty")
+	portfolioReport.Flags().UintP(flag_Window, "w", portfolio.DefaultVolatilityWindowDays, "days of the rolling volatility")
+	portfolioReport.Flags().Float64(flag_RiskFree, 0, "annual risk-free rate in percents for the Sharpe and Sortino ratios")
+}
+
+func (app *App) portfolioReport(cmd *cobra.Command, args []string) {
+	params, err := portfolioReport_Params(cmd)
+	if err != nil {
+		app.Infra.Logger.Error("portfolio-report: parse flags error", zap.Error(err))
+		return
+	}
+	sourceID, _ := cmd.Flags().GetString(flag_Source)
+
+	var riskList portfolio.RiskList
+	if sourceID == "" {
+		riskList, err = app.Domain.Portfolio.RiskAll(app.ctx, params)
+	} else {
+		var item *portfolio.Risk
+		if item, err = app.Domain.Portfolio.Risk(app.ctx, sourceID, params); err == nil {
+			riskList = portfolio.RiskList{*item}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			fmt.Println("portfolio not found")
+			return
+		}
+		app.Infra.Logger.Error("portfolio-report: Portfolio.Risk error", zap.Error(err))
+		return
+	}
+
+	for i := range riskList {
+		portfolioReport_Print(&riskList[i])
+	}
+}
+
+func portfolioReport_Params(cmd *cobra.Command) (*portfolio.RiskParams, error) {
+	params := &portfolio.RiskParams{}
+	var err error
+
+	if params.WindowDays, err = cmd.Flags().GetUint(flag_Window); err != nil {
+		return nil, err
+	}
+	if params.RiskFreeRate, err = cmd.Flags().GetFloat64(flag_RiskFree); err != nil {
+		return nil, err
+	}
+
+	if from, _ := cmd.Flags().GetString(flag_From); from != "" {
+		if params.From, err = time.Parse(time.DateOnly, from); err != nil {
+			return nil, fmt.Errorf("--%s parse error: %w", flag_From, err)
+		}
+	}
+	if to, _ := cmd.Flags().GetString(flag_To); to != "" {
+		if params.To, err = time.Parse(time.DateOnly, to); err != nil {
+			return nil, fmt.Errorf("--%s parse error: %w", flag_To, err)
+		}
+		// окно включает весь последний день
+		params.To = params.To.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return params, nil
+}
+
+func portfolioReport_Print(item *portfolio.Risk) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "PORTFOLIO %s\n", item.SourceID)
+	fmt.Fprintln(w, "HOLDINGS\tINVESTED\tP&L\tP&L %\tFROM\tTO\tRETURN %\tVOLATILITY %\tSHARPE\tSORTINO\tMAX DRAWDOWN %\tCORR. VOLATILITY %\tDIVERSIFICATION\tWHALE FALLING")
+	fmt.Fprintf(w, "%.2f\t%.2f\t%.2f\t%.2f\t%s\t%s\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%d\n", item.Totals.Holdings, item.Totals.Invested, item.Totals.PlValue, item.Totals.PlPercent, item.From.Format(time.DateOnly), item.To.Format(time.DateOnly), item.Return, item.Volatility, item.Sharpe, item.Sortino, item.Drawdown.Percent, item.CorrelatedVolatility, item.DiversificationRatio, item.WhaleFallingNb)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SYMBOL\tPLATFORM\tHOLDINGS\tP&L %\tWEIGHT %\tVOLATILITY %\tRISK %\tPRICED\tWHALES")
+	var position portfolio.PositionRisk
+	for _, position = range item.Positions {
+		whales := "-"
+		if position.WhaleTrend != nil {
+			whales = fmt.Sprintf("%s %.2f%%", position.WhaleTrend.Direction, position.WhaleTrend.ValuePercent)
+		}
+		if position.IsWhaleFalling {
+			whales = "! " + whales
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%t\t%s\n", position.Symbol, position.Platform, position.Holdings, position.PlPercent, position.Weight, position.Volatility, position.RiskContribution, position.IsPriced, whales)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "PLATFORM\tHOLDINGS\tSHARE %")
+	var allocation portfolio.Allocation
+	for _, allocation = range item.AllocationByPlatform {
+		fmt.Fprintf(w, "%s\t%.2f\t%.2f\n", allocation.Name, allocation.Holdings, allocation.Percent)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
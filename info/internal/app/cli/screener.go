@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/screener"
+	"info/internal/pkg/apperror"
+)
+
+const (
+	flag_Preset        = "preset"
+	flag_Criteria      = "criteria"
+	flag_Normalization = "normalization"
+
+	format_Table = "table"
+)
+
+// screenerCmd ...
+var screenerCmd = &cobra.Command{
+	Use:   "screener",
+	Short: "It is the screener command.",
+	Long:  `It is the screener command: ranks the observed currencies by the weighted sum of the CMC and Oracul metrics normalized across the currencies (z-score or percentile) and prints the ranks with the contribution of every criterion. The criteria are taken from the saved preset of --preset or from --criteria. It reads the database only.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.screener(cmd, args)
+	},
+}
+
+func init() {
+	screenerCmd.Flags().StringP(flag_Preset, "p", "", "ID or name of the saved preset; --criteria, --normalization and --limit are ignored if it is set")
+	screenerCmd.Flags().StringSlice(flag_Criteria, nil, "criteria as metric:weight[:days], comma separated, e.g. whale-share-change:2:7,worm-index:-1")
+	screenerCmd.Flags().String(flag_Normalization, screener.Normalization_ZScore, "normalization of the metrics: zscore or percentile")
+	screenerCmd.Flags().UintP(flag_Limit, "l", screener.DefaultLimit, "number of the top currencies")
+	screenerCmd.Flags().String(flag_Format, format_Table, "output format: table or json")
+}
+
+func (app *App) screener(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString(flag_Format)
+	if format != format_Table && format != format_JSON {
+		app.Infra.Logger.Error("screener: parse flags error", zap.Error(fmt.Errorf("--%s must be %s or %s", flag_Format, format_Table, format_JSON)))
+		return
+	}
+
+	var res *screener.Result
+	var err error
+	if preset, _ := cmd.Flags().GetString(flag_Preset); preset != "" {
+		res, err = app.Domain.Screener.RunPreset(app.ctx, preset)
+	} else {
+		var params *screener.Params
+		if params, err = screener_Params(cmd); err != nil {
+			app.Infra.Logger.Error("screener: parse flags error", zap.Error(err))
+			return
+		}
+		res, err = app.Domain.Screener.Run(app.ctx, params)
+	}
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			fmt.Println("preset not found")
+			return
+		}
+		app.Infra.Logger.Error("screener: Screener.Run error", zap.Error(err))
+		return
+	}
+
+	if format == format_JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(res); err != nil {
+			app.Infra.Logger.Error("screener: write output error", zap.Error(err))
+		}
+		return
+	}
+	screener_Print(res)
+}
+
+func screener_Params(cmd *cobra.Command) (*screener.Params, error) {
+	params := &screener.Params{}
+	var err error
+
+	if params.Normalization, err = cmd.Flags().GetString(flag_Normalization); err != nil {
+		return nil, err
+	}
+	if params.Limit, err = cmd.Flags().GetUint(flag_Limit); err != nil {
+		return nil, err
+	}
+	criteria, err := cmd.Flags().GetStringSlice(flag_Criteria)
+	if err != nil {
+		return nil, err
+	}
+	if len(criteria) == 0 {
+		return nil, fmt.Errorf("--%s or --%s is required", flag_Preset, flag_Criteria)
+	}
+
+	params.Criteria = make(screener.CriterionList, 0, len(criteria))
+	for _, s := range criteria {
+		parts := strings.Split(s, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("--%s: %q must be metric:weight[:days]", flag_Criteria, s)
+		}
+		item := screener.Criterion{
+			Metric: parts[0],
+		}
+		if item.Weight, err = strconv.ParseFloat(parts[1], 64); err != nil {
+			return nil, fmt.Errorf("--%s: %q weight parse error: %w", flag_Criteria, s, err)
+		}
+		if len(parts) == 3 {
+			days, err := strconv.ParseUint(parts[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("--%s: %q days parse error: %w", flag_Criteria, s, err)
+			}
+			item.Days = uint(days)
+		}
+		params.Criteria = append(params.Criteria, item)
+	}
+	return params, nil
+}
+
+func screener_Print(res *screener.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "SCREENER %s: %d currencies\n", res.Normalization, res.CurrenciesNb)
+
+	header := []string{"#", "SYMBOL", "NAME", "SCORE"}
+	for _, criterion := range res.Criteria {
+		name := criterion.Metric
+		if criterion.Days > 0 {
+			name += fmt.Sprintf(" %dd", criterion.Days)
+		}
+		header = append(header, fmt.Sprintf("%s x%g", strings.ToUpper(name), criterion.Weight))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, item := range res.Ranks {
+		row := []string{strconv.FormatUint(uint64(item.Position), 10), item.Symbol, item.Name, fmt.Sprintf("%.2f", item.Score)}
+		for _, c := range item.Contributions {
+			value := "-"
+			if c.Value != nil {
+				value = strconv.FormatFloat(*c.Value, 'g', 6, 64)
+			}
+			row = append(row, fmt.Sprintf("%s (%+.2f)", value, c.Contribution))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
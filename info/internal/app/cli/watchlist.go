@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"info/internal/domain/currency"
+	"info/internal/domain/discovery"
+	"info/internal/pkg/apperror"
+)
+
+const (
+	flag_Action = "action"
+	flag_Slug   = "slug"
+	flag_Since  = "since"
+)
+
+// watchlist ...
+var watchlist = &cobra.Command{
+	Use:   "watchlist",
+	Short: "It is the watchlist command.",
+	Long:  `It is the watchlist command: manages the list of the observed currencies which are imported by the currency-collector. Besides the manual add and remove, the discovery promotes the coins matching the criteria from the config and demotes them after the grace period out of the criteria.`,
+}
+
+var watchlistAdd = &cobra.Command{
+	Use:   "add <slug> [slug...]",
+	Short: "Puts the currencies on the watchlist.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.watchlistAdd(cmd, args)
+	},
+}
+
+var watchlistRemove = &cobra.Command{
+	Use:   "remove <slug> [slug...]",
+	Short: "Takes the currencies off the watchlist; their collected data is kept.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.watchlistRemove(cmd, args)
+	},
+}
+
+var watchlistList = &cobra.Command{
+	Use:   "list",
+	Short: "Prints the observed currencies.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.watchlistList(cmd, args)
+	},
+}
+
+var watchlistDiscover = &cobra.Command{
+	Use:   "discover",
+	Short: "Promotes the top-ranked coins to the watchlist and demotes the ones out of the criteria, by the discovery config.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.watchlistDiscover(cmd, args)
+	},
+}
+
+var watchlistEvents = &cobra.Command{
+	Use:   "events",
+	Short: "Prints the promotions and the demotions made by the discovery.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		CliApp.watchlistEvents(cmd, args)
+	},
+}
+
+func init() {
+	watchlistEvents.Flags().StringP(flag_Action, "a", "", "action: promote or demote; all actions if empty")
+	watchlistEvents.Flags().StringP(flag_Slug, "s", "", "slug of the currency")
+	watchlistEvents.Flags().Duration(flag_Since, 0, "only the events for this time, e.g. 720h; all events if 0")
+	watchlistEvents.Flags().UintP(flag_Limit, "l", 100, "number of the last events")
+
+	watchlist.AddCommand(
+		watchlistAdd,
+		watchlistRemove,
+		watchlistList,
+		watchlistDiscover,
+		watchlistEvents,
+	)
+}
+
+func (app *App) watchlistAdd(cmd *cobra.Command, args []string) {
+	currencyList, err := app.Domain.Currency.WatchlistAdd(app.ctx, &args)
+	if err != nil {
+		app.Infra.Logger.Error("watchlist add: Currency.WatchlistAdd error", zap.Error(err))
+		return
+	}
+	app.watchlist_Print(currencyList)
+}
+
+func (app *App) watchlistRemove(cmd *cobra.Command, args []string) {
+	if err := app.Domain.Currency.WatchlistRemove(app.ctx, &args); err != nil {
+		app.Infra.Logger.Error("watchlist remove: Currency.WatchlistRemove error", zap.Error(err))
+		return
+	}
+	fmt.Printf("removed from the watchlist: %d\n", len(args))
+}
+
+func (app *App) watchlistList(cmd *cobra.Command, args []string) {
+	currencyList, err := app.Domain.Currency.Watchlist(app.ctx)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			app.Infra.Logger.Error("watchlist list: Currency.Watchlist error", zap.Error(err))
+			return
+		}
+		currencyList = &currency.CurrencyList{}
+	}
+	app.watchlist_Print(currencyList)
+}
+
+func (app *App) watchlistDiscover(cmd *cobra.Command, args []string) {
+	cfg := app.config.CurrencyCollector
+	if cfg.Discovery == nil {
+		app.Infra.Logger.Error("watchlist discover: discovery is not configured")
+		return
+	}
+
+	events, err := app.Domain.Discovery.Discover(app.ctx, cfg.Discovery)
+	if err != nil {
+		app.Infra.Logger.Error("watchlist discover: Discovery.Discover error", zap.Error(err))
+		return
+	}
+	app.watchlist_PrintEvents(events)
+}
+
+func (app *App) watchlistEvents(cmd *cobra.Command, args []string) {
+	filter := &discovery.EventFilter{}
+	filter.Action, _ = cmd.Flags().GetString(flag_Action)
+	filter.Slug, _ = cmd.Flags().GetString(flag_Slug)
+	filter.Limit, _ = cmd.Flags().GetUint(flag_Limit)
+	since, _ := cmd.Flags().GetDuration(flag_Since)
+	if since > 0 {
+		t := time.Now().UTC().Add(-since)
+		filter.Since = &t
+	}
+
+	if err := discovery.ActionValidate(filter.Action); err != nil {
+		app.Infra.Logger.Error("watchlist events: invalid action", zap.Error(err))
+		return
+	}
+
+	events, err := app.Domain.Discovery.Events(app.ctx, filter)
+	if err != nil {
+		app.Infra.Logger.Error("watchlist events: Discovery.Events error", zap.Error(err))
+		return
+	}
+	app.watchlist_PrintEvents(events)
+}
+
+func (app *App) watchlist_PrintEvents(events *discovery.EventList) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTION\tSLUG\tRANK\tMARKET CAP\tVOLUME 24H\tREASON")
+	var item discovery.Event
+	for _, item = range *events {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.0f\t%.0f\t%s\n", item.CreatedAt.Format(timeFormat4Output), item.Action, item.Slug, item.CmcRank, item.MarketCap, item.Volume24h, item.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		app.Infra.Logger.Error("watchlist: output error", zap.Error(err))
+	}
+}
+
+func (app *App) watchlist_Print(currencyList *currency.CurrencyList) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSYMBOL\tSLUG\tNAME\tCMC RANK")
+	var item currency.Currency
+	for _, item = range *currencyList {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\n", item.ID, item.Symbol, item.Slug, item.Name, item.CmcRank)
+	}
+	if err := w.Flush(); err != nil {
+		app.Infra.Logger.Error("watchlist: output error", zap.Error(err))
+	}
+}
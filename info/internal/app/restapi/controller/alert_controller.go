@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/alert"
+	"info/internal/domain/currency"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+	"strconv"
+	"time"
+)
+
+const (
+	pathParam_RuleID = "id"
+)
+
+type alertController struct {
+	logger   *zap.Logger
+	router   *routing.Router
+	service  *alert.Service
+	currency *currency.Service
+}
+
+// ruleRequest is the body of the rule creation and update; currency is the slug, the symbol or the ID, cooldown is the duration, e.g. 24h.
+type ruleRequest struct {
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Currency  string   `json:"currency"`
+	Threshold float64  `json:"threshold"`
+	Days      uint     `json:"days"`
+	Direction string   `json:"direction"`
+	Report    string   `json:"report"`
+	TopN      uint     `json:"top_n"`
+	Cooldown  string   `json:"cooldown"`
+	Notifiers []string `json:"notifiers"`
+	IsEnabled *bool    `json:"is_enabled"`
+}
+
+func NewAlertController(logger *zap.Logger, router *routing.Router, service *alert.Service, currency *currency.Service) *alertController {
+	return &alertController{
+		logger:   logger,
+		router:   router,
+		service:  service,
+		currency: currency,
+	}
+}
+
+// Rules returns the alert rules.
+func (c *alertController) Rules(rctx *routing.Context) (err error) {
+	const metricName = "alertController.Rules"
+	ctx := rctx.RequestCtx
+
+	l, err := c.service.GetRules(ctx)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.errInternal(ctx, metricName, "Failed to get the alert rules", err)
+		}
+		l = &alert.RuleList{}
+	}
+	return c.success(ctx, metricName, *l)
+}
+
+// Rule returns the alert rule.
+func (c *alertController) Rule(rctx *routing.Context) (err error) {
+	const metricName = "alertController.Rule"
+	ctx := rctx.RequestCtx
+
+	ID, err := c.parseRuleID(rctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	item, err := c.service.GetRule(ctx, ID)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to get the alert rule", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// CreateRule creates the alert rule from the body.
+func (c *alertController) CreateRule(rctx *routing.Context) (err error) {
+	const metricName = "alertController.CreateRule"
+	ctx := rctx.RequestCtx
+
+	entity, err := c.parseRule(ctx)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to get the currency of the alert rule", err)
+	}
+	item, err := c.service.CreateRule(ctx, entity)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to create the alert rule", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// UpdateRule replaces the alert rule with the body.
+func (c *alertController) UpdateRule(rctx *routing.Context) (err error) {
+	const metricName = "alertController.UpdateRule"
+	ctx := rctx.RequestCtx
+
+	ID, err := c.parseRuleID(rctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	entity, err := c.parseRule(ctx)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to get the currency of the alert rule", err)
+	}
+	entity.ID = ID
+	item, err := c.service.UpdateRule(ctx, entity)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to update the alert rule", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// DeleteRule deletes the alert rule with its fired alerts.
+func (c *alertController) DeleteRule(rctx *routing.Context) (err error) {
+	const metricName = "alertController.DeleteRule"
+	ctx := rctx.RequestCtx
+
+	ID, err := c.parseRuleID(rctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if err = c.service.DeleteRule(ctx, ID); err != nil {
+		return c.error(ctx, metricName, "Failed to delete the alert rule", err)
+	}
+	return c.success(ctx, metricName, ID)
+}
+
+// TestRule sends the test alert through the notifiers of the rule; the test alert is not stored.
+func (c *alertController) TestRule(rctx *routing.Context) (err error) {
+	const metricName = "alertController.TestRule"
+	ctx := rctx.RequestCtx
+
+	ID, err := c.parseRuleID(rctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	item, err := c.service.Test(ctx, ID)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to test the alert rule", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// Alerts returns the fired alerts, the newest first.
+// Query params: rule_id, currency (slug, symbol or ID), since (duration, e.g. 720h), limit.
+func (c *alertController) Alerts(rctx *routing.Context) (err error) {
+	const metricName = "alertController.Alerts"
+	ctx := rctx.RequestCtx
+
+	filter := &alert.AlertFilter{}
+	if filter.RuleID, err = fasthttp_tools.ParseQueryArgUint(ctx, "rule_id"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if filter.Limit, err = fasthttp_tools.ParseQueryArgUint(ctx, "limit"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	since, err := fasthttp_tools.ParseQueryArgDuration(ctx, "since")
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.badRequest(ctx, metricName, err)
+		}
+	} else {
+		t := time.Now().UTC().Add(-since)
+		filter.Since = &t
+	}
+
+	if key := string(ctx.QueryArgs().Peek("currency")); key != "" {
+		if filter.CurrencyID, err = c.currencyID(ctx, key); err != nil {
+			return c.error(ctx, metricName, "Failed to get the currency", err)
+		}
+	}
+
+	l, err := c.service.Alerts(ctx, filter)
+	if err != nil {
+		return c.errInternal(ctx, metricName, "Failed to get the alerts", err)
+	}
+	return c.success(ctx, metricName, *l)
+}
+
+func (c *alertController) parseRuleID(rctx *routing.Context) (uint, error) {
+	ID, err := strconv.ParseUint(rctx.Param(pathParam_RuleID), 10, 64)
+	if err != nil || ID == 0 {
+		return 0, fmt.Errorf("[%w] invalid rule id: %q", apperror.ErrBadRequest, rctx.Param(pathParam_RuleID))
+	}
+	return uint(ID), nil
+}
+
+func (c *alertController) parseRule(ctx *fasthttp.RequestCtx) (*alert.Rule, error) {
+	req := ruleRequest{}
+	if err := json.Unmarshal(ctx.PostBody(), &req); err != nil {
+		return nil, fmt.Errorf("[%w] parse body error: %w", apperror.ErrBadRequest, err)
+	}
+
+	entity := &alert.Rule{
+		Name:      req.Name,
+		Kind:      req.Kind,
+		Threshold: req.Threshold,
+		Days:      req.Days,
+		Direction: req.Direction,
+		Report:    req.Report,
+		TopN:      req.TopN,
+		Notifiers: req.Notifiers,
+		IsEnabled: req.IsEnabled == nil || *req.IsEnabled,
+	}
+	if req.Cooldown != "" {
+		cooldown, err := time.ParseDuration(req.Cooldown)
+		if err != nil {
+			return nil, fmt.Errorf("[%w] parse cooldown error: %w", apperror.ErrBadRequest, err)
+		}
+		entity.Cooldown = cooldown
+	}
+	if req.Currency != "" {
+		var err error
+		if entity.CurrencyID, err = c.currencyID(ctx, req.Currency); err != nil {
+			return nil, err
+		}
+	}
+	return entity, nil
+}
+
+// currencyID returns the ID of the currency by the key; the unknown currency is the bad request.
+func (c *alertController) currencyID(ctx *fasthttp.RequestCtx, key string) (uint, error) {
+	item, err := c.currency.GetByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return 0, fmt.Errorf("[%w] currency not found: %q", apperror.ErrBadRequest, key)
+		}
+		return 0, err
+	}
+	return item.ID, nil
+}
+
+func (c *alertController) error(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	switch {
+	case errors.Is(err, apperror.ErrBadRequest):
+		return c.badRequest(ctx, metricName, err)
+	case errors.Is(err, apperror.ErrNotFound):
+		res := fasthttp_tools.NewResponse_ErrNotFound("alert rule not found")
+		fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+		return nil
+	default:
+		return c.errInternal(ctx, metricName, errMsg, err)
+	}
+}
+
+func (c *alertController) success(ctx *fasthttp.RequestCtx, metricName string, data interface{}) error {
+	res := fasthttp_tools.NewResponse_Success(data)
+	if err := fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *alertController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *alertController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
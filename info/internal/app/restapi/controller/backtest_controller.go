@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/backtest"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+	"time"
+)
+
+type backtestController struct {
+	logger  *zap.Logger
+	router  *routing.Router
+	service *backtest.Service
+}
+
+// backtestRequest is the body of the backtest; from and to are the days (2006-01-02), to is inclusive.
+type backtestRequest struct {
+	Slugs      []string           `json:"slugs"`
+	IDs        []uint             `json:"ids"`
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	Capital    float64            `json:"capital"`
+	Stake      float64            `json:"stake"`
+	Strategies []backtestStrategy `json:"strategies"`
+}
+
+// backtestStrategy is backtest.Strategy in the body of the backtest
+type backtestStrategy struct {
+	Name              string  `json:"name"`
+	Cohort            string  `json:"cohort"`
+	Direction         string  `json:"direction"`
+	ChangePercent     float64 `json:"change_percent"`
+	LookbackDays      uint    `json:"lookback_days"`
+	HoldDays          uint    `json:"hold_days"`
+	StopLossPercent   float64 `json:"stop_loss_percent"`
+	TakeProfitPercent float64 `json:"take_profit_percent"`
+}
+
+func (e *backtestStrategy) Strategy() backtest.Strategy {
+	return backtest.Strategy{
+		Name:              e.Name,
+		Cohort:            e.Cohort,
+		Direction:         e.Direction,
+		ChangePercent:     e.ChangePercent,
+		LookbackDays:      e.LookbackDays,
+		HoldDays:          e.HoldDays,
+		StopLossPercent:   e.StopLossPercent,
+		TakeProfitPercent: e.TakeProfitPercent,
+	}
+}
+
+func NewBacktestController(logger *zap.Logger, router *routing.Router, service *backtest.Service) *backtestController {
+	return &backtestController{
+		logger:  logger,
+		router:  router,
+		service: service,
+	}
+}
+
+// Run replays the stored history with the strategies of the body and returns the trades, the equity curves and the summaries.
+func (c *backtestController) Run(rctx *routing.Context) (err error) {
+	const metricName = "backtestController.Run"
+	ctx := rctx.RequestCtx
+
+	params, err := c.parseParams(ctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	res, err := c.service.Run(ctx, params)
+	if err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) || errors.Is(err, apperror.ErrNotFound) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		return c.errInternal(ctx, metricName, "Failed to run the backtest", err)
+	}
+	return c.success(ctx, metricName, *res)
+}
+
+func (c *backtestController) parseParams(ctx *fasthttp.RequestCtx) (*backtest.Params, error) {
+	req := backtestRequest{}
+	if err := json.Unmarshal(ctx.PostBody(), &req); err != nil {
+		return nil, fmt.Errorf("[%w] parse body error: %w", apperror.ErrBadRequest, err)
+	}
+
+	params := &backtest.Params{
+		Slugs:      req.Slugs,
+		IDs:        req.IDs,
+		Capital:    req.Capital,
+		Stake:      req.Stake,
+		Strategies: make([]backtest.Strategy, 0, len(req.Strategies)),
+	}
+	for i := range req.Strategies {
+		params.Strategies = append(params.Strategies, req.Strategies[i].Strategy())
+	}
+	var err error
+	if req.From != "" {
+		if params.From, err = time.Parse(time.DateOnly, req.From); err != nil {
+			return nil, fmt.Errorf("[%w] parse from error: %w", apperror.ErrBadRequest, err)
+		}
+	}
+	if req.To != "" {
+		if params.To, err = time.Parse(time.DateOnly, req.To); err != nil {
+			return nil, fmt.Errorf("[%w] parse to error: %w", apperror.ErrBadRequest, err)
+		}
+		// окно включает весь последний день
+		params.To = params.To.Add(24*time.Hour - time.Nanosecond)
+	}
+	return params, nil
+}
+
+func (c *backtestController) success(ctx *fasthttp.RequestCtx, metricName string, data interface{}) error {
+	res := fasthttp_tools.NewResponse_Success(data)
+	if err := fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *backtestController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *backtestController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"errors"
 	routing "github.com/qiangxue/fasthttp-routing"
 	"github.com/valyala/fasthttp"
@@ -29,73 +30,148 @@ func NewCmcController(logger *zap.Logger, router *routing.Router, service *curre
 	}
 }
 
+// Report_BiggestFall returns the currencies with the biggest latest fall of the whales holdings.
+// Query params:
+//   - period (days to look back; default 61), break (the max break in the trend in days; default 5), magnitude (the min change in percents);
+//   - min_cap (the min market cap), min_rank and max_rank (CMC rank), from and to (RFC3339 or YYYY-MM-DD; the data window), with_not_observed (bool);
+//   - sort (any field of the report item, e.g. FallCapPercent; default FallValue), order (asc, desc; default desc), limit (default 10), offset.
+//
+// The items are currency.WhaleFall: the fall reports keep their field names.
 func (c *cmcController) Report_BiggestFall(rctx *routing.Context) (err error) {
-	const metricName = "transitTariffController.Report_BiggestFall"
+	return c.reportWhaleTrend(rctx, "cmcController.Report_BiggestFall", c.service.Report_BiggestFall, true)
+}
+
+// Report_LongestFall returns the currencies with the longest latest fall of the whales holdings.
+// Query params are the same as in Report_BiggestFall; sort is FallDuration by default.
+func (c *cmcController) Report_LongestFall(rctx *routing.Context) (err error) {
+	return c.reportWhaleTrend(rctx, "cmcController.Report_LongestFall", c.service.Report_LongestFall, true)
+}
+
+// Report_BiggestRise returns the currencies with the biggest latest rise of the whales holdings.
+// Query params are the same as in Report_BiggestFall, the sort fields are the ones of currency.WhaleTrend (default Value).
+func (c *cmcController) Report_BiggestRise(rctx *routing.Context) (err error) {
+	return c.reportWhaleTrend(rctx, "cmcController.Report_BiggestRise", c.service.Report_BiggestRise, false)
+}
+
+// Report_LongestRise returns the currencies with the longest latest rise of the whales holdings.
+// Query params are the same as in Report_BiggestRise; sort is Duration by default.
+func (c *cmcController) Report_LongestRise(rctx *routing.Context) (err error) {
+	return c.reportWhaleTrend(rctx, "cmcController.Report_LongestRise", c.service.Report_LongestRise, false)
+}
+
+func (c *cmcController) reportWhaleTrend(rctx *routing.Context, metricName string, report func(ctx context.Context, params *currency.WhaleTrendParams) (*currency.WhaleTrendList, uint, error), isFall bool) (err error) {
 	ctx := rctx.RequestCtx
 	var res *fasthttp_tools.Response
 
-	limit, err := fasthttp_tools.ParseQueryArgUint(ctx, "limit")
-	if err != nil {
-		if !errors.Is(err, apperror.ErrNotFound) {
-			errMsg := "Parse params error "
-			c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
-			res = fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
-			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
-			return nil
-		}
-		limit = defaultLimit4Report
+	params := &currency.WhaleTrendParams{
+		Sort:  string(ctx.QueryArgs().Peek("sort")),
+		Order: string(ctx.QueryArgs().Peek("order")),
+	}
+	if params.PeriodDays, err = fasthttp_tools.ParseQueryArgUint(ctx, "period"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.BreakDays, err = fasthttp_tools.ParseQueryArgUint(ctx, "break"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.MinMagnitude, err = fasthttp_tools.ParseQueryArgFloat(ctx, "magnitude"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.MinMarketCap, err = fasthttp_tools.ParseQueryArgFloat(ctx, "min_cap"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.MinRank, err = fasthttp_tools.ParseQueryArgUint(ctx, "min_rank"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.MaxRank, err = fasthttp_tools.ParseQueryArgUint(ctx, "max_rank"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.WithNotObserved, err = fasthttp_tools.ParseQueryArgBool(ctx, "with_not_observed"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.From, params.To, err = parseTimeRange(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.Limit, params.Offset, err = parsePaging(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultLimit4Report
 	}
 
-	report, err := c.service.Report_BiggestFall(ctx, limit)
+	list, totalNb, err := report(ctx, params)
 	if err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) {
+			return c.badRequest(ctx, metricName, err)
+		}
 		if errors.Is(err, apperror.ErrNotFound) {
-			errMsg := "Data for Report_BiggestFall was not found"
+			errMsg := "Data for the report was not found"
 			c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
 			res = fasthttp_tools.NewResponse_ErrNotFound(errMsg)
 			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
 			return nil
 		}
-		errMsg := "Failed to get Report_BiggestFall"
+		errMsg := "Failed to get the report"
 		c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
 		res = fasthttp_tools.NewResponse_ErrInternal()
 		fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
 		return nil
 	}
 
-	res = fasthttp_tools.NewResponse_Success(*report)
-	if err = fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *report); err != nil {
+	var data interface{} = *list
+	if isFall {
+		data = *currency.WhaleTrendList2WhaleFallList(list)
+	}
+	res = fasthttp_tools.NewResponse_SuccessWithPagination(data, params.Limit, params.Offset, totalNb)
+	if err = fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
 		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
 	}
 	return nil
 }
 
-func (c *cmcController) Report_LongestFall(rctx *routing.Context) (err error) {
-	const metricName = "transitTariffController.Report_BiggestFall"
+// Report_WhaleMomentum returns the changes of the whales holdings of the observed currencies over the lookback windows.
+// Query params: windows (comma separated days; default 7,14,30,60), sort (the window in days to sort by; default the first window), lag (days; default 2), limit.
+func (c *cmcController) Report_WhaleMomentum(rctx *routing.Context) (err error) {
+	const metricName = "cmcController.Report_WhaleMomentum"
 	ctx := rctx.RequestCtx
 	var res *fasthttp_tools.Response
 
+	params := &currency.WhaleMomentumParams{
+		Limit: defaultLimit4Report,
+	}
+	if params.Windows, err = fasthttp_tools.ParseQueryArgUints(ctx, "windows"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.SortWindow, err = fasthttp_tools.ParseQueryArgUint(ctx, "sort"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	lagDays, err := fasthttp_tools.ParseQueryArgUint(ctx, "lag")
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if err == nil {
+		params.LagDays = &lagDays
+	}
 	limit, err := fasthttp_tools.ParseQueryArgUint(ctx, "limit")
-	if err != nil {
-		if !errors.Is(err, apperror.ErrNotFound) {
-			errMsg := "Parse params error "
-			c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
-			res = fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
-			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
-			return nil
-		}
-		limit = defaultLimit4Report
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if err == nil {
+		params.Limit = limit
 	}
 
-	report, err := c.service.Report_LongestFall(ctx, limit)
+	report, err := c.service.Report_WhaleMomentum(ctx, params)
 	if err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) {
+			return c.badRequest(ctx, metricName, err)
+		}
 		if errors.Is(err, apperror.ErrNotFound) {
-			errMsg := "Data for Report_BiggestFall was not found"
+			errMsg := "Data for Report_WhaleMomentum was not found"
 			c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
 			res = fasthttp_tools.NewResponse_ErrNotFound(errMsg)
 			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
 			return nil
 		}
-		errMsg := "Failed to get Report_BiggestFall"
+		errMsg := "Failed to get Report_WhaleMomentum"
 		c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
 		res = fasthttp_tools.NewResponse_ErrInternal()
 		fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
@@ -108,3 +184,11 @@ func (c *cmcController) Report_LongestFall(rctx *routing.Context) (err error) {
 	}
 	return nil
 }
+
+func (c *cmcController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Parse params error "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"errors"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/concentration"
+	"info/internal/domain/currency"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+)
+
+const (
+	pathParam_Currency = "currency"
+)
+
+type currencyController struct {
+	logger        *zap.Logger
+	router        *routing.Router
+	service       *currency.Service
+	priceAndCap   *price_and_cap.Service
+	concentration *concentration.Service
+}
+
+func NewCurrencyController(logger *zap.Logger, router *routing.Router, service *currency.Service, priceAndCap *price_and_cap.Service, concentration *concentration.Service) *currencyController {
+	return &currencyController{
+		logger:        logger,
+		router:        router,
+		service:       service,
+		priceAndCap:   priceAndCap,
+		concentration: concentration,
+	}
+}
+
+// Detail returns the currency (by ID or slug) with its token addresses, the latest price and cap, the latest concentration,
+// the latest Oracul analytics, speedometers and holder stats and its positions in the whale fall and rise reports.
+func (c *currencyController) Detail(rctx *routing.Context) (err error) {
+	const metricName = "currencyController.Detail"
+	ctx := rctx.RequestCtx
+
+	detail, err := c.service.Detail(ctx, rctx.Param(pathParam_Currency))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			res := fasthttp_tools.NewResponse_ErrNotFound("currency not found")
+			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+			return nil
+		}
+		return c.errInternal(ctx, metricName, "Failed to get the currency detail", err)
+	}
+
+	res := fasthttp_tools.NewResponse_Success(*detail)
+	if err = fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+// Prices returns the price candles of the currency (by ID or slug) aggregated by the interval.
+// Query params: interval (1h, 1d, 1w; default 1d), from and to (RFC3339 or YYYY-MM-DD), limit (of the candles, max 5000), offset.
+func (c *currencyController) Prices(rctx *routing.Context) (err error) {
+	const metricName = "currencyController.Prices"
+	ctx := rctx.RequestCtx
+
+	item, ok := c.getCurrency(rctx, metricName)
+	if !ok {
+		return nil
+	}
+
+	params := &price_and_cap.CandleParams{
+		CurrencyID: item.ID,
+		Interval:   string(ctx.QueryArgs().Peek("interval")),
+	}
+	if params.Interval == "" {
+		params.Interval = price_and_cap.Interval_Day
+	}
+	if params.From, params.To, err = parseTimeRange(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.Limit, params.Offset, err = parsePaging(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	candles, totalNb, err := c.priceAndCap.Candles(ctx, params)
+	if err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		return c.errInternal(ctx, metricName, "Failed to get the prices", err)
+	}
+
+	res := fasthttp_tools.NewResponse_SuccessWithPagination(*candles, params.Limit, params.Offset, totalNb)
+	if err = fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+// Concentration returns the holder concentration of the currency (by ID or slug) with the normalized cohort shares and the deltas to the previous point.
+// Query params: from and to (RFC3339 or YYYY-MM-DD; the last 90 days by default), resample (day, week, month; default day).
+func (c *currencyController) Concentration(rctx *routing.Context) (err error) {
+	const metricName = "currencyController.Concentration"
+	ctx := rctx.RequestCtx
+
+	item, ok := c.getCurrency(rctx, metricName)
+	if !ok {
+		return nil
+	}
+
+	params := &concentration.HistoryParams{
+		CurrencyID: item.ID,
+		Resample:   string(ctx.QueryArgs().Peek("resample")),
+	}
+	if params.From, params.To, err = parseTimeRange(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	history, err := c.concentration.History(ctx, params)
+	if err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		return c.errInternal(ctx, metricName, "Failed to get the concentration", err)
+	}
+
+	res := fasthttp_tools.NewResponse_Success(*history)
+	if err = fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+// getCurrency returns the currency from the path param; it writes the error response if the currency is not found.
+func (c *currencyController) getCurrency(rctx *routing.Context, metricName string) (*currency.Currency, bool) {
+	ctx := rctx.RequestCtx
+	item, err := c.service.GetByKey(ctx, rctx.Param(pathParam_Currency))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			res := fasthttp_tools.NewResponse_ErrNotFound("currency not found")
+			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+			return nil, false
+		}
+		c.errInternal(ctx, metricName, "Failed to get the currency", err)
+		return nil, false
+	}
+	return item, true
+}
+
+func (c *currencyController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *currencyController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/currency"
+	"info/internal/domain/data_audit"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+	"strconv"
+	"time"
+)
+
+const (
+	pathParam_IssueID = "id"
+)
+
+type dataAuditController struct {
+	logger   *zap.Logger
+	router   *routing.Router
+	service  *data_audit.Service
+	currency *currency.Service
+}
+
+// dataAuditRequest is the body of the audit; stale_after is the duration, e.g. 48h.
+type dataAuditRequest struct {
+	Series       []string `json:"series"`
+	Days         uint     `json:"days"`
+	StaleAfter   string   `json:"stale_after"`
+	MaxPriceJump float64  `json:"max_price_jump"`
+	MaxShareJump float64  `json:"max_share_jump"`
+	Quarantine   bool     `json:"quarantine"`
+}
+
+func NewDataAuditController(logger *zap.Logger, router *routing.Router, service *data_audit.Service, currency *currency.Service) *dataAuditController {
+	return &dataAuditController{
+		logger:   logger,
+		router:   router,
+		service:  service,
+		currency: currency,
+	}
+}
+
+// Audit runs the audit with the params of the body and returns the run with its issues.
+func (c *dataAuditController) Audit(rctx *routing.Context) (err error) {
+	const metricName = "dataAuditController.Audit"
+	ctx := rctx.RequestCtx
+
+	req := dataAuditRequest{}
+	if len(ctx.PostBody()) > 0 {
+		if err = json.Unmarshal(ctx.PostBody(), &req); err != nil {
+			return c.badRequest(ctx, metricName, fmt.Errorf("[%w] parse body error: %w", apperror.ErrBadRequest, err))
+		}
+	}
+	params := &data_audit.Params{
+		Series:       req.Series,
+		Days:         req.Days,
+		MaxPriceJump: req.MaxPriceJump,
+		MaxShareJump: req.MaxShareJump,
+		Quarantine:   req.Quarantine,
+	}
+	if req.StaleAfter != "" {
+		if params.StaleAfter, err = time.ParseDuration(req.StaleAfter); err != nil {
+			return c.badRequest(ctx, metricName, fmt.Errorf("[%w] parse stale_after error: %w", apperror.ErrBadRequest, err))
+		}
+	}
+
+	res, err := c.service.Audit(ctx, params)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to run the data audit", err)
+	}
+	return c.success(ctx, metricName, *res)
+}
+
+// Runs returns the last audit runs, the newest first. Query params: limit.
+func (c *dataAuditController) Runs(rctx *routing.Context) (err error) {
+	const metricName = "dataAuditController.Runs"
+	ctx := rctx.RequestCtx
+
+	limit, err := fasthttp_tools.ParseQueryArgUint(ctx, "limit")
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	l, err := c.service.Runs(ctx, limit)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.errInternal(ctx, metricName, "Failed to get the data audit runs", err)
+		}
+		l = &data_audit.RunList{}
+	}
+	return c.success(ctx, metricName, *l)
+}
+
+// Issues returns the issues of the audit run, the last one by default.
+// Query params: run_id, series, kind, currency (slug or ID), limit.
+func (c *dataAuditController) Issues(rctx *routing.Context) (err error) {
+	const metricName = "dataAuditController.Issues"
+	ctx := rctx.RequestCtx
+
+	filter := &data_audit.IssueFilter{
+		Series: string(ctx.QueryArgs().Peek("series")),
+		Kind:   string(ctx.QueryArgs().Peek("kind")),
+	}
+	if filter.RunID, err = fasthttp_tools.ParseQueryArgUint(ctx, "run_id"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if filter.Limit, err = fasthttp_tools.ParseQueryArgUint(ctx, "limit"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if key := string(ctx.QueryArgs().Peek("currency")); key != "" {
+		item, err := c.currency.GetByKey(ctx, key)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return c.badRequest(ctx, metricName, fmt.Errorf("[%w] currency not found: %q", apperror.ErrBadRequest, key))
+			}
+			return c.errInternal(ctx, metricName, "Failed to get the currency", err)
+		}
+		filter.CurrencyID = item.ID
+	}
+
+	l, err := c.service.Issues(ctx, filter)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return c.success(ctx, metricName, data_audit.IssueList{})
+		}
+		return c.error(ctx, metricName, "Failed to get the data audit issues", err)
+	}
+	return c.success(ctx, metricName, *l)
+}
+
+// Quarantine returns the quarantined points, the newest first.
+func (c *dataAuditController) Quarantine(rctx *routing.Context) (err error) {
+	const metricName = "dataAuditController.Quarantine"
+	ctx := rctx.RequestCtx
+
+	l, err := c.service.Quarantine(ctx)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.errInternal(ctx, metricName, "Failed to get the quarantined points", err)
+		}
+		l = &data_audit.QuarantineList{}
+	}
+	return c.success(ctx, metricName, *l)
+}
+
+// QuarantineIssue quarantines the point of the issue.
+func (c *dataAuditController) QuarantineIssue(rctx *routing.Context) (err error) {
+	const metricName = "dataAuditController.QuarantineIssue"
+	ctx := rctx.RequestCtx
+
+	ID, err := c.parseIssueID(rctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	item, err := c.service.QuarantineIssue(ctx, ID)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to quarantine the point of the issue", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// ReleaseIssue returns the point of the issue from the quarantine.
+func (c *dataAuditController) ReleaseIssue(rctx *routing.Context) (err error) {
+	const metricName = "dataAuditController.ReleaseIssue"
+	ctx := rctx.RequestCtx
+
+	ID, err := c.parseIssueID(rctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if err = c.service.ReleaseIssue(ctx, ID); err != nil {
+		return c.error(ctx, metricName, "Failed to release the point of the issue", err)
+	}
+	return c.success(ctx, metricName, ID)
+}
+
+func (c *dataAuditController) parseIssueID(rctx *routing.Context) (uint, error) {
+	ID, err := strconv.ParseUint(rctx.Param(pathParam_IssueID), 10, 64)
+	if err != nil || ID == 0 {
+		return 0, fmt.Errorf("[%w] invalid issue id: %q", apperror.ErrBadRequest, rctx.Param(pathParam_IssueID))
+	}
+	return uint(ID), nil
+}
+
+func (c *dataAuditController) error(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	switch {
+	case errors.Is(err, apperror.ErrBadRequest):
+		return c.badRequest(ctx, metricName, err)
+	case errors.Is(err, apperror.ErrNotFound):
+		res := fasthttp_tools.NewResponse_ErrNotFound("not found")
+		fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+		return nil
+	default:
+		return c.errInternal(ctx, metricName, errMsg, err)
+	}
+}
+
+func (c *dataAuditController) success(ctx *fasthttp.RequestCtx, metricName string, data interface{}) error {
+	res := fasthttp_tools.NewResponse_Success(data)
+	if err := fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *dataAuditController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *dataAuditController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/export"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+	"strings"
+	"time"
+)
+
+const (
+	pathParam_Dataset = "dataset"
+)
+
+type exportController struct {
+	logger  *zap.Logger
+	router  *routing.Router
+	service *export.Service
+	timeout time.Duration
+}
+
+// NewExportController creates the controller; timeout limits the stream of the file, it is the write timeout of the server.
+func NewExportController(logger *zap.Logger, router *routing.Router, service *export.Service, timeout time.Duration) *exportController {
+	return &exportController{
+		logger:  logger,
+		router:  router,
+		service: service,
+		timeout: timeout,
+	}
+}
+
+// Export streams the rows of the dataset as the file.
+// Query params: format (csv, ndjson or parquet; csv by default), slugs and ids (comma separated; all the currencies if both are empty), from, to.
+// The params are checked before the stream: the error of the stream itself can only be logged and breaks the file.
+func (c *exportController) Export(rctx *routing.Context) (err error) {
+	const metricName = "exportController.Export"
+	ctx := rctx.RequestCtx
+
+	params := &export.Params{
+		Dataset: rctx.Param(pathParam_Dataset),
+		Format:  string(ctx.QueryArgs().Peek("format")),
+	}
+	if arg := ctx.QueryArgs().Peek("slugs"); len(arg) > 0 {
+		var slug string
+		for _, slug = range strings.Split(string(arg), ",") {
+			if slug = strings.TrimSpace(slug); slug != "" {
+				params.Slugs = append(params.Slugs, slug)
+			}
+		}
+	}
+	if params.IDs, err = fasthttp_tools.ParseQueryArgUints(ctx, "ids"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.From, params.To, err = parseTimeRange(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	filter, err := c.service.Prepare(ctx, params)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrBadRequest):
+			return c.badRequest(ctx, metricName, err)
+		case errors.Is(err, apperror.ErrNotFound):
+			res := fasthttp_tools.NewResponse_ErrNotFound("currencies not found")
+			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+			return nil
+		default:
+			return c.errInternal(ctx, metricName, "Failed to prepare the export", err)
+		}
+	}
+
+	ctx.SetContentType(filter.ContentType())
+	ctx.Response.Header.Set("Content-Disposition", `attachment; filename="`+filter.FileName()+`"`)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		// поток пишется после выхода из обработчика: контекст запроса уже не используем, запрос ограничен таймаутом записи сервера,
+		// после которого соединение всё равно закрывается; ошибка записи (клиент отключился) прерывает запрос через cancel
+		var streamCtx context.Context
+		var cancel context.CancelFunc
+		if c.timeout > 0 {
+			streamCtx, cancel = context.WithTimeout(context.Background(), c.timeout)
+		} else {
+			streamCtx, cancel = context.WithCancel(context.Background())
+		}
+		defer cancel()
+
+		rowsNb, err := c.service.Export(streamCtx, filter, w)
+		if err != nil {
+			c.logger.Error("Failed to export", zap.String(log_key.Func, metricName), zap.String(log_key.Dataset, filter.Dataset), zap.Uint(log_key.Rows, rowsNb), zap.Error(err))
+		}
+	})
+	return nil
+}
+
+func (c *exportController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *exportController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
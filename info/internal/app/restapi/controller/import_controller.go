@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"errors"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/import_run"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+)
+
+const (
+	defaultLimit4ImportRuns = 10
+)
+
+type importController struct {
+	logger  *zap.Logger
+	router  *routing.Router
+	service *import_run.Service
+}
+
+func NewImportController(logger *zap.Logger, router *routing.Router, service *import_run.Service) *importController {
+	return &importController{
+		logger:  logger,
+		router:  router,
+		service: service,
+	}
+}
+
+// Status returns the last import runs and the state of the imported items.
+// Query params: kind (currency, portfolio, oracul), limit (of the runs), stale_after (duration, e.g. 48h), problems (only stale and failing items).
+func (c *importController) Status(rctx *routing.Context) (err error) {
+	const metricName = "importController.Status"
+	ctx := rctx.RequestCtx
+	var res *fasthttp_tools.Response
+
+	kind := string(ctx.QueryArgs().Peek("kind"))
+	if err = import_run.KindValidate(kind); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	limit, err := fasthttp_tools.ParseQueryArgUint(ctx, "limit")
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		limit = defaultLimit4ImportRuns
+	}
+
+	staleAfter, err := fasthttp_tools.ParseQueryArgDuration(ctx, "stale_after")
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		staleAfter = import_run.DefaultStaleAfter
+	}
+
+	isOnlyProblems, err := fasthttp_tools.ParseQueryArgBool(ctx, "problems")
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		isOnlyProblems = false
+	}
+
+	status, err := c.service.Status(ctx, kind, limit, staleAfter)
+	if err != nil {
+		errMsg := "Failed to get import status"
+		c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+		res = fasthttp_tools.NewResponse_ErrInternal()
+		fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+		return nil
+	}
+	if isOnlyProblems {
+		status.Items = status.Items.Problems()
+	}
+
+	res = fasthttp_tools.NewResponse_Success(*status)
+	if err = fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *importController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Parse params error "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
@@ -0,0 +1,289 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/currency"
+	"info/internal/domain/oracul"
+	"info/internal/domain/oracul_analytics"
+	"info/internal/domain/oracul_daily_balance_stats"
+	"info/internal/domain/oracul_holder_stats"
+	"info/internal/domain/oracul_speedometers"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+	"strings"
+)
+
+type oraculController struct {
+	logger                  *zap.Logger
+	router                  *routing.Router
+	currency                *currency.Service
+	oraculAnalytics         *oracul_analytics.Service
+	oraculSpeedometers      *oracul_speedometers.Service
+	oraculHolderStats       *oracul_holder_stats.Service
+	oraculDailyBalanceStats *oracul_daily_balance_stats.Service
+}
+
+func NewOraculController(logger *zap.Logger, router *routing.Router, currency *currency.Service, oraculAnalytics *oracul_analytics.Service, oraculSpeedometers *oracul_speedometers.Service, oraculHolderStats *oracul_holder_stats.Service, oraculDailyBalanceStats *oracul_daily_balance_stats.Service) *oraculController {
+	return &oraculController{
+		logger:                  logger,
+		router:                  router,
+		currency:                currency,
+		oraculAnalytics:         oraculAnalytics,
+		oraculSpeedometers:      oraculSpeedometers,
+		oraculHolderStats:       oraculHolderStats,
+		oraculDailyBalanceStats: oraculDailyBalanceStats,
+	}
+}
+
+// Latest returns the latest data of every Oracul dataset of the currency (by ID or slug).
+func (c *oraculController) Latest(rctx *routing.Context) (err error) {
+	const metricName = "oraculController.Latest"
+	ctx := rctx.RequestCtx
+
+	item, ok := c.getCurrency(rctx, metricName)
+	if !ok {
+		return nil
+	}
+
+	latest, err := c.oraculAnalytics.Latest(ctx, item.ID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return c.notFound(ctx, "oracul data not found")
+		}
+		return c.errInternal(ctx, metricName, "Failed to get the latest oracul data", err)
+	}
+	return c.success(ctx, metricName, *latest)
+}
+
+// Analytics returns the analytics (whales concentration, worm index, growth fuel) of the currency (by ID or slug).
+// Query params: from and to (RFC3339 or YYYY-MM-DD; the last 90 days by default).
+func (c *oraculController) Analytics(rctx *routing.Context) (err error) {
+	return c.history(rctx, "oraculController.Analytics", func(ctx context.Context, params *oracul.HistoryParams) (interface{}, error) {
+		l, err := c.oraculAnalytics.History(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return *l, nil
+	})
+}
+
+// Speedometers returns the buy and sell rates and the volumes of the cohorts of the currency (by ID or slug).
+// Query params: from and to (RFC3339 or YYYY-MM-DD; the last 90 days by default).
+func (c *oraculController) Speedometers(rctx *routing.Context) (err error) {
+	return c.history(rctx, "oraculController.Speedometers", func(ctx context.Context, params *oracul.HistoryParams) (interface{}, error) {
+		l, err := c.oraculSpeedometers.History(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return *l, nil
+	})
+}
+
+// HolderStats returns the volumes and the holder counts of the cohorts of the currency (by ID or slug).
+// Query params: from and to (RFC3339 or YYYY-MM-DD; the last 90 days by default).
+func (c *oraculController) HolderStats(rctx *routing.Context) (err error) {
+	return c.history(rctx, "oraculController.HolderStats", func(ctx context.Context, params *oracul.HistoryParams) (interface{}, error) {
+		l, err := c.oraculHolderStats.History(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return *l, nil
+	})
+}
+
+// DailyBalanceStats returns the daily balances and the holder counts of the cohorts of the currency (by ID or slug).
+// Query params: from and to (RFC3339 or YYYY-MM-DD; the last 90 days by default).
+func (c *oraculController) DailyBalanceStats(rctx *routing.Context) (err error) {
+	return c.history(rctx, "oraculController.DailyBalanceStats", func(ctx context.Context, params *oracul.HistoryParams) (interface{}, error) {
+		l, err := c.oraculDailyBalanceStats.History(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return *l, nil
+	})
+}
+
+// AnalyticsLatest returns the latest analytics of the currencies.
+// Query params: currencies (IDs or slugs, comma separated; all the observed currencies by default).
+func (c *oraculController) AnalyticsLatest(rctx *routing.Context) (err error) {
+	return c.latestList(rctx, "oraculController.AnalyticsLatest", func(ctx context.Context, currencyIDs *[]uint) (interface{}, error) {
+		l, err := c.oraculAnalytics.MGetLast(ctx, currencyIDs)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return oracul_analytics.OraculAnalyticsList{}, nil
+			}
+			return nil, err
+		}
+		return *l, nil
+	})
+}
+
+// SpeedometersLatest returns the latest speedometers of the currencies.
+// Query params: currencies (IDs or slugs, comma separated; all the observed currencies by default).
+func (c *oraculController) SpeedometersLatest(rctx *routing.Context) (err error) {
+	return c.latestList(rctx, "oraculController.SpeedometersLatest", func(ctx context.Context, currencyIDs *[]uint) (interface{}, error) {
+		l, err := c.oraculSpeedometers.MGetLast(ctx, currencyIDs)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return oracul_speedometers.OraculSpeedometersList{}, nil
+			}
+			return nil, err
+		}
+		return *l, nil
+	})
+}
+
+// HolderStatsLatest returns the latest holder stats of the currencies.
+// Query params: currencies (IDs or slugs, comma separated; all the observed currencies by default).
+func (c *oraculController) HolderStatsLatest(rctx *routing.Context) (err error) {
+	return c.latestList(rctx, "oraculController.HolderStatsLatest", func(ctx context.Context, currencyIDs *[]uint) (interface{}, error) {
+		l, err := c.oraculHolderStats.MGetLast(ctx, currencyIDs)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return oracul_holder_stats.OraculHolderStatsList{}, nil
+			}
+			return nil, err
+		}
+		return *l, nil
+	})
+}
+
+// DailyBalanceStatsLatest returns the latest daily balance stats of the currencies.
+// Query params: currencies (IDs or slugs, comma separated; all the observed currencies by default).
+func (c *oraculController) DailyBalanceStatsLatest(rctx *routing.Context) (err error) {
+	return c.latestList(rctx, "oraculController.DailyBalanceStatsLatest", func(ctx context.Context, currencyIDs *[]uint) (interface{}, error) {
+		l, err := c.oraculDailyBalanceStats.MGetLast(ctx, currencyIDs)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return oracul_daily_balance_stats.OraculDailyBalanceStatsList{}, nil
+			}
+			return nil, err
+		}
+		return *l, nil
+	})
+}
+
+// history writes the dataset of the currency from the path param in the window from the query params.
+func (c *oraculController) history(rctx *routing.Context, metricName string, get func(ctx context.Context, params *oracul.HistoryParams) (interface{}, error)) error {
+	ctx := rctx.RequestCtx
+
+	item, ok := c.getCurrency(rctx, metricName)
+	if !ok {
+		return nil
+	}
+
+	from, to, err := parseTimeRange(ctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	data, err := get(ctx, &oracul.HistoryParams{CurrencyID: item.ID, From: from, To: to})
+	if err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		return c.errInternal(ctx, metricName, "Failed to get the oracul data", err)
+	}
+	return c.success(ctx, metricName, data)
+}
+
+// latestList writes the latest dataset of the currencies from the currencies query param.
+func (c *oraculController) latestList(rctx *routing.Context, metricName string, get func(ctx context.Context, currencyIDs *[]uint) (interface{}, error)) error {
+	ctx := rctx.RequestCtx
+
+	currencyIDs, err := c.parseCurrencyIDs(ctx)
+	if err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		return c.errInternal(ctx, metricName, "Failed to get the currencies", err)
+	}
+
+	data, err := get(ctx, currencyIDs)
+	if err != nil {
+		return c.errInternal(ctx, metricName, "Failed to get the latest oracul data", err)
+	}
+	return c.success(ctx, metricName, data)
+}
+
+// parseCurrencyIDs returns the IDs of the currencies of the currencies query param or of all the observed currencies if it is empty.
+func (c *oraculController) parseCurrencyIDs(ctx *fasthttp.RequestCtx) (*[]uint, error) {
+	arg := strings.TrimSpace(string(ctx.QueryArgs().Peek("currencies")))
+	if arg == "" {
+		l, err := c.currency.GetAll(ctx)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return &[]uint{}, nil
+			}
+			return nil, err
+		}
+		return l.IDs(), nil
+	}
+
+	res := make([]uint, 0)
+	var key string
+	for _, key = range strings.Split(arg, ",") {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		item, err := c.currency.GetByKey(ctx, key)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return nil, fmt.Errorf("[%w] currency not found: %q", apperror.ErrBadRequest, key)
+			}
+			return nil, err
+		}
+		res = append(res, item.ID)
+	}
+	return &res, nil
+}
+
+// getCurrency returns the currency from the path param; it writes the error response if the currency is not found.
+func (c *oraculController) getCurrency(rctx *routing.Context, metricName string) (*currency.Currency, bool) {
+	ctx := rctx.RequestCtx
+	item, err := c.currency.GetByKey(ctx, rctx.Param(pathParam_Currency))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			c.notFound(ctx, "currency not found")
+			return nil, false
+		}
+		c.errInternal(ctx, metricName, "Failed to get the currency", err)
+		return nil, false
+	}
+	return item, true
+}
+
+func (c *oraculController) notFound(ctx *fasthttp.RequestCtx, msg string) error {
+	res := fasthttp_tools.NewResponse_ErrNotFound(msg)
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+	return nil
+}
+
+func (c *oraculController) success(ctx *fasthttp.RequestCtx, metricName string, data interface{}) error {
+	res := fasthttp_tools.NewResponse_Success(data)
+	if err := fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *oraculController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *oraculController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"errors"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/portfolio"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+)
+
+const (
+	pathParam_SourceID = "sourceId"
+)
+
+type portfolioController struct {
+	logger  *zap.Logger
+	router  *routing.Router
+	service *portfolio.Service
+}
+
+func NewPortfolioController(logger *zap.Logger, router *routing.Router, service *portfolio.Service) *portfolioController {
+	return &portfolioController{
+		logger:  logger,
+		router:  router,
+		service: service,
+	}
+}
+
+// List returns all the portfolios with the positions and the totals.
+func (c *portfolioController) List(rctx *routing.Context) (err error) {
+	const metricName = "portfolioController.List"
+	ctx := rctx.RequestCtx
+
+	portfolioList, err := c.service.GetAll(ctx)
+	if err != nil {
+		return c.errInternal(ctx, metricName, "Failed to get the portfolios", err)
+	}
+	return c.success(ctx, metricName, *portfolioList)
+}
+
+// Get returns the portfolio by the source ID with the positions joined with the currencies and their current whale trends, and with the totals.
+func (c *portfolioController) Get(rctx *routing.Context) (err error) {
+	const metricName = "portfolioController.Get"
+	ctx := rctx.RequestCtx
+
+	item, err := c.service.Get(ctx, rctx.Param(pathParam_SourceID))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			res := fasthttp_tools.NewResponse_ErrNotFound("portfolio not found")
+			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+			return nil
+		}
+		return c.errInternal(ctx, metricName, "Failed to get the portfolio", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// History returns the value of the portfolio over time by its import snapshots.
+// Query params: from and to (RFC3339 or YYYY-MM-DD; the last 365 days by default).
+func (c *portfolioController) History(rctx *routing.Context) (err error) {
+	const metricName = "portfolioController.History"
+	ctx := rctx.RequestCtx
+
+	params := &portfolio.HistoryParams{}
+	if params.From, params.To, err = parseTimeRange(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	series, err := c.service.History(ctx, rctx.Param(pathParam_SourceID), params)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to get the portfolio history", err)
+	}
+	return c.success(ctx, metricName, series)
+}
+
+// PositionsHistory returns the P&L evolution of every position of the portfolio by its import snapshots.
+// Query params: from and to (RFC3339 or YYYY-MM-DD; the last 365 days by default).
+func (c *portfolioController) PositionsHistory(rctx *routing.Context) (err error) {
+	const metricName = "portfolioController.PositionsHistory"
+	ctx := rctx.RequestCtx
+
+	params := &portfolio.HistoryParams{}
+	if params.From, params.To, err = parseTimeRange(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	l, err := c.service.PositionsHistory(ctx, rctx.Param(pathParam_SourceID), params)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to get the positions history", err)
+	}
+	return c.success(ctx, metricName, l)
+}
+
+// Benchmark compares the value curve of the portfolio with holding BTC or ETH over the same period.
+// Query params: vs (btc, eth; default btc), from and to (RFC3339 or YYYY-MM-DD; the last 365 days by default).
+func (c *portfolioController) Benchmark(rctx *routing.Context) (err error) {
+	const metricName = "portfolioController.Benchmark"
+	ctx := rctx.RequestCtx
+
+	benchmark := string(ctx.QueryArgs().Peek("vs"))
+	if benchmark == "" {
+		benchmark = portfolio.Benchmark_BTC
+	}
+	params := &portfolio.HistoryParams{}
+	if params.From, params.To, err = parseTimeRange(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	item, err := c.service.Benchmark(ctx, rctx.Param(pathParam_SourceID), benchmark, params)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to compare the portfolio with the benchmark", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// Risk returns the risk analytics of the portfolio: the allocation by coin and by platform, the max drawdown, the rolling volatility,
+// the Sharpe and Sortino ratios, the correlation-weighted risk and the positions whose whale concentration is falling.
+// Query params: from and to (RFC3339 or YYYY-MM-DD; the last 365 days by default), window (days of the rolling volatility; default 30),
+// risk_free (annual risk-free rate in percents; default 0).
+func (c *portfolioController) Risk(rctx *routing.Context) (err error) {
+	const metricName = "portfolioController.Risk"
+	ctx := rctx.RequestCtx
+
+	params := &portfolio.RiskParams{}
+	if params.From, params.To, err = parseTimeRange(ctx); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.WindowDays, err = fasthttp_tools.ParseQueryArgUint(ctx, "window"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+	if params.RiskFreeRate, err = fasthttp_tools.ParseQueryArgFloat(ctx, "risk_free"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	item, err := c.service.Risk(ctx, rctx.Param(pathParam_SourceID), params)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			res := fasthttp_tools.NewResponse_ErrNotFound("portfolio not found")
+			fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+			return nil
+		}
+		return c.error(ctx, metricName, "Failed to get the portfolio risk", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// error writes the bad request, the not found or the internal error response by the kind of the err.
+func (c *portfolioController) error(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	switch {
+	case errors.Is(err, apperror.ErrBadRequest):
+		return c.badRequest(ctx, metricName, err)
+	case errors.Is(err, apperror.ErrNotFound):
+		res := fasthttp_tools.NewResponse_ErrNotFound("portfolio history not found")
+		fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+		return nil
+	default:
+		return c.errInternal(ctx, metricName, errMsg, err)
+	}
+}
+
+func (c *portfolioController) success(ctx *fasthttp.RequestCtx, metricName string, data interface{}) error {
+	res := fasthttp_tools.NewResponse_Success(data)
+	if err := fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *portfolioController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *portfolioController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"errors"
+	"github.com/valyala/fasthttp"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"time"
+)
+
+// parseTimeRange returns the from and the to query params; the absent ones are zero.
+func parseTimeRange(ctx *fasthttp.RequestCtx) (from time.Time, to time.Time, err error) {
+	if from, err = fasthttp_tools.ParseQueryArgTime(ctx, "from"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return from, to, err
+	}
+	if to, err = fasthttp_tools.ParseQueryArgTime(ctx, "to"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return from, to, err
+	}
+	return from, to, nil
+}
+
+// parsePaging returns the limit and the offset query params; the absent ones are zero.
+func parsePaging(ctx *fasthttp.RequestCtx) (limit uint, offset uint, err error) {
+	if limit, err = fasthttp_tools.ParseQueryArgUint(ctx, "limit"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return limit, offset, err
+	}
+	if offset, err = fasthttp_tools.ParseQueryArgUint(ctx, "offset"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return limit, offset, err
+	}
+	return limit, offset, nil
+}
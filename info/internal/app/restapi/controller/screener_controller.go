@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/screener"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+)
+
+const (
+	pathParam_Preset = "preset"
+)
+
+type screenerController struct {
+	logger  *zap.Logger
+	router  *routing.Router
+	service *screener.Service
+}
+
+// screenerRequest is the body of the screener run and of the preset creation and update; name is used by the presets only.
+type screenerRequest struct {
+	Name          string               `json:"name"`
+	Normalization string               `json:"normalization"`
+	Criteria      []screener.Criterion `json:"criteria"`
+	Limit         uint                 `json:"limit"`
+}
+
+func NewScreenerController(logger *zap.Logger, router *routing.Router, service *screener.Service) *screenerController {
+	return &screenerController{
+		logger:  logger,
+		router:  router,
+		service: service,
+	}
+}
+
+// Run ranks the observed currencies by the criteria of the body.
+func (c *screenerController) Run(rctx *routing.Context) (err error) {
+	const metricName = "screenerController.Run"
+	ctx := rctx.RequestCtx
+
+	req, err := c.parseRequest(ctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	res, err := c.service.Run(ctx, &screener.Params{
+		Normalization: req.Normalization,
+		Criteria:      req.Criteria,
+		Limit:         req.Limit,
+	})
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to run the screener", err)
+	}
+	return c.success(ctx, metricName, *res)
+}
+
+// Presets returns the screener presets.
+func (c *screenerController) Presets(rctx *routing.Context) (err error) {
+	const metricName = "screenerController.Presets"
+	ctx := rctx.RequestCtx
+
+	l, err := c.service.GetPresets(ctx)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.errInternal(ctx, metricName, "Failed to get the screener presets", err)
+		}
+		l = &screener.PresetList{}
+	}
+	return c.success(ctx, metricName, *l)
+}
+
+// Preset returns the screener preset by its ID or by its name.
+func (c *screenerController) Preset(rctx *routing.Context) (err error) {
+	const metricName = "screenerController.Preset"
+	ctx := rctx.RequestCtx
+
+	item, err := c.service.GetPresetByKey(ctx, rctx.Param(pathParam_Preset))
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to get the screener preset", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// CreatePreset creates the screener preset from the body.
+func (c *screenerController) CreatePreset(rctx *routing.Context) (err error) {
+	const metricName = "screenerController.CreatePreset"
+	ctx := rctx.RequestCtx
+
+	req, err := c.parseRequest(ctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	item, err := c.service.CreatePreset(ctx, req.preset())
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to create the screener preset", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// UpdatePreset replaces the screener preset found by its ID or by its name with the body.
+func (c *screenerController) UpdatePreset(rctx *routing.Context) (err error) {
+	const metricName = "screenerController.UpdatePreset"
+	ctx := rctx.RequestCtx
+
+	req, err := c.parseRequest(ctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+	old, err := c.service.GetPresetByKey(ctx, rctx.Param(pathParam_Preset))
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to get the screener preset", err)
+	}
+	entity := req.preset()
+	entity.ID = old.ID
+	item, err := c.service.UpdatePreset(ctx, entity)
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to update the screener preset", err)
+	}
+	return c.success(ctx, metricName, *item)
+}
+
+// DeletePreset deletes the screener preset found by its ID or by its name.
+func (c *screenerController) DeletePreset(rctx *routing.Context) (err error) {
+	const metricName = "screenerController.DeletePreset"
+	ctx := rctx.RequestCtx
+
+	item, err := c.service.GetPresetByKey(ctx, rctx.Param(pathParam_Preset))
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to get the screener preset", err)
+	}
+	if err = c.service.DeletePreset(ctx, item.ID); err != nil {
+		return c.error(ctx, metricName, "Failed to delete the screener preset", err)
+	}
+	return c.success(ctx, metricName, item.ID)
+}
+
+// RunPreset ranks the observed currencies by the criteria of the preset found by its ID or by its name.
+func (c *screenerController) RunPreset(rctx *routing.Context) (err error) {
+	const metricName = "screenerController.RunPreset"
+	ctx := rctx.RequestCtx
+
+	res, err := c.service.RunPreset(ctx, rctx.Param(pathParam_Preset))
+	if err != nil {
+		return c.error(ctx, metricName, "Failed to run the screener preset", err)
+	}
+	return c.success(ctx, metricName, *res)
+}
+
+func (c *screenerController) parseRequest(ctx *fasthttp.RequestCtx) (*screenerRequest, error) {
+	req := &screenerRequest{}
+	if err := json.Unmarshal(ctx.PostBody(), req); err != nil {
+		return nil, fmt.Errorf("[%w] parse body error: %w", apperror.ErrBadRequest, err)
+	}
+	return req, nil
+}
+
+func (r *screenerRequest) preset() *screener.Preset {
+	return &screener.Preset{
+		Name:          r.Name,
+		Normalization: r.Normalization,
+		Criteria:      r.Criteria,
+		Limit:         r.Limit,
+	}
+}
+
+func (c *screenerController) error(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	switch {
+	case errors.Is(err, apperror.ErrBadRequest), errors.Is(err, apperror.ErrAlreadyExists):
+		return c.badRequest(ctx, metricName, err)
+	case errors.Is(err, apperror.ErrNotFound):
+		res := fasthttp_tools.NewResponse_ErrNotFound("screener preset not found")
+		fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusNotFound, *res)
+		return nil
+	default:
+		return c.errInternal(ctx, metricName, errMsg, err)
+	}
+}
+
+func (c *screenerController) success(ctx *fasthttp.RequestCtx, metricName string, data interface{}) error {
+	res := fasthttp_tools.NewResponse_Success(data)
+	if err := fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *screenerController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *screenerController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
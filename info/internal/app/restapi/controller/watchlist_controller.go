@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	routing "github.com/qiangxue/fasthttp-routing"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"info/internal/domain/currency"
+	"info/internal/domain/discovery"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/fasthttp_tools"
+	"info/internal/pkg/log_key"
+	"strings"
+	"time"
+)
+
+type watchlistController struct {
+	logger    *zap.Logger
+	router    *routing.Router
+	service   *currency.Service
+	discovery *discovery.Service
+}
+
+type watchlistRequest struct {
+	Slugs []string `json:"slugs"`
+}
+
+func NewWatchlistController(logger *zap.Logger, router *routing.Router, service *currency.Service, discovery *discovery.Service) *watchlistController {
+	return &watchlistController{
+		logger:    logger,
+		router:    router,
+		service:   service,
+		discovery: discovery,
+	}
+}
+
+// List returns the observed currencies.
+func (c *watchlistController) List(rctx *routing.Context) (err error) {
+	const metricName = "watchlistController.List"
+	ctx := rctx.RequestCtx
+
+	currencyList, err := c.service.Watchlist(ctx)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.errInternal(ctx, metricName, "Failed to get the watchlist", err)
+		}
+		currencyList = &currency.CurrencyList{}
+	}
+	return c.success(ctx, metricName, *currencyList)
+}
+
+// Add puts the currencies on the watchlist.
+// Slugs are taken from the body ({"slugs": ["bitcoin", "ethereum"]}) or from the slugs query param (comma separated).
+func (c *watchlistController) Add(rctx *routing.Context) (err error) {
+	const metricName = "watchlistController.Add"
+	ctx := rctx.RequestCtx
+
+	slugs, err := c.parseSlugs(ctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	currencyList, err := c.service.WatchlistAdd(ctx, &slugs)
+	if err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) || errors.Is(err, apperror.ErrNotFound) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		return c.errInternal(ctx, metricName, "Failed to add to the watchlist", err)
+	}
+	return c.success(ctx, metricName, *currencyList)
+}
+
+// Remove takes the currencies off the watchlist.
+// Slugs are taken the same way as in Add.
+func (c *watchlistController) Remove(rctx *routing.Context) (err error) {
+	const metricName = "watchlistController.Remove"
+	ctx := rctx.RequestCtx
+
+	slugs, err := c.parseSlugs(ctx)
+	if err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	if err = c.service.WatchlistRemove(ctx, &slugs); err != nil {
+		if errors.Is(err, apperror.ErrBadRequest) || errors.Is(err, apperror.ErrNotFound) {
+			return c.badRequest(ctx, metricName, err)
+		}
+		return c.errInternal(ctx, metricName, "Failed to remove from the watchlist", err)
+	}
+	return c.success(ctx, metricName, slugs)
+}
+
+// Events returns the promotions and the demotions made by the discovery, the newest first.
+// Query params: action (promote, demote), slug, since (duration, e.g. 720h), limit.
+func (c *watchlistController) Events(rctx *routing.Context) (err error) {
+	const metricName = "watchlistController.Events"
+	ctx := rctx.RequestCtx
+
+	filter := &discovery.EventFilter{
+		Action: string(ctx.QueryArgs().Peek("action")),
+		Slug:   string(ctx.QueryArgs().Peek("slug")),
+	}
+	if err = discovery.ActionValidate(filter.Action); err != nil {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	if filter.Limit, err = fasthttp_tools.ParseQueryArgUint(ctx, "limit"); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return c.badRequest(ctx, metricName, err)
+	}
+
+	since, err := fasthttp_tools.ParseQueryArgDuration(ctx, "since")
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return c.badRequest(ctx, metricName, err)
+		}
+	} else {
+		t := time.Now().UTC().Add(-since)
+		filter.Since = &t
+	}
+
+	events, err := c.discovery.Events(ctx, filter)
+	if err != nil {
+		return c.errInternal(ctx, metricName, "Failed to get the watchlist events", err)
+	}
+	return c.success(ctx, metricName, *events)
+}
+
+func (c *watchlistController) parseSlugs(ctx *fasthttp.RequestCtx) ([]string, error) {
+	var slugs []string
+	if body := ctx.PostBody(); len(body) > 0 {
+		req := watchlistRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, fmt.Errorf("[%w] parse body error: %w", apperror.ErrBadRequest, err)
+		}
+		slugs = req.Slugs
+	} else if arg := ctx.QueryArgs().Peek("slugs"); len(arg) > 0 {
+		slugs = strings.Split(string(arg), ",")
+	}
+
+	res := make([]string, 0, len(slugs))
+	var slug string
+	for _, slug = range slugs {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			res = append(res, slug)
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("[%w] slugs are required", apperror.ErrBadRequest)
+	}
+	return res, nil
+}
+
+func (c *watchlistController) success(ctx *fasthttp.RequestCtx, metricName string, data interface{}) error {
+	res := fasthttp_tools.NewResponse_Success(data)
+	if err := fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusOK, *res); err != nil {
+		c.logger.Error("fasthttp_tools.FastHTTPWriteResult error", zap.String(log_key.Func, metricName), zap.Error(err))
+	}
+	return nil
+}
+
+func (c *watchlistController) errInternal(ctx *fasthttp.RequestCtx, metricName string, errMsg string, err error) error {
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrInternal()
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusInternalServerError, *res)
+	return nil
+}
+
+func (c *watchlistController) badRequest(ctx *fasthttp.RequestCtx, metricName string, err error) error {
+	errMsg := "Bad request "
+	c.logger.Error(errMsg, zap.String(log_key.Func, metricName), zap.Error(err))
+	res := fasthttp_tools.NewResponse_ErrBadRequest(errMsg + err.Error())
+	fasthttp_tools.FastHTTPWriteResult(ctx, fasthttp.StatusBadRequest, *res)
+	return nil
+}
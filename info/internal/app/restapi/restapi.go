@@ -96,6 +96,74 @@ func (a *RestAPI) buildHandler() {
 	cmcController := controller.NewCmcController(a.logger, r, a.Domain.Currency)
 	api.Get("/cmc/report/whale-biggest-fall", cmcController.Report_BiggestFall)
 	api.Get("/cmc/report/whale-longest-fall", cmcController.Report_LongestFall)
+	api.Get("/cmc/report/whale-biggest-rise", cmcController.Report_BiggestRise)
+	api.Get("/cmc/report/whale-longest-rise", cmcController.Report_LongestRise)
+	api.Get("/cmc/report/whale-momentum", cmcController.Report_WhaleMomentum)
+
+	importController := controller.NewImportController(a.logger, r, a.Domain.ImportRun)
+	api.Get("/imports", importController.Status)
+
+	watchlistController := controller.NewWatchlistController(a.logger, r, a.Domain.Currency, a.Domain.Discovery)
+	api.Get("/currencies/watchlist", watchlistController.List)
+	api.Post("/currencies/watchlist", watchlistController.Add)
+	api.Delete("/currencies/watchlist", watchlistController.Remove)
+	api.Get("/currencies/watchlist/events", watchlistController.Events)
+
+	currencyController := controller.NewCurrencyController(a.logger, r, a.Domain.Currency, a.Domain.PriceAndCap, a.Domain.Concentration)
+	api.Get("/currencies/<currency>", currencyController.Detail)
+	api.Get("/currencies/<currency>/prices", currencyController.Prices)
+	api.Get("/currencies/<currency>/concentration", currencyController.Concentration)
+
+	portfolioController := controller.NewPortfolioController(a.logger, r, a.Domain.Portfolio)
+	api.Get("/portfolios", portfolioController.List)
+	api.Get("/portfolios/<sourceId>", portfolioController.Get)
+	api.Get("/portfolios/<sourceId>/history", portfolioController.History)
+	api.Get("/portfolios/<sourceId>/positions/history", portfolioController.PositionsHistory)
+	api.Get("/portfolios/<sourceId>/benchmark", portfolioController.Benchmark)
+	api.Get("/portfolios/<sourceId>/risk", portfolioController.Risk)
+
+	alertController := controller.NewAlertController(a.logger, r, a.Domain.Alert, a.Domain.Currency)
+	api.Get("/alerts", alertController.Alerts)
+	api.Get("/alerts/rules", alertController.Rules)
+	api.Post("/alerts/rules", alertController.CreateRule)
+	api.Get("/alerts/rules/<id>", alertController.Rule)
+	api.Put("/alerts/rules/<id>", alertController.UpdateRule)
+	api.Delete("/alerts/rules/<id>", alertController.DeleteRule)
+	api.Post("/alerts/rules/<id>/test", alertController.TestRule)
+
+	backtestController := controller.NewBacktestController(a.logger, r, a.Domain.Backtest)
+	api.Post("/backtest", backtestController.Run)
+
+	screenerController := controller.NewScreenerController(a.logger, r, a.Domain.Screener)
+	api.Post("/screener", screenerController.Run)
+	api.Get("/screener/presets", screenerController.Presets)
+	api.Post("/screener/presets", screenerController.CreatePreset)
+	api.Get("/screener/presets/<preset>", screenerController.Preset)
+	api.Put("/screener/presets/<preset>", screenerController.UpdatePreset)
+	api.Delete("/screener/presets/<preset>", screenerController.DeletePreset)
+	api.Get("/screener/presets/<preset>/run", screenerController.RunPreset)
+
+	dataAuditController := controller.NewDataAuditController(a.logger, r, a.Domain.DataAudit, a.Domain.Currency)
+	api.Post("/data-audit", dataAuditController.Audit)
+	api.Get("/data-audit/runs", dataAuditController.Runs)
+	api.Get("/data-audit/issues", dataAuditController.Issues)
+	api.Get("/data-audit/quarantine", dataAuditController.Quarantine)
+	api.Post("/data-audit/issues/<id>/quarantine", dataAuditController.QuarantineIssue)
+	api.Delete("/data-audit/issues/<id>/quarantine", dataAuditController.ReleaseIssue)
+
+	exportController := controller.NewExportController(a.logger, r, a.Domain.Export, a.config.Rest.WriteTimeout)
+	api.Get("/export/<dataset>", exportController.Export)
+
+	oraculController := controller.NewOraculController(a.logger, r, a.Domain.Currency, a.Domain.OraculAnalytics, a.Domain.OraculSpeedometers, a.Domain.OraculHolderStats, a.Domain.OraculDailyBalanceStats)
+	api.Get("/oracul/analytics/latest", oraculController.AnalyticsLatest)
+	api.Get("/oracul/speedometers/latest", oraculController.SpeedometersLatest)
+	api.Get("/oracul/holder-stats/latest", oraculController.HolderStatsLatest)
+	api.Get("/oracul/daily-balance-stats/latest", oraculController.DailyBalanceStatsLatest)
+	api.Get("/oracul/currencies/<currency>/latest", oraculController.Latest)
+	api.Get("/oracul/currencies/<currency>/analytics", oraculController.Analytics)
+	api.Get("/oracul/currencies/<currency>/speedometers", oraculController.Speedometers)
+	api.Get("/oracul/currencies/<currency>/holder-stats", oraculController.HolderStats)
+	api.Get("/oracul/currencies/<currency>/daily-balance-stats", oraculController.DailyBalanceStats)
 
 	a.serverRestAPI.Handler = r.HandleRequest
 }
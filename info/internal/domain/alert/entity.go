@@ -0,0 +1,150 @@
+package alert
+
+import (
+	"context"
+	"info/internal/domain/currency"
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Kind_WhaleShareDrop = "whale-share-drop" // the whale share dropped by Threshold percents or more in Days days
+	Kind_PriceCross     = "price-cross"      // the price crossed the Threshold level in the Direction
+	Kind_ReportEntry    = "report-entry"     // the currency entered the top TopN of the Report
+	Kind_WormIndexAbove = "worm-index-above" // the worm index is above Threshold
+
+	Direction_Up   = "up"
+	Direction_Down = "down"
+	Direction_Any  = "any"
+
+	Notifier_Log     = "log"
+	Notifier_Webhook = "webhook"
+
+	DefaultCooldown   = 24 * time.Hour
+	DefaultReportTopN = 10
+	MaxDays           = 3650
+)
+
+var KindList = []interface{}{
+	Kind_WhaleShareDrop,
+	Kind_PriceCross,
+	Kind_ReportEntry,
+	Kind_WormIndexAbove,
+}
+
+var DirectionList = []interface{}{
+	Direction_Up,
+	Direction_Down,
+	Direction_Any,
+}
+
+var ReportList = []interface{}{
+	currency.Report_WhaleBiggestFall,
+	currency.Report_WhaleLongestFall,
+	currency.Report_WhaleBiggestRise,
+	currency.Report_WhaleLongestRise,
+}
+
+// Notifier delivers the fired alerts, e.g. to a webhook or to the log.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, item *Alert) error
+}
+
+// Rule is the condition checked after every import.
+// The rule of the whale-share-drop or the worm-index-above kind fires for every currency matching it, but not more often than once in Cooldown.
+// The rule of the price-cross or the report-entry kind fires only for the currencies which were not matched at the previous evaluation.
+type Rule struct {
+	ID         uint
+	Name       string
+	Kind       string
+	CurrencyID uint // 0 - all the observed currencies; it is required for price-cross
+	Threshold  float64
+	Days       uint
+	Direction  string
+	Report     string
+	TopN       uint
+	Cooldown   time.Duration
+	Notifiers  []string
+	IsEnabled  bool
+	Matched    []uint // the currencies matched at the last evaluation
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// SetDefaults sets the empty Cooldown, Notifiers, Direction and TopN to the default ones.
+func (e *Rule) SetDefaults() {
+	if e.Cooldown == 0 {
+		e.Cooldown = DefaultCooldown
+	}
+	if len(e.Notifiers) == 0 {
+		e.Notifiers = []string{Notifier_Log}
+	}
+	if e.Kind == Kind_PriceCross && e.Direction == "" {
+		e.Direction = Direction_Any
+	}
+	if e.Kind == Kind_ReportEntry && e.TopN == 0 {
+		e.TopN = DefaultReportTopN
+	}
+}
+
+func (e *Rule) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.Name, validation.Required),
+		validation.Field(&e.Kind, validation.Required, validation.In(KindList...)),
+		validation.Field(&e.CurrencyID, validation.When(e.Kind == Kind_PriceCross, validation.Required)),
+		validation.Field(&e.Threshold, validation.When(e.Kind == Kind_WhaleShareDrop || e.Kind == Kind_PriceCross, validation.Required, validation.Min(0.0))),
+		validation.Field(&e.Days, validation.When(e.Kind == Kind_WhaleShareDrop, validation.Required, validation.Max(uint(MaxDays)))),
+		validation.Field(&e.Direction, validation.When(e.Kind == Kind_PriceCross, validation.Required, validation.In(DirectionList...))),
+		validation.Field(&e.Report, validation.When(e.Kind == Kind_ReportEntry, validation.Required, validation.In(ReportList...))),
+		validation.Field(&e.Cooldown, validation.Min(time.Duration(0))),
+		validation.Field(&e.Notifiers, validation.Required),
+	)
+}
+
+// isEdge returns true if the rule fires only for the currencies which were not matched at the previous evaluation.
+func (e *Rule) isEdge() bool {
+	return e.Kind == Kind_PriceCross || e.Kind == Kind_ReportEntry
+}
+
+func (e *Rule) wasMatched(currencyID uint) bool {
+	return slices.Contains(e.Matched, currencyID)
+}
+
+type RuleList []Rule
+
+// Alert is the fired rule for the currency; Notified are the notifiers which delivered it, Error is the errors of the others.
+type Alert struct {
+	ID         uint
+	RuleID     uint
+	RuleName   string
+	Kind       string
+	CurrencyID uint
+	Symbol     string
+	Value      float64
+	Message    string
+	Notified   []string
+	Error      *string
+	FiredAt    time.Time
+}
+
+type AlertList []Alert
+
+// AlertFilter filters the fired alerts; the empty fields are not applied.
+type AlertFilter struct {
+	RuleID     uint
+	CurrencyID uint
+	Since      *time.Time
+	Limit      uint
+}
+
+// FiredKey is the rule and the currency which the cooldown is applied to.
+type FiredKey struct {
+	RuleID     uint
+	CurrencyID uint
+}
+
+// FiredMap is the time of the last alert by the rule and the currency.
+type FiredMap map[FiredKey]time.Time
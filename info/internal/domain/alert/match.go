@@ -0,0 +1,180 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain/concentration"
+	"info/internal/domain/currency"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"math"
+	"slices"
+	"time"
+)
+
+// whaleShareDropSlackDays is read before the window of the whale share drop for the days without the concentration.
+const whaleShareDropSlackDays = 7
+
+// matchWhaleShareDrop returns the currencies whose whale share at the last day dropped by Threshold percents or more
+// from the share Days days before. Only the last Days+whaleShareDropSlackDays days are read, so the currencies
+// without the concentration in the last whaleShareDropSlackDays days are skipped.
+func (s *Service) matchWhaleShareDrop(ctx context.Context, rule *Rule, currencies currency.CurrencyList, now time.Time) ([]match, error) {
+	res := make([]match, 0)
+	if len(currencies) == 0 {
+		return res, nil
+	}
+	from := now.AddDate(0, 0, -int(rule.Days)-whaleShareDropSlackDays)
+	concentrationMap, err := s.concentration.MGetBetween(ctx, currencies.IDs(), from, now)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	var ok bool
+	var concentrationList concentration.ConcentrationList
+	for _, item := range currencies {
+		if concentrationList, ok = concentrationMap[item.ID]; !ok {
+			continue
+		}
+		current := concentrationList.LastNotAfter(now)
+		// день до окна только для переноса доли на начало окна
+		if current == nil || current.D.Before(now.AddDate(0, 0, -whaleShareDropSlackDays)) {
+			continue
+		}
+		start := concentrationList.LastNotAfter(current.D.AddDate(0, 0, -int(rule.Days)))
+		if start == nil {
+			continue
+		}
+		startShare, currentShare := start.Shares().Whales, current.Shares().Whales
+		if startShare == 0 {
+			continue
+		}
+		drop := (startShare - currentShare) * 100 / startShare
+		if drop < rule.Threshold {
+			continue
+		}
+		res = append(res, match{
+			CurrencyID: item.ID,
+			Symbol:     item.Symbol,
+			Value:      round(drop),
+			Message:    fmt.Sprintf("%s: the whale share dropped by %.2f%% in %d days: %.2f%% -> %.2f%%", item.Symbol, drop, rule.Days, startShare, currentShare),
+		})
+	}
+	return res, nil
+}
+
+// matchPriceCross returns the currencies whose last price crossed the Threshold level in the Direction from the previous price.
+func (s *Service) matchPriceCross(ctx context.Context, rule *Rule, currencies currency.CurrencyList) ([]match, error) {
+	res := make([]match, 0)
+	if len(currencies) == 0 {
+		return res, nil
+	}
+	priceMap, err := s.priceAndCap.MGetLast(ctx, currencies.IDs(), 2)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	for _, item := range currencies {
+		l := slices.Clone(priceMap[item.ID])
+		if len(l) < 2 {
+			continue
+		}
+		slices.SortFunc(l, func(a, b price_and_cap.PriceAndCap) int {
+			return a.Ts.Compare(b.Ts)
+		})
+		prev, last := l[len(l)-2], l[len(l)-1]
+
+		var direction string
+		switch {
+		case prev.Price < rule.Threshold && last.Price >= rule.Threshold:
+			direction = Direction_Up
+		case prev.Price > rule.Threshold && last.Price <= rule.Threshold:
+			direction = Direction_Down
+		default:
+			continue
+		}
+		if rule.Direction != Direction_Any && rule.Direction != direction {
+			continue
+		}
+		res = append(res, match{
+			CurrencyID: item.ID,
+			Symbol:     item.Symbol,
+			Value:      last.Price,
+			Message:    fmt.Sprintf("%s: the price crossed %g %s: %g -> %g", item.Symbol, rule.Threshold, direction, prev.Price, last.Price),
+		})
+	}
+	return res, nil
+}
+
+// matchReportEntry returns the currencies in the top TopN of the report with the default params; Value is the position.
+func (s *Service) matchReportEntry(ctx context.Context, rule *Rule) ([]match, error) {
+	var report func(ctx context.Context, params *currency.WhaleTrendParams) (*currency.WhaleTrendList, uint, error)
+	switch rule.Report {
+	case currency.Report_WhaleBiggestFall:
+		report = s.currency.Report_BiggestFall
+	case currency.Report_WhaleLongestFall:
+		report = s.currency.Report_LongestFall
+	case currency.Report_WhaleBiggestRise:
+		report = s.currency.Report_BiggestRise
+	case currency.Report_WhaleLongestRise:
+		report = s.currency.Report_LongestRise
+	default:
+		return nil, fmt.Errorf("[%w] unknown report of the rule: %q", apperror.ErrInternal, rule.Report)
+	}
+
+	l, _, err := report(ctx, &currency.WhaleTrendParams{Limit: rule.TopN})
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return []match{}, nil
+		}
+		return nil, err
+	}
+
+	res := make([]match, 0, len(*l))
+	for i, item := range *l {
+		if rule.CurrencyID != 0 && item.CurrencyID != rule.CurrencyID {
+			continue
+		}
+		res = append(res, match{
+			CurrencyID: item.CurrencyID,
+			Symbol:     item.Symbol,
+			Value:      float64(i + 1),
+			Message:    fmt.Sprintf("%s entered the top %d of %s at the position %d: %s %.2f%% in %s", item.Symbol, rule.TopN, rule.Report, i+1, item.Direction, item.ValuePercent, item.Duration),
+		})
+	}
+	return res, nil
+}
+
+// matchWormIndexAbove returns the currencies whose last worm index is above Threshold.
+func (s *Service) matchWormIndexAbove(ctx context.Context, rule *Rule, currencies currency.CurrencyList) ([]match, error) {
+	res := make([]match, 0)
+	for _, item := range currencies {
+		analytics, err := s.oraculAnalytics.GetLast(ctx, item.ID)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		if analytics.WormIndex <= rule.Threshold {
+			continue
+		}
+		res = append(res, match{
+			CurrencyID: item.ID,
+			Symbol:     item.Symbol,
+			Value:      analytics.WormIndex,
+			Message:    fmt.Sprintf("%s: the worm index %g is above %g at %s", item.Symbol, analytics.WormIndex, rule.Threshold, analytics.Ts.Format(time.DateTime)),
+		})
+	}
+	return res, nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,27 @@
+package alert
+
+import (
+	"context"
+	"time"
+)
+
+type ReplicaSet interface {
+	WriteRepo() WriteRepository
+	ReadRepo() ReadRepository
+}
+
+type WriteRepository interface {
+	CreateRule(ctx context.Context, entity *Rule) (uint, error)
+	UpdateRule(ctx context.Context, entity *Rule) error
+	DeleteRule(ctx context.Context, ID uint) error
+	UpdateRuleMatched(ctx context.Context, ID uint, matched []uint) error
+	MCreateAlert(ctx context.Context, entities *AlertList) error
+	UpdateAlertNotified(ctx context.Context, entity *Alert) error
+}
+
+type ReadRepository interface {
+	GetRule(ctx context.Context, ID uint) (*Rule, error)
+	GetRules(ctx context.Context) (*RuleList, error)
+	GetAlerts(ctx context.Context, filter *AlertFilter) (*AlertList, error)
+	GetFiredMap(ctx context.Context, since time.Time) (FiredMap, error)
+}
@@ -0,0 +1,355 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain/concentration"
+	"info/internal/domain/currency"
+	"info/internal/domain/oracul_analytics"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Service struct {
+	replicaSet      ReplicaSet
+	currency        *currency.Service
+	concentration   *concentration.Service
+	priceAndCap     *price_and_cap.Service
+	oraculAnalytics *oracul_analytics.Service
+	notifiers       map[string]Notifier
+}
+
+func NewService(replicaSet ReplicaSet, currency *currency.Service, concentration *concentration.Service, priceAndCap *price_and_cap.Service, oraculAnalytics *oracul_analytics.Service, notifiers ...Notifier) *Service {
+	s := &Service{
+		replicaSet:      replicaSet,
+		currency:        currency,
+		concentration:   concentration,
+		priceAndCap:     priceAndCap,
+		oraculAnalytics: oraculAnalytics,
+		notifiers:       make(map[string]Notifier, len(notifiers)),
+	}
+	var notifier Notifier
+	for _, notifier = range notifiers {
+		s.notifiers[notifier.Name()] = notifier
+	}
+	return s
+}
+
+// Notifiers returns the names of the available notifiers.
+func (s *Service) Notifiers() []string {
+	res := make([]string, 0, len(s.notifiers))
+	for name := range s.notifiers {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (s *Service) GetRules(ctx context.Context) (*RuleList, error) {
+	return s.replicaSet.ReadRepo().GetRules(ctx)
+}
+
+func (s *Service) GetRule(ctx context.Context, ID uint) (*Rule, error) {
+	return s.replicaSet.ReadRepo().GetRule(ctx, ID)
+}
+
+func (s *Service) CreateRule(ctx context.Context, entity *Rule) (*Rule, error) {
+	if err := s.validateRule(entity); err != nil {
+		return nil, err
+	}
+	entity.CreatedAt = time.Now().UTC()
+	entity.UpdatedAt = entity.CreatedAt
+	entity.Matched = []uint{}
+
+	ID, err := s.replicaSet.WriteRepo().CreateRule(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	entity.ID = ID
+	return entity, nil
+}
+
+// UpdateRule replaces the rule; the currencies matched at the previous evaluation are forgotten, as the condition may be another.
+func (s *Service) UpdateRule(ctx context.Context, entity *Rule) (*Rule, error) {
+	if err := s.validateRule(entity); err != nil {
+		return nil, err
+	}
+	old, err := s.replicaSet.ReadRepo().GetRule(ctx, entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	entity.CreatedAt = old.CreatedAt
+	entity.UpdatedAt = time.Now().UTC()
+	entity.Matched = []uint{}
+
+	if err = s.replicaSet.WriteRepo().UpdateRule(ctx, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// DeleteRule deletes the rule with its fired alerts.
+func (s *Service) DeleteRule(ctx context.Context, ID uint) error {
+	return s.replicaSet.WriteRepo().DeleteRule(ctx, ID)
+}
+
+// Alerts returns the fired alerts, the newest first.
+func (s *Service) Alerts(ctx context.Context, filter *AlertFilter) (*AlertList, error) {
+	l, err := s.replicaSet.ReadRepo().GetAlerts(ctx, filter)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return &AlertList{}, nil
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// Test sends the test alert of the rule through its notifiers; the test alert is not stored.
+func (s *Service) Test(ctx context.Context, ID uint) (*Alert, error) {
+	rule, err := s.replicaSet.ReadRepo().GetRule(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	item := &Alert{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		Kind:     rule.Kind,
+		Message:  fmt.Sprintf("test alert of the rule %q", rule.Name),
+		FiredAt:  time.Now().UTC(),
+	}
+	s.notify(ctx, rule, item)
+	return item, nil
+}
+
+func (s *Service) validateRule(entity *Rule) error {
+	entity.SetDefaults()
+	if err := entity.Validate(); err != nil {
+		return fmt.Errorf("[%w] alert rule error: %w", apperror.ErrBadRequest, err)
+	}
+	var name string
+	for _, name = range entity.Notifiers {
+		if _, ok := s.notifiers[name]; !ok {
+			return fmt.Errorf("[%w] alert rule error: unknown notifier %q; available: %s", apperror.ErrBadRequest, name, strings.Join(s.Notifiers(), ", "))
+		}
+	}
+	return nil
+}
+
+// Evaluate checks the enabled rules, stores and delivers the fired alerts.
+// An error of one rule does not stop the others; the errors are joined.
+func (s *Service) Evaluate(ctx context.Context) (res *AlertList, err error) {
+	const metricName = "alert.Service.Evaluate"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	res = &AlertList{}
+	rules, err := s.replicaSet.ReadRepo().GetRules(ctx)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	var maxCooldown time.Duration
+	var rule Rule
+	for _, rule = range *rules {
+		if rule.IsEnabled && rule.Cooldown > maxCooldown {
+			maxCooldown = rule.Cooldown
+		}
+	}
+	firedMap, err := s.replicaSet.ReadRepo().GetFiredMap(ctx, now.Add(-maxCooldown))
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		firedMap = FiredMap{}
+	}
+
+	currencyList, err := s.currency.GetAll(ctx)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		currencyList = &currency.CurrencyList{}
+	}
+
+	var errs []error
+	ruleMap := make(map[uint]*Rule, len(*rules))
+	matchedMap := make(map[uint][]uint)
+	for i := range *rules {
+		rule = (*rules)[i]
+		if !rule.IsEnabled {
+			continue
+		}
+		ruleMap[rule.ID] = &(*rules)[i]
+		l, matched, err := s.evaluateRule(ctx, &rule, currencyList, firedMap, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alert rule %d %q evaluation error: %w", rule.ID, rule.Name, err))
+			continue
+		}
+		if rule.isEdge() && !slices.Equal(matched, rule.Matched) {
+			matchedMap[rule.ID] = matched
+		}
+		*res = append(*res, l...)
+	}
+
+	if err = s.fire(ctx, ruleMap, matchedMap, res); err != nil {
+		errs = append(errs, err)
+	}
+	return res, errors.Join(errs...)
+}
+
+// fire saves the alerts and the matched currencies of the edge rules (matchedMap by the rule ID)
+// and then delivers the alerts and saves the results of the delivery.
+// The alert is saved first, so the notification has its ID and the alert which is not saved is not sent:
+// the cooldown of the rule is counted by the saved alerts only. The matched currencies are saved only after the alerts,
+// otherwise the edge rule would not fire again on the matches whose alerts are lost.
+func (s *Service) fire(ctx context.Context, ruleMap map[uint]*Rule, matchedMap map[uint][]uint, l *AlertList) error {
+	if len(*l) > 0 {
+		if err := s.replicaSet.WriteRepo().MCreateAlert(ctx, l); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for ruleID, matched := range matchedMap {
+		if err := s.replicaSet.WriteRepo().UpdateRuleMatched(ctx, ruleID, matched); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i := range *l {
+		item := &(*l)[i]
+		rule, ok := ruleMap[item.RuleID]
+		if !ok {
+			continue
+		}
+		s.notify(ctx, rule, item)
+		if err := s.replicaSet.WriteRepo().UpdateAlertNotified(ctx, item); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// match is the currency matching the rule
+type match struct {
+	CurrencyID uint
+	Symbol     string
+	Value      float64
+	Message    string
+}
+
+// evaluateRule returns the new alerts of the rule and the sorted IDs of the matched currencies; nothing is saved.
+func (s *Service) evaluateRule(ctx context.Context, rule *Rule, currencyList *currency.CurrencyList, firedMap FiredMap, now time.Time) (AlertList, []uint, error) {
+	currencies, err := s.ruleCurrencies(ctx, rule, currencyList)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matches []match
+	switch rule.Kind {
+	case Kind_WhaleShareDrop:
+		matches, err = s.matchWhaleShareDrop(ctx, rule, currencies, now)
+	case Kind_PriceCross:
+		matches, err = s.matchPriceCross(ctx, rule, currencies)
+	case Kind_ReportEntry:
+		matches, err = s.matchReportEntry(ctx, rule)
+	case Kind_WormIndexAbove:
+		matches, err = s.matchWormIndexAbove(ctx, rule, currencies)
+	default:
+		err = fmt.Errorf("[%w] unknown kind of the rule: %q", apperror.ErrInternal, rule.Kind)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, matched := newAlerts(rule, matches, firedMap, now)
+	return res, matched, nil
+}
+
+// newAlerts returns the alerts of the matches which are not in the cooldown of the rule (and are new matches for the edge rule)
+// and the sorted IDs of all the matched currencies. The alerts are not delivered yet.
+func newAlerts(rule *Rule, matches []match, firedMap FiredMap, now time.Time) (AlertList, []uint) {
+	res := make(AlertList, 0)
+	matched := make([]uint, 0, len(matches))
+	var item match
+	for _, item = range matches {
+		matched = append(matched, item.CurrencyID)
+		if rule.isEdge() && rule.wasMatched(item.CurrencyID) {
+			continue
+		}
+		if last, ok := firedMap[FiredKey{RuleID: rule.ID, CurrencyID: item.CurrencyID}]; ok && now.Sub(last) < rule.Cooldown {
+			continue
+		}
+		res = append(res, Alert{
+			RuleID:     rule.ID,
+			RuleName:   rule.Name,
+			Kind:       rule.Kind,
+			CurrencyID: item.CurrencyID,
+			Symbol:     item.Symbol,
+			Value:      item.Value,
+			Message:    item.Message,
+			Notified:   []string{},
+			FiredAt:    now,
+		})
+	}
+	slices.Sort(matched)
+	return res, matched
+}
+
+// ruleCurrencies returns the currency of the rule or all the observed currencies if the rule is for any currency.
+func (s *Service) ruleCurrencies(ctx context.Context, rule *Rule, currencyList *currency.CurrencyList) (currency.CurrencyList, error) {
+	if rule.CurrencyID == 0 {
+		return *currencyList, nil
+	}
+	for i := range *currencyList {
+		if (*currencyList)[i].ID == rule.CurrencyID {
+			return currency.CurrencyList{(*currencyList)[i]}, nil
+		}
+	}
+	// монета правила может быть и не в вотчлисте
+	l, err := s.currency.MGet(ctx, &[]uint{rule.CurrencyID})
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return currency.CurrencyList{}, nil
+		}
+		return nil, err
+	}
+	return *l, nil
+}
+
+// notify delivers the alert through the notifiers of the rule and sets its Notified and Error.
+func (s *Service) notify(ctx context.Context, rule *Rule, item *Alert) {
+	item.Notified = make([]string, 0, len(rule.Notifiers))
+	errs := make([]string, 0)
+	var name string
+	for _, name = range rule.Notifiers {
+		notifier, ok := s.notifiers[name]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s: unknown notifier", name))
+			continue
+		}
+		if err := notifier.Notify(ctx, item); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+		item.Notified = append(item.Notified, name)
+	}
+	if len(errs) > 0 {
+		errStr := strings.Join(errs, "; ")
+		item.Error = &errStr
+	}
+}
@@ -0,0 +1,255 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeRepository keeps the created alerts in memory; it implements both the read and the write repositories.
+type fakeRepository struct {
+	alerts    AlertList
+	createErr error
+	calls     []string
+}
+
+func (r *fakeRepository) WriteRepo() WriteRepository { return r }
+func (r *fakeRepository) ReadRepo() ReadRepository   { return r }
+
+func (r *fakeRepository) CreateRule(ctx context.Context, entity *Rule) (uint, error) { return 0, nil }
+func (r *fakeRepository) UpdateRule(ctx context.Context, entity *Rule) error         { return nil }
+func (r *fakeRepository) DeleteRule(ctx context.Context, ID uint) error              { return nil }
+func (r *fakeRepository) UpdateRuleMatched(ctx context.Context, ID uint, matched []uint) error {
+	r.calls = append(r.calls, "UpdateRuleMatched")
+	return nil
+}
+func (r *fakeRepository) GetRule(ctx context.Context, ID uint) (*Rule, error) { return nil, nil }
+func (r *fakeRepository) GetRules(ctx context.Context) (*RuleList, error)     { return nil, nil }
+func (r *fakeRepository) GetAlerts(ctx context.Context, filter *AlertFilter) (*AlertList, error) {
+	return &r.alerts, nil
+}
+
+func (r *fakeRepository) MCreateAlert(ctx context.Context, entities *AlertList) error {
+	r.calls = append(r.calls, "MCreateAlert")
+	if r.createErr != nil {
+		return r.createErr
+	}
+	for i := range *entities {
+		(*entities)[i].ID = uint(len(r.alerts) + 1)
+		r.alerts = append(r.alerts, (*entities)[i])
+	}
+	return nil
+}
+
+func (r *fakeRepository) UpdateAlertNotified(ctx context.Context, entity *Alert) error {
+	r.calls = append(r.calls, "UpdateAlertNotified")
+	for i := range r.alerts {
+		if r.alerts[i].ID == entity.ID {
+			r.alerts[i].Notified = entity.Notified
+			r.alerts[i].Error = entity.Error
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) GetFiredMap(ctx context.Context, since time.Time) (FiredMap, error) {
+	res := FiredMap{}
+	for _, item := range r.alerts {
+		key := FiredKey{RuleID: item.RuleID, CurrencyID: item.CurrencyID}
+		if item.FiredAt.Before(since) || item.FiredAt.Before(res[key]) {
+			continue
+		}
+		res[key] = item.FiredAt
+	}
+	return res, nil
+}
+
+type fakeNotifier struct {
+	name string
+	err  error
+	ids  []uint // the IDs of the delivered alerts
+}
+
+func (n *fakeNotifier) Name() string { return n.name }
+
+func (n *fakeNotifier) Notify(ctx context.Context, item *Alert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.ids = append(n.ids, item.ID)
+	return nil
+}
+
+func newTestService(repo *fakeRepository, notifiers ...Notifier) *Service {
+	return NewService(repo, nil, nil, nil, nil, notifiers...)
+}
+
+func TestNewAlerts(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	matches := []match{{CurrencyID: 2, Symbol: "B"}, {CurrencyID: 1, Symbol: "A"}}
+	tests := []struct {
+		name        string
+		rule        Rule
+		firedMap    FiredMap
+		wantAlerts  []uint
+		wantMatched []uint
+	}{
+		{
+			name:        "never fired",
+			rule:        Rule{ID: 1, Kind: Kind_WormIndexAbove, Cooldown: time.Hour},
+			firedMap:    FiredMap{},
+			wantAlerts:  []uint{2, 1},
+			wantMatched: []uint{1, 2},
+		},
+		{
+			name:        "in the cooldown",
+			rule:        Rule{ID: 1, Kind: Kind_WormIndexAbove, Cooldown: time.Hour},
+			firedMap:    FiredMap{{RuleID: 1, CurrencyID: 2}: now.Add(-30 * time.Minute)},
+			wantAlerts:  []uint{1},
+			wantMatched: []uint{1, 2},
+		},
+		{
+			name:        "after the cooldown",
+			rule:        Rule{ID: 1, Kind: Kind_WormIndexAbove, Cooldown: time.Hour},
+			firedMap:    FiredMap{{RuleID: 1, CurrencyID: 2}: now.Add(-time.Hour)},
+			wantAlerts:  []uint{2, 1},
+			wantMatched: []uint{1, 2},
+		},
+		{
+			name:        "cooldown of the other rule",
+			rule:        Rule{ID: 1, Kind: Kind_WormIndexAbove, Cooldown: time.Hour},
+			firedMap:    FiredMap{{RuleID: 2, CurrencyID: 2}: now},
+			wantAlerts:  []uint{2, 1},
+			wantMatched: []uint{1, 2},
+		},
+		{
+			name:        "edge rule fires on the new matches only",
+			rule:        Rule{ID: 1, Kind: Kind_PriceCross, Matched: []uint{2}},
+			firedMap:    FiredMap{},
+			wantAlerts:  []uint{1},
+			wantMatched: []uint{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, matched := newAlerts(&tt.rule, matches, tt.firedMap, now)
+			ids := make([]uint, 0, len(alerts))
+			for _, item := range alerts {
+				ids = append(ids, item.CurrencyID)
+				if item.ID != 0 || item.Notified == nil || len(item.Notified) != 0 || !item.FiredAt.Equal(now) {
+					t.Fatalf("alert = %+v, want the new not delivered alert", item)
+				}
+			}
+			if !slices.Equal(ids, tt.wantAlerts) {
+				t.Fatalf("alerts of the currencies = %v, want %v", ids, tt.wantAlerts)
+			}
+			if !slices.Equal(matched, tt.wantMatched) {
+				t.Fatalf("matched = %v, want %v", matched, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestService_fire(t *testing.T) {
+	errDelivery := errors.New("connection refused")
+	tests := []struct {
+		name         string
+		createErr    error
+		notifierErr  error
+		matchedMap   map[uint][]uint
+		wantErr      bool
+		wantCalls    []string
+		wantIDs      []uint
+		wantNotified []string
+		wantError    bool
+	}{
+		{
+			name:         "saved and delivered",
+			wantCalls:    []string{"MCreateAlert", "UpdateAlertNotified"},
+			wantIDs:      []uint{1},
+			wantNotified: []string{"test"},
+		},
+		{
+			name:         "not delivered",
+			notifierErr:  errDelivery,
+			wantCalls:    []string{"MCreateAlert", "UpdateAlertNotified"},
+			wantNotified: []string{},
+			wantError:    true,
+		},
+		{
+			name:      "not saved is not sent",
+			createErr: errors.New("db is down"),
+			wantErr:   true,
+			wantCalls: []string{"MCreateAlert"},
+		},
+		{
+			name:         "matched saved after the alert",
+			matchedMap:   map[uint][]uint{7: {1}},
+			wantCalls:    []string{"MCreateAlert", "UpdateRuleMatched", "UpdateAlertNotified"},
+			wantIDs:      []uint{1},
+			wantNotified: []string{"test"},
+		},
+		{
+			name:       "matched not saved without the alert",
+			createErr:  errors.New("db is down"),
+			matchedMap: map[uint][]uint{7: {1}},
+			wantErr:    true,
+			wantCalls:  []string{"MCreateAlert"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{createErr: tt.createErr}
+			notifier := &fakeNotifier{name: "test", err: tt.notifierErr}
+			s := newTestService(repo, notifier)
+			rule := &Rule{ID: 7, Kind: Kind_WormIndexAbove, Notifiers: []string{"test"}}
+			l := &AlertList{{RuleID: rule.ID, CurrencyID: 1, Notified: []string{}}}
+
+			err := s.fire(context.Background(), map[uint]*Rule{rule.ID: rule}, tt.matchedMap, l)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fire() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(repo.calls, tt.wantCalls) {
+				t.Fatalf("repository calls = %v, want %v", repo.calls, tt.wantCalls)
+			}
+			if !slices.Equal(notifier.ids, tt.wantIDs) {
+				t.Fatalf("delivered IDs = %v, want %v", notifier.ids, tt.wantIDs)
+			}
+			if tt.createErr != nil {
+				return
+			}
+			saved := repo.alerts[0]
+			if !slices.Equal(saved.Notified, tt.wantNotified) {
+				t.Fatalf("saved Notified = %v, want %v", saved.Notified, tt.wantNotified)
+			}
+			if (saved.Error != nil) != tt.wantError {
+				t.Fatalf("saved Error = %v, want error %v", saved.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestService_cooldown fires the rule on every evaluation: the alert is delivered again only after the cooldown of the saved alert.
+func TestService_cooldown(t *testing.T) {
+	repo := &fakeRepository{}
+	notifier := &fakeNotifier{name: "test"}
+	s := newTestService(repo, notifier)
+	rule := &Rule{ID: 1, Kind: Kind_WormIndexAbove, Cooldown: time.Hour, Notifiers: []string{"test"}}
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	for _, elapsed := range []time.Duration{0, 10 * time.Minute, 59 * time.Minute, time.Hour, 90 * time.Minute, 2 * time.Hour} {
+		now := start.Add(elapsed)
+		firedMap, _ := repo.GetFiredMap(context.Background(), now.Add(-rule.Cooldown))
+		l, _ := newAlerts(rule, []match{{CurrencyID: 1, Symbol: "A"}}, firedMap, now)
+		if err := s.fire(context.Background(), map[uint]*Rule{rule.ID: rule}, nil, &l); err != nil {
+			t.Fatalf("fire() at %v error: %v", elapsed, err)
+		}
+	}
+	if want := []uint{1, 2, 3}; !slices.Equal(notifier.ids, want) {
+		t.Fatalf("delivered IDs = %v, want %v at 0, 1h and 2h", notifier.ids, want)
+	}
+}
@@ -0,0 +1,185 @@
+package backtest
+
+import (
+	"info/internal/pkg/trend"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Cohort_Whales    = "whales"
+	Cohort_Investors = "investors"
+	Cohort_Retail    = "retail"
+
+	ExitReason_Hold       = "hold"
+	ExitReason_StopLoss   = "stop-loss"
+	ExitReason_TakeProfit = "take-profit"
+	ExitReason_End        = "end" // the trade is still open at the end of the window; it is closed at the last price
+
+	DefaultDays    = 365
+	DefaultCapital = 10000
+	DefaultStake   = 1000
+	MaxDays        = 3650
+	MaxStrategies  = 20
+)
+
+var CohortList = []interface{}{
+	Cohort_Whales,
+	Cohort_Investors,
+	Cohort_Retail,
+}
+
+var DirectionList = []interface{}{
+	trend.Direction_Rise,
+	trend.Direction_Fall,
+}
+
+// Strategy is the entry and the exit rules: the currency is bought at the close of the day when the share of the Cohort
+// changed by ChangePercent percents (of the share) or more in the Direction over LookbackDays days;
+// it is sold at the close of the day when the price fell by StopLossPercent, rose by TakeProfitPercent or HoldDays passed.
+// The zero StopLossPercent and TakeProfitPercent are not applied.
+type Strategy struct {
+	Name              string
+	Cohort            string
+	Direction         string
+	ChangePercent     float64
+	LookbackDays      uint
+	HoldDays          uint
+	StopLossPercent   float64
+	TakeProfitPercent float64
+}
+
+// SetDefaults sets the empty Cohort to whales and the empty Direction to rise.
+func (e *Strategy) SetDefaults() {
+	if e.Cohort == "" {
+		e.Cohort = Cohort_Whales
+	}
+	if e.Direction == "" {
+		e.Direction = trend.Direction_Rise
+	}
+}
+
+func (e *Strategy) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.Name, validation.Required),
+		validation.Field(&e.Cohort, validation.Required, validation.In(CohortList...)),
+		validation.Field(&e.Direction, validation.Required, validation.In(DirectionList...)),
+		validation.Field(&e.ChangePercent, validation.Required, validation.Min(0.0)),
+		validation.Field(&e.LookbackDays, validation.Required, validation.Max(uint(MaxDays))),
+		validation.Field(&e.HoldDays, validation.Required, validation.Max(uint(MaxDays))),
+		validation.Field(&e.StopLossPercent, validation.Min(0.0), validation.Max(100.0)),
+		validation.Field(&e.TakeProfitPercent, validation.Min(0.0)),
+	)
+}
+
+// Params of the backtest: the currencies by Slugs and IDs (all the observed currencies if both are empty), the window and the strategies.
+// Every trade invests Stake; the equity is Capital plus the profit of the closed trades and of the open ones.
+type Params struct {
+	Slugs      []string
+	IDs        []uint
+	From       time.Time
+	To         time.Time
+	Capital    float64
+	Stake      float64
+	Strategies []Strategy
+}
+
+// SetDefaults sets the empty To to now, the empty From to DefaultDays before To, the empty Capital and Stake to the default ones.
+func (e *Params) SetDefaults() {
+	if e.To.IsZero() {
+		e.To = time.Now().UTC()
+	}
+	if e.From.IsZero() {
+		e.From = e.To.AddDate(0, 0, -DefaultDays)
+	}
+	if e.Capital == 0 {
+		e.Capital = DefaultCapital
+	}
+	if e.Stake == 0 {
+		e.Stake = DefaultStake
+	}
+	for i := range e.Strategies {
+		e.Strategies[i].SetDefaults()
+	}
+}
+
+func (e *Params) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.To, validation.Required, validation.Min(e.From), validation.Max(e.From.AddDate(0, 0, MaxDays))),
+		validation.Field(&e.Capital, validation.Required, validation.Min(0.0)),
+		validation.Field(&e.Stake, validation.Required, validation.Min(0.0)),
+		validation.Field(&e.Strategies, validation.Required, validation.Length(1, MaxStrategies)),
+	)
+}
+
+// maxLookbackDays returns the biggest LookbackDays of the strategies.
+func (e *Params) maxLookbackDays() uint {
+	var res uint
+	for i := range e.Strategies {
+		if e.Strategies[i].LookbackDays > res {
+			res = e.Strategies[i].LookbackDays
+		}
+	}
+	return res
+}
+
+// Trade is the position opened by the strategy; ReturnPercent is the change of the price from the entry to the exit.
+type Trade struct {
+	Strategy      string
+	CurrencyID    uint
+	Symbol        string
+	Signal        float64 // the change of the cohort share in percents which triggered the entry
+	EntryAt       time.Time
+	EntryPrice    float64
+	ExitAt        time.Time
+	ExitPrice     float64
+	ExitReason    string
+	HoldDays      uint
+	ReturnPercent float64
+	Profit        float64
+}
+
+type TradeList []Trade
+
+// EquityPoint is the equity at the close of the day D
+type EquityPoint struct {
+	D          time.Time
+	Equity     float64
+	OpenTrades uint
+}
+
+type EquityCurve []EquityPoint
+
+// Drawdown is the biggest fall of the equity from the peak to the trough in percents of the peak.
+type Drawdown struct {
+	Percent  float64
+	PeakAt   time.Time
+	TroughAt time.Time
+}
+
+// StrategyResult is the trades of the strategy with their summary; HitRate is the share of the profitable trades in percents.
+type StrategyResult struct {
+	Strategy    Strategy
+	TradesNb    uint
+	WinsNb      uint
+	HitRate     float64
+	AvgReturn   float64
+	AvgHoldDays float64
+	TotalReturn float64 // the change of the equity in percents of the capital
+	FinalEquity float64
+	MaxDrawdown Drawdown
+	Trades      TradeList
+	Equity      EquityCurve
+}
+
+type StrategyResultList []StrategyResult
+
+type Result struct {
+	From       time.Time
+	To         time.Time
+	Capital    float64
+	Stake      float64
+	Currencies []string // the symbols
+	Strategies StrategyResultList
+}
@@ -0,0 +1,243 @@
+package backtest
+
+import (
+	"info/internal/domain/concentration"
+	"info/internal/domain/currency"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/trend"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// shareSeries is the share of the cohort by the day; it is carried forward from the last day with the data,
+// isActual is true if there is the concentration of exactly that day.
+type shareSeries struct {
+	values   []float64
+	isActual []bool
+}
+
+// currencySeries is the daily closes and the cohort shares of the currency.
+// The shares start lookback days before the window, so the index of the day i of the window in them is i+lookback.
+type currencySeries struct {
+	currency currency.Currency
+	closes   []float64
+	shares   map[string]shareSeries
+}
+
+func replay(params *Params, currencyList currency.CurrencyList, concentrationMap concentration.ConcentrationMap, priceMap price_and_cap.PriceAndCapMap) *Result {
+	days := price_and_cap.DayList(params.From, params.To)
+	lookback := params.maxLookbackDays()
+	extDays := price_and_cap.DayList(days[0].AddDate(0, 0, -int(lookback)), params.To)
+
+	res := &Result{
+		From:       days[0],
+		To:         days[len(days)-1],
+		Capital:    params.Capital,
+		Stake:      params.Stake,
+		Currencies: make([]string, 0, len(currencyList)),
+		Strategies: make(StrategyResultList, 0, len(params.Strategies)),
+	}
+
+	seriesList := make([]currencySeries, 0, len(currencyList))
+	for _, item := range currencyList {
+		res.Currencies = append(res.Currencies, item.Symbol)
+		l := priceMap[item.ID]
+		c := concentrationMap[item.ID]
+		seriesList = append(seriesList, currencySeries{
+			currency: item,
+			closes:   l.DailyCloses(days),
+			shares: map[string]shareSeries{
+				Cohort_Whales:    dailyShares(c, extDays, func(s concentration.Shares) float64 { return s.Whales }),
+				Cohort_Investors: dailyShares(c, extDays, func(s concentration.Shares) float64 { return s.Investors }),
+				Cohort_Retail:    dailyShares(c, extDays, func(s concentration.Shares) float64 { return s.Retail }),
+			},
+		})
+	}
+
+	for i := range params.Strategies {
+		res.Strategies = append(res.Strategies, runStrategy(&params.Strategies[i], params, days, int(lookback), seriesList))
+	}
+	return res
+}
+
+// runStrategy opens at most one trade of the currency at a time; the trade closed at the day is not re-opened before the next day.
+func runStrategy(strategy *Strategy, params *Params, days []time.Time, offset int, seriesList []currencySeries) StrategyResult {
+	res := StrategyResult{
+		Strategy: *strategy,
+		Trades:   make(TradeList, 0),
+		Equity:   make(EquityCurve, 0, len(days)),
+	}
+	open := make([]*Trade, len(seriesList))
+	entryIdx := make([]int, len(seriesList))
+	var realized float64
+
+	for i, d := range days {
+		var unrealized float64
+		var openNb uint
+		for c := range seriesList {
+			price := seriesList[c].closes[i]
+			if trade := open[c]; trade != nil {
+				if reason := exitReason(strategy, trade.EntryPrice, price, uint(i-entryIdx[c])); reason != "" {
+					closeTrade(trade, d, price, reason, uint(i-entryIdx[c]), params.Stake)
+					realized += trade.Profit
+					res.Trades = append(res.Trades, *trade)
+					open[c] = nil
+					continue
+				}
+				unrealized += params.Stake * (price/trade.EntryPrice - 1)
+				openNb++
+				continue
+			}
+
+			if price == 0 {
+				continue
+			}
+			change, ok := signal(strategy, seriesList[c].shares[strategy.Cohort], i+offset)
+			if !ok {
+				continue
+			}
+			open[c] = &Trade{
+				Strategy:   strategy.Name,
+				CurrencyID: seriesList[c].currency.ID,
+				Symbol:     seriesList[c].currency.Symbol,
+				Signal:     round(change),
+				EntryAt:    d,
+				EntryPrice: price,
+			}
+			entryIdx[c] = i
+			openNb++
+		}
+		res.Equity = append(res.Equity, EquityPoint{
+			D:          d,
+			Equity:     round(params.Capital + realized + unrealized),
+			OpenTrades: openNb,
+		})
+	}
+
+	last := len(days) - 1
+	for c, trade := range open {
+		if trade == nil {
+			continue
+		}
+		closeTrade(trade, days[last], seriesList[c].closes[last], ExitReason_End, uint(last-entryIdx[c]), params.Stake)
+		realized += trade.Profit
+		res.Trades = append(res.Trades, *trade)
+	}
+	slices.SortFunc(res.Trades, func(a, b Trade) int {
+		if n := a.EntryAt.Compare(b.EntryAt); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Symbol, b.Symbol)
+	})
+
+	summarize(&res, params)
+	return res
+}
+
+// signal returns the change of the cohort share in percents over the lookback days to the day k if it triggers the entry.
+func signal(strategy *Strategy, shares shareSeries, k int) (float64, bool) {
+	start := k - int(strategy.LookbackDays)
+	if start < 0 || !shares.isActual[k] || shares.values[start] == 0 {
+		return 0, false
+	}
+	change := (shares.values[k] - shares.values[start]) * 100 / shares.values[start]
+	switch strategy.Direction {
+	case trend.Direction_Rise:
+		return change, change >= strategy.ChangePercent
+	case trend.Direction_Fall:
+		return change, change <= -strategy.ChangePercent
+	}
+	return 0, false
+}
+
+// exitReason returns the reason to close the trade at the price after the held days or the empty string to keep it.
+func exitReason(strategy *Strategy, entryPrice float64, price float64, held uint) string {
+	change := (price/entryPrice - 1) * 100
+	switch {
+	case strategy.StopLossPercent > 0 && change <= -strategy.StopLossPercent:
+		return ExitReason_StopLoss
+	case strategy.TakeProfitPercent > 0 && change >= strategy.TakeProfitPercent:
+		return ExitReason_TakeProfit
+	case held >= strategy.HoldDays:
+		return ExitReason_Hold
+	}
+	return ""
+}
+
+func closeTrade(trade *Trade, d time.Time, price float64, reason string, held uint, stake float64) {
+	trade.ExitAt = d
+	trade.ExitPrice = price
+	trade.ExitReason = reason
+	trade.HoldDays = held
+	trade.ReturnPercent = round((price/trade.EntryPrice - 1) * 100)
+	trade.Profit = round(stake * (price/trade.EntryPrice - 1))
+}
+
+func summarize(res *StrategyResult, params *Params) {
+	res.TradesNb = uint(len(res.Trades))
+	if res.TradesNb > 0 {
+		var sumReturn, sumHold float64
+		var trade Trade
+		for _, trade = range res.Trades {
+			if trade.ReturnPercent > 0 {
+				res.WinsNb++
+			}
+			sumReturn += trade.ReturnPercent
+			sumHold += float64(trade.HoldDays)
+		}
+		res.HitRate = round(float64(res.WinsNb) * 100 / float64(res.TradesNb))
+		res.AvgReturn = round(sumReturn / float64(res.TradesNb))
+		res.AvgHoldDays = round(sumHold / float64(res.TradesNb))
+	}
+	res.FinalEquity = params.Capital
+	if len(res.Equity) > 0 {
+		res.FinalEquity = res.Equity[len(res.Equity)-1].Equity
+	}
+	res.TotalReturn = round((res.FinalEquity - params.Capital) * 100 / params.Capital)
+	res.MaxDrawdown = maxDrawdown(res.Equity)
+}
+
+func maxDrawdown(curve EquityCurve) Drawdown {
+	values := make([]float64, len(curve))
+	for i := range curve {
+		values[i] = curve[i].Equity
+	}
+	percent, peak, trough := trend.MaxDrawdown(values)
+	if percent == 0 {
+		return Drawdown{}
+	}
+	return Drawdown{
+		Percent:  round(percent),
+		PeakAt:   curve[peak].D,
+		TroughAt: curve[trough].D,
+	}
+}
+
+// dailyShares returns the share of the cohort by the day.
+func dailyShares(l concentration.ConcentrationList, days []time.Time, share func(s concentration.Shares) float64) shareSeries {
+	res := shareSeries{
+		values:   make([]float64, len(days)),
+		isActual: make([]bool, len(days)),
+	}
+	items := slices.Clone(l)
+	slices.SortFunc(items, func(a, b concentration.Concentration) int {
+		return a.D.Compare(b.D)
+	})
+	var j int
+	var value float64
+	for i, d := range days {
+		end := d.AddDate(0, 0, 1)
+		for ; j < len(items) && items[j].D.Before(end); j++ {
+			value = share(items[j].Shares())
+			res.isActual[i] = !items[j].D.Before(d)
+		}
+		res.values[i] = value
+	}
+	return res
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,185 @@
+package backtest
+
+import (
+	"info/internal/pkg/trend"
+	"testing"
+	"time"
+)
+
+func day(n int) time.Time {
+	return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n)
+}
+
+func curve(equities ...float64) EquityCurve {
+	res := make(EquityCurve, 0, len(equities))
+	for i, equity := range equities {
+		res = append(res, EquityPoint{D: day(i), Equity: equity})
+	}
+	return res
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve EquityCurve
+		want  Drawdown
+	}{
+		{name: "empty", curve: EquityCurve{}, want: Drawdown{}},
+		{name: "flat", curve: curve(100, 100, 100), want: Drawdown{}},
+		{name: "rising", curve: curve(100, 110, 120), want: Drawdown{}},
+		{name: "single trough", curve: curve(100, 120, 90, 110), want: Drawdown{Percent: 25, PeakAt: day(1), TroughAt: day(2)}},
+		{name: "deeper trough after recovery", curve: curve(100, 80, 130, 65, 140), want: Drawdown{Percent: 50, PeakAt: day(2), TroughAt: day(3)}},
+		{name: "bigger earlier trough", curve: curve(100, 50, 200, 180), want: Drawdown{Percent: 50, PeakAt: day(0), TroughAt: day(1)}},
+		{name: "rounded", curve: curve(300, 200), want: Drawdown{Percent: 33.33, PeakAt: day(0), TroughAt: day(1)}},
+		{name: "non-positive peak", curve: curve(0, -10), want: Drawdown{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maxDrawdown(tt.curve); got != tt.want {
+				t.Errorf("maxDrawdown() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	params := &Params{Capital: 1000, Stake: 100}
+	tests := []struct {
+		name   string
+		trades TradeList
+		equity EquityCurve
+		want   StrategyResult
+	}{
+		{
+			name:   "no trades",
+			trades: TradeList{},
+			equity: curve(1000, 1000),
+			want:   StrategyResult{FinalEquity: 1000},
+		},
+		{
+			name:   "no equity",
+			trades: TradeList{},
+			equity: EquityCurve{},
+			want:   StrategyResult{FinalEquity: 1000},
+		},
+		{
+			name: "wins and losses",
+			trades: TradeList{
+				{ReturnPercent: 10, HoldDays: 2},
+				{ReturnPercent: -5, HoldDays: 3},
+				{ReturnPercent: 0, HoldDays: 1},
+			},
+			equity: curve(1000, 1100, 1005),
+			want: StrategyResult{
+				TradesNb:    3,
+				WinsNb:      1,
+				HitRate:     33.33,
+				AvgReturn:   1.67,
+				AvgHoldDays: 2,
+				FinalEquity: 1005,
+				TotalReturn: 0.5,
+				MaxDrawdown: Drawdown{Percent: 8.64, PeakAt: day(1), TroughAt: day(2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := StrategyResult{Trades: tt.trades, Equity: tt.equity}
+			summarize(&res, params)
+			if res.TradesNb != tt.want.TradesNb || res.WinsNb != tt.want.WinsNb {
+				t.Errorf("TradesNb, WinsNb = %d, %d, want %d, %d", res.TradesNb, res.WinsNb, tt.want.TradesNb, tt.want.WinsNb)
+			}
+			if res.HitRate != tt.want.HitRate || res.AvgReturn != tt.want.AvgReturn || res.AvgHoldDays != tt.want.AvgHoldDays {
+				t.Errorf("HitRate, AvgReturn, AvgHoldDays = %v, %v, %v, want %v, %v, %v",
+					res.HitRate, res.AvgReturn, res.AvgHoldDays, tt.want.HitRate, tt.want.AvgReturn, tt.want.AvgHoldDays)
+			}
+			if res.FinalEquity != tt.want.FinalEquity || res.TotalReturn != tt.want.TotalReturn {
+				t.Errorf("FinalEquity, TotalReturn = %v, %v, want %v, %v", res.FinalEquity, res.TotalReturn, tt.want.FinalEquity, tt.want.TotalReturn)
+			}
+			if res.MaxDrawdown != tt.want.MaxDrawdown {
+				t.Errorf("MaxDrawdown = %+v, want %+v", res.MaxDrawdown, tt.want.MaxDrawdown)
+			}
+		})
+	}
+}
+
+func TestExitReason(t *testing.T) {
+	strategy := &Strategy{HoldDays: 5, StopLossPercent: 10, TakeProfitPercent: 20}
+	noLimits := &Strategy{HoldDays: 5}
+	tests := []struct {
+		name     string
+		strategy *Strategy
+		price    float64
+		held     uint
+		want     string
+	}{
+		{name: "keep", strategy: strategy, price: 105, held: 1, want: ""},
+		{name: "stop-loss", strategy: strategy, price: 89, held: 1, want: ExitReason_StopLoss},
+		{name: "take-profit", strategy: strategy, price: 121, held: 1, want: ExitReason_TakeProfit},
+		{name: "stop-loss before hold", strategy: strategy, price: 80, held: 5, want: ExitReason_StopLoss},
+		{name: "hold", strategy: strategy, price: 100, held: 5, want: ExitReason_Hold},
+		{name: "zero stop-loss is not applied", strategy: noLimits, price: 10, held: 1, want: ""},
+		{name: "zero take-profit is not applied", strategy: noLimits, price: 1000, held: 1, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitReason(tt.strategy, 100, tt.price, tt.held); got != tt.want {
+				t.Errorf("exitReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloseTrade(t *testing.T) {
+	trade := &Trade{EntryPrice: 200}
+	closeTrade(trade, day(3), 250, ExitReason_TakeProfit, 3, 1000)
+	want := Trade{
+		EntryPrice:    200,
+		ExitAt:        day(3),
+		ExitPrice:     250,
+		ExitReason:    ExitReason_TakeProfit,
+		HoldDays:      3,
+		ReturnPercent: 25,
+		Profit:        250,
+	}
+	if *trade != want {
+		t.Errorf("closeTrade() = %+v, want %+v", *trade, want)
+	}
+}
+
+func TestSignal(t *testing.T) {
+	shares := shareSeries{
+		values:   []float64{0.5, 0.5, 0.6, 0.4, 0.4},
+		isActual: []bool{true, true, true, true, false},
+	}
+	zeroStart := shareSeries{
+		values:   []float64{0, 0.5},
+		isActual: []bool{true, true},
+	}
+	rise := &Strategy{Direction: trend.Direction_Rise, ChangePercent: 10, LookbackDays: 1}
+	fall := &Strategy{Direction: trend.Direction_Fall, ChangePercent: 10, LookbackDays: 1}
+	tests := []struct {
+		name       string
+		strategy   *Strategy
+		shares     shareSeries
+		k          int
+		wantChange float64
+		wantOk     bool
+	}{
+		{name: "before the lookback", strategy: rise, shares: shares, k: 0, wantChange: 0, wantOk: false},
+		{name: "rise triggers", strategy: rise, shares: shares, k: 2, wantChange: 20, wantOk: true},
+		{name: "rise does not trigger on the fall", strategy: rise, shares: shares, k: 3, wantChange: -33.33, wantOk: false},
+		{name: "fall triggers", strategy: fall, shares: shares, k: 3, wantChange: -33.33, wantOk: true},
+		{name: "no change", strategy: rise, shares: shares, k: 1, wantChange: 0, wantOk: false},
+		{name: "carried forward share", strategy: fall, shares: shares, k: 4, wantChange: 0, wantOk: false},
+		{name: "zero start share", strategy: rise, shares: zeroStart, k: 1, wantChange: 0, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, ok := signal(tt.strategy, tt.shares, tt.k)
+			if round(change) != tt.wantChange || ok != tt.wantOk {
+				t.Errorf("signal() = %v, %v, want %v, %v", round(change), ok, tt.wantChange, tt.wantOk)
+			}
+		})
+	}
+}
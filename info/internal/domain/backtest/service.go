@@ -0,0 +1,87 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain/concentration"
+	"info/internal/domain/currency"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"runtime/debug"
+)
+
+type Service struct {
+	currency      *currency.Service
+	concentration *concentration.Service
+	priceAndCap   *price_and_cap.Service
+}
+
+func NewService(currency *currency.Service, concentration *concentration.Service, priceAndCap *price_and_cap.Service) *Service {
+	return &Service{
+		currency:      currency,
+		concentration: concentration,
+		priceAndCap:   priceAndCap,
+	}
+}
+
+// Run replays the stored history of the concentration and of the prices day by day and applies the strategies to every currency.
+// It reads the database only.
+func (s *Service) Run(ctx context.Context, params *Params) (res *Result, err error) {
+	const metricName = "backtest.Service.Run"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	if err = paramsPrepare(params); err != nil {
+		return nil, err
+	}
+
+	currencyList, err := s.currencyList(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	IDs := currencyList.IDs()
+
+	// доли нужны и за LookbackDays до окна; последняя точка до начала чтения переносится вперёд
+	from := params.From.AddDate(0, 0, -int(params.maxLookbackDays()))
+	concentrationMap, err := s.concentration.MGetBetween(ctx, IDs, from, params.To)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	priceMap, err := s.priceAndCap.MGetBetween(ctx, IDs, params.From, params.To)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+
+	return replay(params, *currencyList, concentrationMap, priceMap), nil
+}
+
+func paramsPrepare(params *Params) error {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("[%w] backtest params error: %w", apperror.ErrBadRequest, err)
+	}
+	names := make(map[string]struct{}, len(params.Strategies))
+	for i := range params.Strategies {
+		if err := params.Strategies[i].Validate(); err != nil {
+			return fmt.Errorf("[%w] backtest strategy %d error: %w", apperror.ErrBadRequest, i, err)
+		}
+		if _, ok := names[params.Strategies[i].Name]; ok {
+			return fmt.Errorf("[%w] backtest strategy %d error: duplicate name %q", apperror.ErrBadRequest, i, params.Strategies[i].Name)
+		}
+		names[params.Strategies[i].Name] = struct{}{}
+	}
+	return nil
+}
+
+// currencyList returns the currencies by the slugs and the IDs of the params or all the observed currencies if both are empty.
+func (s *Service) currencyList(ctx context.Context, params *Params) (*currency.CurrencyList, error) {
+	if len(params.Slugs) == 0 && len(params.IDs) == 0 {
+		return s.currency.GetAll(ctx)
+	}
+	return s.currency.MGetBySlugsAndIDs(ctx, &params.Slugs, &params.IDs)
+}
@@ -1,6 +1,9 @@
 package concentration
 
-import "time"
+import (
+	"info/internal/pkg/trend"
+	"time"
+)
 
 const ()
 
@@ -26,6 +29,35 @@ func (l *ConcentrationList) Slice() *[]Concentration {
 	return &res
 }
 
+// Between returns the items from the window [from, to]
+func (l *ConcentrationList) Between(from time.Time, to time.Time) *ConcentrationList {
+	if l == nil {
+		return nil
+	}
+	res := make(ConcentrationList, 0, len(*l))
+	var item Concentration
+	for _, item = range *l {
+		if !item.D.Before(from) && !item.D.After(to) {
+			res = append(res, item)
+		}
+	}
+	return &res
+}
+
+func (l *ConcentrationList) MinTime() *time.Time {
+	if l == nil || len(*l) == 0 {
+		return nil
+	}
+	min := (*l)[0].D
+	var item Concentration
+	for _, item = range *l {
+		if item.D.Before(min) {
+			min = item.D
+		}
+	}
+	return &min
+}
+
 func (l *ConcentrationList) MaxTime() *time.Time {
 	if l == nil || len(*l) == 0 {
 		return nil
@@ -41,3 +73,35 @@ func (l *ConcentrationList) MaxTime() *time.Time {
 }
 
 type ConcentrationMap map[uint]ConcentrationList
+
+// LastNotAfter returns the item of the latest day which is not after the t; it returns nil if there is no such item.
+func (l *ConcentrationList) LastNotAfter(t time.Time) *Concentration {
+	if l == nil {
+		return nil
+	}
+	var res *Concentration
+	for i := range *l {
+		if (*l)[i].D.After(t) {
+			continue
+		}
+		if res == nil || (*l)[i].D.After(res.D) {
+			res = &(*l)[i]
+		}
+	}
+	return res
+}
+
+// Series returns the series of the value (whales, investors or retail) by the day.
+func (l *ConcentrationList) Series(value func(e *Concentration) float64) trend.Series {
+	if l == nil {
+		return nil
+	}
+	res := make(trend.Series, 0, len(*l))
+	for i := range *l {
+		res = append(res, trend.Point{
+			T: (*l)[i].D,
+			V: value(&(*l)[i]),
+		})
+	}
+	return res
+}
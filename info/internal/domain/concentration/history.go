@@ -0,0 +1,178 @@
+package concentration
+
+import (
+	"context"
+	"fmt"
+	"info/internal/pkg/apperror"
+	"math"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Resample_Day   = "day"
+	Resample_Week  = "week"
+	Resample_Month = "month"
+
+	defaultHistoryPeriod = time.Hour * 24 * 90
+)
+
+var ResampleList = []interface{}{
+	Resample_Day,
+	Resample_Week,
+	Resample_Month,
+}
+
+// Shares is the percentage of the holdings of every cohort
+type Shares struct {
+	Whales    float64
+	Investors float64
+	Retail    float64
+}
+
+// Shares returns the normalized cohort shares in percents; all the shares are zero if there are no holdings.
+func (e *Concentration) Shares() Shares {
+	total := e.Whales + e.Investors + e.Retail
+	if total == 0 {
+		return Shares{}
+	}
+	return Shares{
+		Whales:    round(e.Whales * 100 / total),
+		Investors: round(e.Investors * 100 / total),
+		Retail:    round(e.Retail * 100 / total),
+	}
+}
+
+// HistoryPoint is the concentration at the day with the change since the previous point
+type HistoryPoint struct {
+	D         time.Time
+	Whales    float64
+	Investors float64
+	Retail    float64
+	Shares    Shares
+	Delta     *HistoryDelta
+}
+
+// HistoryDelta is the change of the raw values and of the shares (in percentage points); it is nil for the first point.
+type HistoryDelta struct {
+	Whales    float64
+	Investors float64
+	Retail    float64
+	Shares    Shares
+}
+
+type HistoryPointList []HistoryPoint
+
+type HistoryParams struct {
+	CurrencyID uint
+	From       time.Time
+	To         time.Time
+	Resample   string
+}
+
+// SetDefaults sets the empty To to today, the empty From to 90 days before To and the empty Resample to day.
+func (e *HistoryParams) SetDefaults() {
+	if e.To.IsZero() {
+		e.To = time.Now().UTC()
+	}
+	if e.From.IsZero() {
+		e.From = e.To.Add(-defaultHistoryPeriod)
+	}
+	if e.Resample == "" {
+		e.Resample = Resample_Day
+	}
+}
+
+func (e *HistoryParams) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.CurrencyID, validation.Required),
+		validation.Field(&e.From, validation.Required),
+		validation.Field(&e.To, validation.Required, validation.Min(e.From)),
+		validation.Field(&e.Resample, validation.Required, validation.In(ResampleList...)),
+	)
+}
+
+// History returns the concentration of the currency with the normalized shares and the deltas, from the oldest to the newest day.
+func (s *Service) History(ctx context.Context, params *HistoryParams) (*HistoryPointList, error) {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] concentration history params error: %w", apperror.ErrBadRequest, err)
+	}
+
+	l, err := s.replicaSet.ReadRepo().GetBetween(ctx, params.CurrencyID, params.From, params.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.Resample(params.Resample).History(), nil
+}
+
+// Resample returns the last item of every week or month; the list must be sorted by the day ascending.
+func (l *ConcentrationList) Resample(resample string) *ConcentrationList {
+	if l == nil || resample == Resample_Day {
+		return l
+	}
+	res := make(ConcentrationList, 0, len(*l))
+	var item Concentration
+	for _, item = range *l {
+		if len(res) > 0 && periodStart(res[len(res)-1].D, resample).Equal(periodStart(item.D, resample)) {
+			res[len(res)-1] = item
+			continue
+		}
+		res = append(res, item)
+	}
+	return &res
+}
+
+// History returns the points with the shares and the deltas to the previous item; the list must be sorted by the day ascending.
+func (l *ConcentrationList) History() *HistoryPointList {
+	if l == nil {
+		return &HistoryPointList{}
+	}
+	res := make(HistoryPointList, 0, len(*l))
+	var point HistoryPoint
+	var prev *HistoryPoint
+	var item Concentration
+	for _, item = range *l {
+		point = HistoryPoint{
+			D:         item.D,
+			Whales:    item.Whales,
+			Investors: item.Investors,
+			Retail:    item.Retail,
+			Shares:    item.Shares(),
+		}
+		if prev != nil {
+			point.Delta = &HistoryDelta{
+				Whales:    point.Whales - prev.Whales,
+				Investors: point.Investors - prev.Investors,
+				Retail:    point.Retail - prev.Retail,
+				Shares: Shares{
+					Whales:    round(point.Shares.Whales - prev.Shares.Whales),
+					Investors: round(point.Shares.Investors - prev.Shares.Investors),
+					Retail:    round(point.Shares.Retail - prev.Shares.Retail),
+				},
+			}
+		}
+		res = append(res, point)
+		prev = &res[len(res)-1]
+	}
+	return &res
+}
+
+// periodStart returns the first day of the week (Monday) or of the month of d.
+func periodStart(d time.Time, resample string) time.Time {
+	y, m, day := d.Date()
+	switch resample {
+	case Resample_Week:
+		weekday := (int(d.Weekday()) + 6) % 7
+		return time.Date(y, m, day-weekday, 0, 0, 0, 0, d.Location())
+	case Resample_Month:
+		return time.Date(y, m, 1, 0, 0, 0, 0, d.Location())
+	}
+	return time.Date(y, m, day, 0, 0, 0, 0, d.Location())
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
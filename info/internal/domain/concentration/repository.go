@@ -3,6 +3,7 @@ package concentration
 import (
 	"context"
 	"info/internal/domain"
+	"time"
 )
 
 type ReplicaSet interface {
@@ -16,5 +17,8 @@ type WriteRepository interface {
 }
 
 type ReadRepository interface {
+	GetLast(ctx context.Context, currencyID uint) (*Concentration, error)
 	MGet(ctx context.Context, currencyIDs *[]uint) (ConcentrationMap, error)
+	MGetBetween(ctx context.Context, currencyIDs *[]uint, from time.Time, to time.Time) (ConcentrationMap, error)
+	GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*ConcentrationList, error)
 }
@@ -0,0 +1,201 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain"
+	"info/internal/domain/concentration"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"runtime/debug"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Dataset_PriceAndCap   = "price_and_cap"
+	Dataset_Concentration = "concentration"
+	Dataset_All           = "all"
+)
+
+var DatasetList = []interface{}{
+	Dataset_PriceAndCap,
+	Dataset_Concentration,
+	Dataset_All,
+}
+
+type BackfillParams struct {
+	Slugs   []string
+	IDs     []uint
+	From    time.Time
+	To      time.Time
+	Dataset string
+}
+
+func (e *BackfillParams) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.From, validation.Required),
+		validation.Field(&e.To, validation.Required, validation.Min(e.From)),
+		validation.Field(&e.Dataset, validation.Required, validation.In(DatasetList...)),
+	)
+}
+
+func (e *BackfillParams) isDataset(dataset string) bool {
+	return e.Dataset == Dataset_All || e.Dataset == dataset
+}
+
+// BackfillCall is a planned call of the upstream API
+type BackfillCall struct {
+	CurrencyID uint
+	Slug       string
+	Dataset    string
+	TimeRange  string
+	From       time.Time
+	To         time.Time
+	RowsNb     int
+}
+
+type BackfillPlan []BackfillCall
+
+// PlanBackfill returns the calls of the upstream API needed to backfill the window.
+func (s *Service) PlanBackfill(ctx context.Context, params *BackfillParams) (*BackfillPlan, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] backfill params error: %w", apperror.ErrBadRequest, err)
+	}
+
+	currencyList, err := s.MGetBySlugsAndIDs(ctx, &params.Slugs, &params.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make(BackfillPlan, 0, len(*currencyList)*2)
+	var currency Currency
+	for _, currency = range *currencyList {
+		if params.isDataset(Dataset_PriceAndCap) {
+			plan = append(plan, BackfillCall{
+				CurrencyID: currency.ID,
+				Slug:       currency.Slug,
+				Dataset:    Dataset_PriceAndCap,
+				TimeRange:  price_and_cap.TimeRangeFor(params.From),
+				From:       params.From,
+				To:         params.To,
+			})
+		}
+		if params.isDataset(Dataset_Concentration) {
+			plan = append(plan, BackfillCall{
+				CurrencyID: currency.ID,
+				Slug:       currency.Slug,
+				Dataset:    Dataset_Concentration,
+				TimeRange:  concentration.TimeRangeFor(params.From),
+				From:       params.From,
+				To:         params.To,
+			})
+		}
+	}
+	return &plan, nil
+}
+
+// Backfill re-fetches the window of the datasets and upserts it. Every currency is backfilled in its own transaction,
+// its import times are moved forward if the backfilled data is newer. A failed currency does not stop the backfill.
+func (s *Service) Backfill(ctx context.Context, plan *BackfillPlan) (err error) {
+	const metricName = "currency.Service.Backfill"
+	if plan == nil || len(*plan) == 0 {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	importErr := domain.NewImportError(len(*plan))
+	var call BackfillCall
+	var i, j int
+	for i = 0; i < len(*plan); i = j {
+		// вызовы одной валюты идут подряд
+		for j = i + 1; j < len(*plan) && (*plan)[j].CurrencyID == (*plan)[i].CurrencyID; j++ {
+		}
+		call = (*plan)[i]
+		if err = ctx.Err(); err == nil {
+			err = s.backfillCurrency(ctx, (*plan)[i:j])
+		}
+		if err != nil {
+			importErr.AddFailure(call.Slug, err)
+			continue
+		}
+		importErr.AddSuccess(call.Slug)
+	}
+
+	return importErr.Err()
+}
+
+func (s *Service) backfillCurrency(ctx context.Context, calls BackfillPlan) (err error) {
+	const metricName = "currency.Service.backfillCurrency"
+	var tx domain.Tx
+	currencyID := calls[0].CurrencyID
+
+	tx, err = s.replicaSet.WriteRepo().Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+
+		if err == nil {
+			if err = tx.Commit(ctx); err == nil {
+				return
+			}
+			err = fmt.Errorf("[%w] "+metricName+" Commit error: %w", apperror.ErrInternal, err)
+		}
+
+		if err2 := tx.Rollback(ctx); err2 != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Rollback error: %w", apperror.ErrInternal, err2))
+		}
+	}()
+
+	importMaxTimeItem := ImportMaxTime{
+		CurrencyID: currencyID,
+	}
+	importMaxTimeMap, err := s.replicaSet.WriteRepo().GetImportMaxTimeForUpdateTx(ctx, tx, &[]uint{currencyID})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+		err = nil
+	} else if item, ok := importMaxTimeMap[currencyID]; ok {
+		importMaxTimeItem = item
+	}
+
+	var maxTime *time.Time
+	for i := range calls {
+		switch calls[i].Dataset {
+		case Dataset_PriceAndCap:
+			if maxTime, calls[i].RowsNb, err = s.priceAndCap.BackfillTx(ctx, tx, currencyID, calls[i].TimeRange, calls[i].From, calls[i].To); err != nil {
+				return err
+			}
+			importMaxTimeItem.PriceAndCap = reconcileImportMaxTime(importMaxTimeItem.PriceAndCap, maxTime)
+		case Dataset_Concentration:
+			if maxTime, calls[i].RowsNb, err = s.concentration.BackfillTx(ctx, tx, currencyID, calls[i].TimeRange, calls[i].From, calls[i].To); err != nil {
+				return err
+			}
+			importMaxTimeItem.Concentration = reconcileImportMaxTime(importMaxTimeItem.Concentration, maxTime)
+		}
+	}
+
+	return s.replicaSet.WriteRepo().MUpsertImportMaxTimeTx(ctx, tx, &[]ImportMaxTime{importMaxTimeItem})
+}
+
+// reconcileImportMaxTime moves the import time forward only. A never imported dataset stays never imported,
+// so the regular import still fetches its full history.
+func reconcileImportMaxTime(importMaxTime *time.Time, backfillMaxTime *time.Time) *time.Time {
+	if importMaxTime == nil || backfillMaxTime == nil || !backfillMaxTime.After(*importMaxTime) {
+		return importMaxTime
+	}
+	return backfillMaxTime
+}
@@ -0,0 +1,172 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain/concentration"
+	"info/internal/domain/oracul_analytics"
+	"info/internal/domain/oracul_holder_stats"
+	"info/internal/domain/oracul_speedometers"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/trend"
+	"runtime/debug"
+	"sync"
+)
+
+const (
+	Report_WhaleBiggestFall = "whale-biggest-fall"
+	Report_WhaleLongestFall = "whale-longest-fall"
+	Report_WhaleBiggestRise = "whale-biggest-rise"
+	Report_WhaleLongestRise = "whale-longest-rise"
+)
+
+// Detail is everything known about the currency; the parts without the data are nil.
+type Detail struct {
+	Currency        Currency
+	TokenAddresses  TokenAddressList
+	PriceAndCap     *price_and_cap.PriceAndCap
+	Concentration   *concentration.Concentration
+	Shares          *concentration.Shares
+	OraculAnalytics *oracul_analytics.OraculAnalytics
+	Speedometers    *oracul_speedometers.OraculSpeedometers
+	HolderStats     *oracul_holder_stats.OraculHolderStats
+	WhaleFall       *WhaleTrend
+	WhaleRise       *WhaleTrend
+	ReportPositions []ReportPosition
+}
+
+// ReportPosition is the place of the currency in the report with the default params; Position starts from 1.
+type ReportPosition struct {
+	Report   string
+	Position uint
+	TotalNb  uint
+}
+
+// Detail returns the currency (by ID or slug) with all its latest data; the data are read in parallel.
+func (s *Service) Detail(ctx context.Context, key string) (*Detail, error) {
+	item, err := s.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	res := &Detail{
+		Currency:        *item,
+		TokenAddresses:  TokenAddressList{},
+		ReportPositions: []ReportPosition{},
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	run := func(name string, f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := func() (err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("[%w] currency.Service.Detail %s Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, name, r, string(debug.Stack()))
+					}
+				}()
+				return f()
+			}()
+			// отсутствие данных у монеты - не ошибка, эта часть просто останется пустой
+			if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	run("TokenAddresses", func() error {
+		l, err := s.replicaSet.ReadRepo().MGetTokenAddress(ctx, &[]uint{item.ID})
+		if err == nil {
+			res.TokenAddresses = *l
+		}
+		return err
+	})
+	run("PriceAndCap", func() (err error) {
+		res.PriceAndCap, err = s.priceAndCap.GetLast(ctx, item.ID)
+		return err
+	})
+	run("Concentration", func() (err error) {
+		if res.Concentration, err = s.concentration.GetLast(ctx, item.ID); err == nil {
+			shares := res.Concentration.Shares()
+			res.Shares = &shares
+		}
+		return err
+	})
+	run("OraculAnalytics", func() (err error) {
+		res.OraculAnalytics, err = s.oraculAnalytics.GetLast(ctx, item.ID)
+		return err
+	})
+	run("Speedometers", func() (err error) {
+		res.Speedometers, err = s.oraculAnalytics.GetLastSpeedometers(ctx, item.ID)
+		return err
+	})
+	run("HolderStats", func() (err error) {
+		res.HolderStats, err = s.oraculAnalytics.GetLastHolderStats(ctx, item.ID)
+		return err
+	})
+	var positions []ReportPosition
+	run("WhaleTrends", func() (err error) {
+		res.WhaleFall, res.WhaleRise, positions, err = s.whaleTrendPositions(ctx, item.ID)
+		return err
+	})
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	res.ReportPositions = append(res.ReportPositions, positions...)
+	return res, nil
+}
+
+// whaleTrendPositions returns the fall and the rise of the currency and its positions in the whale trend reports with the default params.
+// The data of the reports are read once for both directions.
+func (s *Service) whaleTrendPositions(ctx context.Context, currencyID uint) (fall *WhaleTrend, rise *WhaleTrend, positions []ReportPosition, err error) {
+	params := &WhaleTrendParams{
+		Sort: WhaleTrendSort_Value,
+	}
+	params.SetDefaults()
+
+	data, err := s.getWhaleTrendData(ctx, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	positions = make([]ReportPosition, 0, 4)
+	fall, positions = reportPositions(data.calc(s, trend.Direction_Fall, params), currencyID, Report_WhaleBiggestFall, Report_WhaleLongestFall, positions)
+	rise, positions = reportPositions(data.calc(s, trend.Direction_Rise, params), currencyID, Report_WhaleBiggestRise, Report_WhaleLongestRise, positions)
+	return fall, rise, positions, nil
+}
+
+// reportPositions returns the trend of the currency and appends its positions in the biggest and in the longest reports of the list.
+func reportPositions(l *WhaleTrendList, currencyID uint, biggestReport string, longestReport string, positions []ReportPosition) (*WhaleTrend, []ReportPosition) {
+	var item *WhaleTrend
+	for _, report := range []struct {
+		name string
+		sort string
+	}{
+		{name: biggestReport, sort: WhaleTrendSort_Value},
+		{name: longestReport, sort: WhaleTrendSort_Duration},
+	} {
+		l.Sort(report.sort, Order_Desc)
+		for i := range *l {
+			if (*l)[i].CurrencyID != currencyID {
+				continue
+			}
+			trendItem := (*l)[i]
+			item = &trendItem
+			positions = append(positions, ReportPosition{
+				Report:   report.name,
+				Position: uint(i + 1),
+				TotalNb:  uint(len(*l)),
+			})
+			break
+		}
+	}
+	return item, positions
+}
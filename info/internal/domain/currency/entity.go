@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"info/internal/pkg/apperror"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -16,6 +18,17 @@ type ImportMaxTime struct {
 	Concentration *time.Time
 }
 
+// OldestTime returns the oldest of the import times; the zero time means that something has never been imported.
+func (e *ImportMaxTime) OldestTime() time.Time {
+	if e.PriceAndCap == nil || e.Concentration == nil {
+		return time.Time{}
+	}
+	if e.PriceAndCap.Before(*e.Concentration) {
+		return *e.PriceAndCap
+	}
+	return *e.Concentration
+}
+
 type Currency struct {
 	ID                            uint
 	Symbol                        string
@@ -37,6 +50,15 @@ func (e *Currency) Validate() error {
 	return nil
 }
 
+// MarketCap returns the cap by the bigger of the circulating supplies and the latest price.
+func (e *Currency) MarketCap() float64 {
+	supply := e.CirculatingSupply
+	if e.SelfReportedCirculatingSupply > supply {
+		supply = e.SelfReportedCirculatingSupply
+	}
+	return supply * e.LatestPrice
+}
+
 type CurrencyList []Currency
 
 func (l *CurrencyList) IDs() *[]uint {
@@ -51,6 +73,31 @@ func (l *CurrencyList) IDs() *[]uint {
 	return &res
 }
 
+func (l *CurrencyList) Slugs() *[]string {
+	if l == nil {
+		return nil
+	}
+	res := make([]string, 0, len(*l))
+	var item Currency
+	for _, item = range *l {
+		res = append(res, item.Slug)
+	}
+	return &res
+}
+
+// SortByImportMaxTime sorts the list so that the currencies with the oldest import times go first.
+func (l *CurrencyList) SortByImportMaxTime(importMaxTimeMap map[uint]ImportMaxTime) *CurrencyList {
+	if l == nil {
+		return nil
+	}
+	slices.SortStableFunc(*l, func(a, b Currency) int {
+		aMaxTime := importMaxTimeMap[a.ID]
+		bMaxTime := importMaxTimeMap[b.ID]
+		return aMaxTime.OldestTime().Compare(bMaxTime.OldestTime())
+	})
+	return l
+}
+
 type CurrencyMap map[uint]Currency
 
 func (m CurrencyMap) List() *CurrencyList {
@@ -104,3 +151,57 @@ type TokenAddress struct {
 }
 
 type TokenAddressList []TokenAddress
+
+// SortByPriority sorts the addresses by the currency; the address on the main platform of the currency goes first,
+// the other ones go by the chain and the address, so the choice of the first address does not depend on the order of the source.
+func (l *TokenAddressList) SortByPriority(currencyList *CurrencyList) *TokenAddressList {
+	if l == nil {
+		return nil
+	}
+	mainPlatforms := make(map[uint]TokenAddress, len(*currencyList))
+	var item Currency
+	for _, item = range *currencyList {
+		if item.Platform != nil {
+			mainPlatforms[item.ID] = TokenAddress{CurrencyID: item.ID, Blockchain: item.Platform.Symbol, Address: item.Platform.TokenAddress}
+		}
+	}
+	slices.SortStableFunc(*l, func(a, b TokenAddress) int {
+		if a.CurrencyID != b.CurrencyID {
+			if a.CurrencyID < b.CurrencyID {
+				return -1
+			}
+			return 1
+		}
+		aIsMain, bIsMain := a == mainPlatforms[a.CurrencyID], b == mainPlatforms[b.CurrencyID]
+		switch {
+		case aIsMain && !bIsMain:
+			return -1
+		case !aIsMain && bIsMain:
+			return 1
+		}
+		if a.Blockchain != b.Blockchain {
+			return strings.Compare(a.Blockchain, b.Blockchain)
+		}
+		return strings.Compare(a.Address, b.Address)
+	})
+	return l
+}
+
+// Unique returns the list without the duplicates.
+func (l *TokenAddressList) Unique() *TokenAddressList {
+	if l == nil {
+		return nil
+	}
+	res := make(TokenAddressList, 0, len(*l))
+	exists := make(map[TokenAddress]struct{}, len(*l))
+	var ok bool
+	var item TokenAddress
+	for _, item = range *l {
+		if _, ok = exists[item]; ok {
+			continue
+		}
+		exists[item] = struct{}{}
+		res = append(res, item)
+	}
+	return &res
+}
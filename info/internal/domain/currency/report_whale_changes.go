@@ -1,12 +1,66 @@
 package currency
 
 import (
+	"cmp"
+	"info/internal/pkg/trend"
 	"slices"
+	"strings"
 	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	DefaultWhaleTrendPeriodDays = 61 // Максимальный период времени, который смотрим
+	DefaultWhaleTrendBreakDays  = 5  // Максимальный перерыв в тренде
+	MaxWhaleTrendPeriodDays     = 3650
+
+	WhaleTrendSort_Value    = "Value"
+	WhaleTrendSort_Duration = "Duration"
+
+	Order_Asc  = "asc"
+	Order_Desc = "desc"
 )
 
+var OrderList = []interface{}{
+	Order_Asc,
+	Order_Desc,
+}
+
+// WhaleTrend is the latest rise or fall of the whales holdings of the currency with the change of the cap and of the price.
+// Value, Cap and Price are the changes in the direction of the trend: they are positive if they go the same way as the whales.
+// The percents are the To values in percents of the From values.
+type WhaleTrend struct {
+	CurrencyID   uint
+	Symbol       string
+	CmcRank      uint
+	MarketCap    float64
+	Direction    string
+	Duration     time.Duration
+	DayFrom      time.Time
+	DayTo        time.Time
+	Value        float64
+	ValueFrom    float64
+	ValueTo      float64
+	ValuePercent float64
+	Cap          float64
+	CapFrom      float64
+	CapTo        float64
+	CapPercent   float64
+	Price        float64
+	PriceFrom    float64
+	PriceTo      float64
+	PricePercent float64
+}
+
+type WhaleTrendList []WhaleTrend
+
+// WhaleFall is the fall of the whales holdings in the fields of the fall reports, which are kept for their clients.
 type WhaleFall struct {
+	CurrencyID       uint
 	Symbol           string
+	CmcRank          uint
+	MarketCap        float64
 	FallDuration     time.Duration
 	DayFrom          time.Time
 	DayTo            time.Time
@@ -26,47 +80,201 @@ type WhaleFall struct {
 
 type WhaleFallList []WhaleFall
 
-func (l *WhaleFallList) SortByFallValueDesc() *WhaleFallList {
+func WhaleTrend2WhaleFall(e *WhaleTrend) *WhaleFall {
+	return &WhaleFall{
+		CurrencyID:       e.CurrencyID,
+		Symbol:           e.Symbol,
+		CmcRank:          e.CmcRank,
+		MarketCap:        e.MarketCap,
+		FallDuration:     e.Duration,
+		DayFrom:          e.DayFrom,
+		DayTo:            e.DayTo,
+		FallValue:        e.Value,
+		ValueFrom:        e.ValueFrom,
+		ValueTo:          e.ValueTo,
+		FallValuePercent: e.ValuePercent,
+		FallCap:          e.Cap,
+		CapFrom:          e.CapFrom,
+		CapTo:            e.CapTo,
+		FallCapPercent:   e.CapPercent,
+		FallPrice:        e.Price,
+		PriceFrom:        e.PriceFrom,
+		PriceTo:          e.PriceTo,
+		FallPricePercent: e.PricePercent,
+	}
+}
+
+func WhaleTrendList2WhaleFallList(l *WhaleTrendList) *WhaleFallList {
 	if l == nil {
 		return nil
 	}
-	slices.SortFunc(*l, func(a, b WhaleFall) int {
-		switch {
-		case a.FallValue < b.FallValue:
-			return 1
-		case a.FallValue > b.FallValue:
-			return -1
-		default:
-			return 0
+	res := make(WhaleFallList, 0, len(*l))
+	var item WhaleTrend
+	for _, item = range *l {
+		res = append(res, *WhaleTrend2WhaleFall(&item))
+	}
+	return &res
+}
+
+// whaleTrendCompareMap is the comparison of the items by the field of WhaleTrend, it is used for the sort param
+var whaleTrendCompareMap = map[string]func(a, b *WhaleTrend) int{
+	"Symbol":       func(a, b *WhaleTrend) int { return cmp.Compare(a.Symbol, b.Symbol) },
+	"CmcRank":      func(a, b *WhaleTrend) int { return cmp.Compare(a.CmcRank, b.CmcRank) },
+	"MarketCap":    func(a, b *WhaleTrend) int { return cmp.Compare(a.MarketCap, b.MarketCap) },
+	"Duration":     func(a, b *WhaleTrend) int { return cmp.Compare(a.Duration, b.Duration) },
+	"DayFrom":      func(a, b *WhaleTrend) int { return a.DayFrom.Compare(b.DayFrom) },
+	"DayTo":        func(a, b *WhaleTrend) int { return a.DayTo.Compare(b.DayTo) },
+	"Value":        func(a, b *WhaleTrend) int { return cmp.Compare(a.Value, b.Value) },
+	"ValueFrom":    func(a, b *WhaleTrend) int { return cmp.Compare(a.ValueFrom, b.ValueFrom) },
+	"ValueTo":      func(a, b *WhaleTrend) int { return cmp.Compare(a.ValueTo, b.ValueTo) },
+	"ValuePercent": func(a, b *WhaleTrend) int { return cmp.Compare(a.ValuePercent, b.ValuePercent) },
+	"Cap":          func(a, b *WhaleTrend) int { return cmp.Compare(a.Cap, b.Cap) },
+	"CapFrom":      func(a, b *WhaleTrend) int { return cmp.Compare(a.CapFrom, b.CapFrom) },
+	"CapTo":        func(a, b *WhaleTrend) int { return cmp.Compare(a.CapTo, b.CapTo) },
+	"CapPercent":   func(a, b *WhaleTrend) int { return cmp.Compare(a.CapPercent, b.CapPercent) },
+	"Price":        func(a, b *WhaleTrend) int { return cmp.Compare(a.Price, b.Price) },
+	"PriceFrom":    func(a, b *WhaleTrend) int { return cmp.Compare(a.PriceFrom, b.PriceFrom) },
+	"PriceTo":      func(a, b *WhaleTrend) int { return cmp.Compare(a.PriceTo, b.PriceTo) },
+	"PricePercent": func(a, b *WhaleTrend) int { return cmp.Compare(a.PricePercent, b.PricePercent) },
+}
+
+// whaleFallSortFieldMap is the fields of WhaleFall which are named differently in WhaleTrend
+var whaleFallSortFieldMap = map[string]string{
+	"FallDuration":     "Duration",
+	"FallValue":        "Value",
+	"FallValuePercent": "ValuePercent",
+	"FallCap":          "Cap",
+	"FallCapPercent":   "CapPercent",
+	"FallPrice":        "Price",
+	"FallPricePercent": "PricePercent",
+}
+
+// WhaleTrendSortField returns the field of WhaleTrend by the name in any case, the names of the fields of WhaleFall are accepted too;
+// it returns the empty string if there is no such field.
+func WhaleTrendSortField(name string) string {
+	var field, trendField string
+	for field = range whaleTrendCompareMap {
+		if strings.EqualFold(field, name) {
+			return field
+		}
+	}
+	for field, trendField = range whaleFallSortFieldMap {
+		if strings.EqualFold(field, name) {
+			return trendField
 		}
-	})
-	return l
+	}
+	return ""
+}
+
+type WhaleTrendParams struct {
+	PeriodDays      uint
+	BreakDays       uint
+	MinMagnitude    float64 // the min change of the whales in percents
+	MinMarketCap    float64
+	MinRank         uint
+	MaxRank         uint
+	From            time.Time // the data before From are not looked at
+	To              time.Time // the trends are looked for back from To; it is now by default
+	WithNotObserved bool
+	Sort            string // the field of WhaleTrend
+	Order           string
+	Limit           uint
+	Offset          uint
+}
+
+// SetDefaults sets the empty PeriodDays and BreakDays to the default ones, the empty To to now and the empty Order to desc.
+func (e *WhaleTrendParams) SetDefaults() {
+	if e.PeriodDays == 0 {
+		e.PeriodDays = DefaultWhaleTrendPeriodDays
+	}
+	if e.BreakDays == 0 {
+		e.BreakDays = DefaultWhaleTrendBreakDays
+	}
+	if e.To.IsZero() {
+		e.To = time.Now().UTC()
+	}
+	if field := WhaleTrendSortField(e.Sort); field != "" {
+		e.Sort = field
+	}
+	if e.Order == "" {
+		e.Order = Order_Desc
+	}
+}
+
+func (e *WhaleTrendParams) Validate() error {
+	fields := make([]interface{}, 0, len(whaleTrendCompareMap))
+	var field string
+	for field = range whaleTrendCompareMap {
+		fields = append(fields, field)
+	}
+	return validation.ValidateStruct(e,
+		validation.Field(&e.PeriodDays, validation.Required, validation.Max(uint(MaxWhaleTrendPeriodDays))),
+		validation.Field(&e.BreakDays, validation.Required, validation.Max(e.PeriodDays)),
+		validation.Field(&e.MinMagnitude, validation.Min(0.0)),
+		validation.Field(&e.MinMarketCap, validation.Min(0.0)),
+		validation.Field(&e.MaxRank, validation.When(e.MaxRank != 0, validation.Min(e.MinRank))),
+		validation.Field(&e.To, validation.Required, validation.Min(e.From)),
+		validation.Field(&e.Sort, validation.Required, validation.In(fields...)),
+		validation.Field(&e.Order, validation.Required, validation.In(OrderList...)),
+	)
+}
+
+// Config returns the config of the trend detection.
+func (e *WhaleTrendParams) Config() trend.Config {
+	return trend.Config{
+		Period:       time.Hour * 24 * time.Duration(e.PeriodDays),
+		MaxBreak:     time.Hour * 24 * time.Duration(e.BreakDays),
+		MinMagnitude: e.MinMagnitude,
+	}
+}
+
+// IsMatched returns true if the currency passes the cap and the rank filters.
+func (e *WhaleTrendParams) IsMatched(currency *Currency) bool {
+	if e.MinMarketCap > 0 && currency.MarketCap() < e.MinMarketCap {
+		return false
+	}
+	if e.MinRank > 0 && currency.CmcRank < e.MinRank {
+		return false
+	}
+	if e.MaxRank > 0 && currency.CmcRank > e.MaxRank {
+		return false
+	}
+	return true
 }
 
-func (l *WhaleFallList) SortByFallDurationDesc() *WhaleFallList {
+// Sort sorts the list by the field of WhaleTrend; the items with the equal fields are sorted by the symbol.
+func (l *WhaleTrendList) Sort(field string, order string) *WhaleTrendList {
 	if l == nil {
 		return nil
 	}
-	slices.SortFunc(*l, func(a, b WhaleFall) int {
-		switch {
-		case a.FallDuration < b.FallDuration:
-			return 1
-		case a.FallDuration > b.FallDuration:
-			return -1
-		default:
-			return 0
+	compare, ok := whaleTrendCompareMap[field]
+	if !ok {
+		return l
+	}
+	slices.SortFunc(*l, func(a, b WhaleTrend) int {
+		res := compare(&a, &b)
+		if order == Order_Desc {
+			res = -res
+		}
+		if res == 0 {
+			res = cmp.Compare(a.Symbol, b.Symbol)
 		}
+		return res
 	})
 	return l
 }
 
-func (l *WhaleFallList) Limit(limit uint) *WhaleFallList {
+// Page returns the items from offset, not more than limit.
+func (l *WhaleTrendList) Page(limit uint, offset uint) *WhaleTrendList {
 	if l == nil {
 		return nil
 	}
-	if uint(len(*l)) < limit {
-		limit = uint(len(*l))
+	if uint(len(*l)) < offset {
+		offset = uint(len(*l))
+	}
+	newList := (*l)[offset:]
+	if uint(len(newList)) > limit {
+		newList = newList[:limit]
 	}
-	newList := (*l)[:limit]
 	return &newList
 }
@@ -0,0 +1,175 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"info/internal/domain/concentration"
+	"info/internal/pkg/apperror"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	DefaultWhaleMomentumLagDays = 2 // из-за глюков в данных последние дни не берём
+	MaxWhaleMomentumWindowsNb   = 10
+	MaxWhaleMomentumWindowDays  = 3650
+
+	// whaleMomentumBonusFactor is the multiplier of the change in the sort window for the bonus (it is kept as in the old cw1/cw2/cm1 views)
+	whaleMomentumBonusFactor = 1000
+)
+
+// DefaultWhaleMomentumWindows are the windows of the old views: 1 week, 2 weeks, 1 month, 2 months
+var DefaultWhaleMomentumWindows = []uint{7, 14, 30, 60}
+
+// WhaleMomentum is the change of the whales holdings of the currency in percents over the lookback windows.
+type WhaleMomentum struct {
+	CurrencyID uint
+	Symbol     string
+	D          time.Time // the day of the current value
+	Bonus      float64
+	Changes    map[string]float64 // the change by the window label (e.g. "7d")
+}
+
+type WhaleMomentumList []WhaleMomentum
+
+type WhaleMomentumParams struct {
+	Windows    []uint // lookback windows in days
+	SortWindow uint   // the window (in days) which the report is sorted by ascending; the first window by default
+	LagDays    *uint  // the current value is taken not later than LagDays ago; nil is the default, 0 is today
+	Limit      uint
+}
+
+// SetDefaults sets the empty Windows and SortWindow and the nil LagDays to the ones of the old views.
+func (e *WhaleMomentumParams) SetDefaults() {
+	if len(e.Windows) == 0 {
+		e.Windows = slices.Clone(DefaultWhaleMomentumWindows)
+	}
+	if e.SortWindow == 0 {
+		e.SortWindow = e.Windows[0]
+	}
+	if e.LagDays == nil {
+		lagDays := uint(DefaultWhaleMomentumLagDays)
+		e.LagDays = &lagDays
+	}
+}
+
+func (e *WhaleMomentumParams) Validate() error {
+	windows := make([]interface{}, 0, len(e.Windows))
+	var window uint
+	for _, window = range e.Windows {
+		windows = append(windows, window)
+	}
+	return validation.ValidateStruct(e,
+		validation.Field(&e.Windows, validation.Required, validation.Length(1, MaxWhaleMomentumWindowsNb), validation.Each(validation.Required, validation.Max(uint(MaxWhaleMomentumWindowDays)))),
+		validation.Field(&e.SortWindow, validation.Required, validation.In(windows...)),
+		validation.Field(&e.LagDays, validation.Max(uint(MaxWhaleMomentumWindowDays))),
+	)
+}
+
+// WhaleMomentumWindowLabel returns the label of the window which is used as the key of WhaleMomentum.Changes.
+func WhaleMomentumWindowLabel(days uint) string {
+	return strconv.FormatUint(uint64(days), 10) + "d"
+}
+
+// Report_WhaleMomentum returns the changes of the whales holdings of the observed currencies over the windows.
+// Only the currencies having the data for all the windows are in the report.
+// The report is sorted by the change in the sort window ascending and then by the changes in the other windows in their order.
+func (s *Service) Report_WhaleMomentum(ctx context.Context, params *WhaleMomentumParams) (*WhaleMomentumList, error) {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] whale momentum params error: %w", apperror.ErrBadRequest, err)
+	}
+
+	currencyList, err := s.replicaSet.ReadRepo().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	concentrationMap, err := s.concentration.MGet(ctx, currencyList.IDs())
+	if err != nil {
+		return nil, err
+	}
+
+	l := s.calcWhaleMomentumList(currencyList, concentrationMap, params, time.Now().UTC())
+	if params.Limit > 0 && uint(len(*l)) > params.Limit {
+		*l = (*l)[:params.Limit]
+	}
+	return l, nil
+}
+
+func (s *Service) calcWhaleMomentumList(currencyList *CurrencyList, concentrationMap concentration.ConcentrationMap, params *WhaleMomentumParams, now time.Time) *WhaleMomentumList {
+	res := make(WhaleMomentumList, 0, len(*currencyList))
+	if concentrationMap == nil {
+		return &res
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var ok bool
+	var currency Currency
+	var concentrationList concentration.ConcentrationList
+	var item *WhaleMomentum
+	for _, currency = range *currencyList {
+		if concentrationList, ok = concentrationMap[currency.ID]; !ok {
+			continue
+		}
+		if item = calcWhaleMomentum(&currency, &concentrationList, params, today); item != nil {
+			res = append(res, *item)
+		}
+	}
+
+	order := make([]string, 0, len(params.Windows))
+	order = append(order, WhaleMomentumWindowLabel(params.SortWindow))
+	var window uint
+	for _, window = range params.Windows {
+		if window != params.SortWindow {
+			order = append(order, WhaleMomentumWindowLabel(window))
+		}
+	}
+	slices.SortStableFunc(res, func(a, b WhaleMomentum) int {
+		var label string
+		for _, label = range order {
+			switch {
+			case a.Changes[label] < b.Changes[label]:
+				return -1
+			case a.Changes[label] > b.Changes[label]:
+				return 1
+			}
+		}
+		return 0
+	})
+	return &res
+}
+
+// calcWhaleMomentum returns nil if there is no data for some window or the whales are zero at the start of some window.
+func calcWhaleMomentum(currency *Currency, concentrationList *concentration.ConcentrationList, params *WhaleMomentumParams, today time.Time) *WhaleMomentum {
+	current := concentrationList.LastNotAfter(today.AddDate(0, 0, -int(*params.LagDays)))
+	if current == nil {
+		return nil
+	}
+
+	res := &WhaleMomentum{
+		CurrencyID: currency.ID,
+		Symbol:     currency.Symbol,
+		D:          current.D,
+		Changes:    make(map[string]float64, len(params.Windows)),
+	}
+	var window uint
+	var start *concentration.Concentration
+	var change float64
+	for _, window = range params.Windows {
+		start = concentrationList.LastNotAfter(today.AddDate(0, 0, -int(window)))
+		if start == nil || start.Whales == 0 {
+			return nil
+		}
+		change = (current.Whales - start.Whales) * 100 / start.Whales
+		res.Changes[WhaleMomentumWindowLabel(window)] = math.Round(change*10000) / 10000
+		if window == params.SortWindow {
+			res.Bonus = math.Round(change * whaleMomentumBonusFactor)
+		}
+	}
+	return res
+}
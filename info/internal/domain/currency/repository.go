@@ -15,11 +15,17 @@ type WriteRepository interface {
 	GetImportMaxTimeForUpdateTx(ctx context.Context, tx domain.Tx, currencyIDs *[]uint) (map[uint]ImportMaxTime, error)
 	Create(ctx context.Context, entity *Currency) (ID uint, err error)
 	MUpsert(ctx context.Context, entities *CurrencyList) error
+	MUpsertTx(ctx context.Context, tx domain.Tx, entities *CurrencyList) error
+	MSetIsForObserving(ctx context.Context, slugs *[]string, isForObserving bool) (*[]string, error)
+	MSetIsForObservingTx(ctx context.Context, tx domain.Tx, slugs *[]string, isForObserving bool) (*[]string, error)
 	Update(ctx context.Context, entity *Currency) error
 	Delete(ctx context.Context, ID uint) error
 	MCreateImportMaxTime(ctx context.Context, entities *[]ImportMaxTime) error
+	MCreateImportMaxTimeTx(ctx context.Context, tx domain.Tx, entities *[]ImportMaxTime) error
 	MUpsertImportMaxTimeTx(ctx context.Context, tx domain.Tx, entities *[]ImportMaxTime) error
 	MUpsertImportMaxTimeMapTx(ctx context.Context, tx domain.Tx, entities map[uint]ImportMaxTime) error
+	MDeleteTokenAddressTx(ctx context.Context, tx domain.Tx, IDs *[]uint) error
+	MCreateTokenAddressTx(ctx context.Context, tx domain.Tx, entities *TokenAddressList) error
 }
 
 type ReadRepository interface {
@@ -28,5 +34,7 @@ type ReadRepository interface {
 	MGet(ctx context.Context, IDs *[]uint) (*CurrencyList, error)
 	MGetBySlug(ctx context.Context, slugs *[]string) (*CurrencyList, error)
 	GetAll(ctx context.Context) (*CurrencyList, error)
+	GetAllWithNotObserved(ctx context.Context) (*CurrencyList, error)
 	MGetTokenAddress(ctx context.Context, IDs *[]uint) (*TokenAddressList, error)
+	MGetImportMaxTime(ctx context.Context, currencyIDs *[]uint) (map[uint]ImportMaxTime, error)
 }
@@ -2,17 +2,19 @@ package currency
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"info/internal/domain"
 	"info/internal/domain/concentration"
+	"info/internal/domain/import_run"
 	"info/internal/domain/oracul_analytics"
 	"info/internal/domain/price_and_cap"
 	"info/internal/pkg/apperror"
+	"info/internal/pkg/trend"
+	"info/internal/pkg/workerpool"
 	"math"
 	"runtime/debug"
-	"time"
+	"strconv"
 )
 
 const (
@@ -25,6 +27,7 @@ type CmcApi interface {
 
 type CmcProApi interface {
 	GetCurrenciesBySlugs(ctx context.Context, slugs *[]string) (currencyMap CurrencyMap, err error)
+	GetTokenAddresses(ctx context.Context, currencyIDs *[]uint) (*TokenAddressList, error)
 }
 
 type Service struct {
@@ -32,18 +35,22 @@ type Service struct {
 	priceAndCap     *price_and_cap.Service
 	concentration   *concentration.Service
 	oraculAnalytics *oracul_analytics.Service
+	importRun       *import_run.Service
 	cmcApi          CmcApi
 	cmcProApi       CmcProApi
+	concurrency     uint
 }
 
-func NewService(replicaSet ReplicaSet, priceAndCap *price_and_cap.Service, concentration *concentration.Service, oraculAnalytics *oracul_analytics.Service, cmcApi CmcApi, cmcProApi CmcProApi) *Service {
+func NewService(replicaSet ReplicaSet, priceAndCap *price_and_cap.Service, concentration *concentration.Service, oraculAnalytics *oracul_analytics.Service, importRun *import_run.Service, cmcApi CmcApi, cmcProApi CmcProApi, concurrency uint) *Service {
 	return &Service{
 		replicaSet:      replicaSet,
 		priceAndCap:     priceAndCap,
 		concentration:   concentration,
 		oraculAnalytics: oraculAnalytics,
+		importRun:       importRun,
 		cmcApi:          cmcApi,
 		cmcProApi:       cmcProApi,
+		concurrency:     concurrency,
 	}
 }
 
@@ -63,18 +70,135 @@ func (s *Service) Get(ctx context.Context, ID uint) (*Currency, error) {
 	return s.replicaSet.ReadRepo().Get(ctx, ID)
 }
 
+// GetByKey returns the currency by its ID or by its slug.
+func (s *Service) GetByKey(ctx context.Context, key string) (*Currency, error) {
+	if ID, err := strconv.ParseUint(key, 10, 64); err == nil {
+		return s.replicaSet.ReadRepo().Get(ctx, uint(ID))
+	}
+	return s.replicaSet.ReadRepo().GetBySlug(ctx, key)
+}
+
+func (s *Service) MGet(ctx context.Context, IDs *[]uint) (*CurrencyList, error) {
+	return s.replicaSet.ReadRepo().MGet(ctx, IDs)
+}
+
+func (s *Service) MGetBySlug(ctx context.Context, slugs *[]string) (*CurrencyList, error) {
+	return s.replicaSet.ReadRepo().MGetBySlug(ctx, slugs)
+}
+
+// MGetBySlugsAndIDs returns the currencies by the slugs and by the IDs without the duplicates; the unknown ones are skipped.
+// It returns ErrNotFound if none of them is found.
+func (s *Service) MGetBySlugsAndIDs(ctx context.Context, slugs *[]string, IDs *[]uint) (*CurrencyList, error) {
+	res := make(CurrencyList, 0, len(*slugs)+len(*IDs))
+	existsIDs := make(map[uint]struct{}, cap(res))
+	var currency Currency
+
+	appendList := func(l *CurrencyList, err error) error {
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		for _, currency = range *l {
+			if _, ok := existsIDs[currency.ID]; !ok {
+				existsIDs[currency.ID] = struct{}{}
+				res = append(res, currency)
+			}
+		}
+		return nil
+	}
+
+	if len(*slugs) > 0 {
+		if err := appendList(s.replicaSet.ReadRepo().MGetBySlug(ctx, slugs)); err != nil {
+			return nil, err
+		}
+	}
+	if len(*IDs) > 0 {
+		if err := appendList(s.replicaSet.ReadRepo().MGet(ctx, IDs)); err != nil {
+			return nil, err
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("[%w] currencies by the slugs and the IDs", apperror.ErrNotFound)
+	}
+	return &res, nil
+}
+
 func (s *Service) GetAll(ctx context.Context) (*CurrencyList, error) {
 	return s.replicaSet.ReadRepo().GetAll(ctx)
 }
 
+// Import imports the price and the concentration of every currency in its own transaction, starting with the currencies with the oldest import times.
+// The currencies are imported by the pool of concurrency workers.
+// A failed currency does not stop the import: the next run resumes it from its last import time.
 func (s *Service) Import(ctx context.Context, listOfCurrencySlugs *[]string) (err error) {
-	const metricName = "currency.Service.ImportTx"
-	var tx domain.Tx
+	const metricName = "currency.Service.Import"
+
+	journal := s.importRun.Start(ctx, import_run.Kind_Currency)
+	defer func() {
+		err = errors.Join(err, journal.Finish(ctx, err))
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
 
 	currencyList, err := s.baseImport(ctx, listOfCurrencySlugs)
 	if err != nil {
 		return err
 	}
+	if currencyList == nil || len(*currencyList) == 0 {
+		return nil
+	}
+
+	importMaxTimeMap, err := s.replicaSet.ReadRepo().MGetImportMaxTime(ctx, currencyList.IDs())
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+		importMaxTimeMap = make(map[uint]ImportMaxTime)
+	}
+	currencyList.SortByImportMaxTime(importMaxTimeMap)
+
+	importErr := domain.NewImportError(len(*currencyList))
+	workerpool.Run(ctx, s.concurrency, *currencyList, func(ctx context.Context, currency Currency) {
+		runItem := import_run.NewImportRunItem(import_run.Kind_Currency, currency.Slug, &currency.ID)
+		err := s.importWorkerItem(ctx, currency.ID, runItem)
+		journal.AddItem(ctx, runItem, err)
+		if err != nil {
+			importErr.AddFailure(currency.Slug, err)
+			return
+		}
+		importErr.AddSuccess(currency.Slug)
+	})
+
+	return importErr.Err()
+}
+
+// importWorkerItem runs importCurrency in the worker of the pool: the panic is returned as the error of the currency,
+// so it fails this currency only and does not kill the whole import.
+func (s *Service) importWorkerItem(ctx context.Context, currencyID uint, runItem *import_run.ImportRunItem) (err error) {
+	const metricName = "currency.Service.importWorkerItem"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	return s.importCurrency(ctx, currencyID, runItem)
+}
+
+// importCurrency imports the price and the concentration of the currency and updates its import times in one transaction.
+func (s *Service) importCurrency(ctx context.Context, currencyID uint, runItem *import_run.ImportRunItem) (err error) {
+	const metricName = "currency.Service.importCurrency"
+	var tx domain.Tx
 
 	tx, err = s.replicaSet.WriteRepo().Begin(ctx)
 	if err != nil {
@@ -93,58 +217,60 @@ func (s *Service) Import(ctx context.Context, listOfCurrencySlugs *[]string) (er
 			err = fmt.Errorf("[%w] "+metricName+" Commit error: %w", apperror.ErrInternal, err)
 		}
 
-		if tx != nil {
-			if err2 := tx.Rollback(ctx); err2 != nil {
-				err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Rollback error: %w", apperror.ErrInternal, err))
-			}
+		if err2 := tx.Rollback(ctx); err2 != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Rollback error: %w", apperror.ErrInternal, err2))
 		}
-
 	}()
 
-	importMaxTimeMap, err := s.replicaSet.WriteRepo().GetImportMaxTimeForUpdateTx(ctx, tx, currencyList.IDs())
+	importMaxTimeItem := ImportMaxTime{
+		CurrencyID: currencyID,
+	}
+	importMaxTimeMap, err := s.replicaSet.WriteRepo().GetImportMaxTimeForUpdateTx(ctx, tx, &[]uint{currencyID})
 	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+		err = nil
+	} else if item, ok := importMaxTimeMap[currencyID]; ok {
+		importMaxTimeItem = item
+	}
+
+	if importMaxTimeItem.PriceAndCap, err = s.priceAndCap.ImportTx(ctx, tx, currencyID, importMaxTimeItem.PriceAndCap, runItem); err != nil {
+		return err
+	}
+	if importMaxTimeItem.Concentration, err = s.concentration.ImportTx(ctx, tx, currencyID, importMaxTimeItem.Concentration, runItem); err != nil {
 		return err
 	}
 
-	var importMaxTimeItem ImportMaxTime
-	var currency Currency
-	var ok bool
-	var i int
-	for i, currency = range *currencyList {
-		importMaxTimeItem, ok = importMaxTimeMap[currency.ID]
-		if !ok {
-			importMaxTimeItem = ImportMaxTime{
-				CurrencyID: currency.ID,
-			}
-		}
-		fmt.Printf("%d. %s\n", i, currency.Symbol)
-		time.Sleep(6 * time.Second)
-		if importMaxTimeItem.PriceAndCap, err = s.priceAndCap.ImportTx(ctx, tx, importMaxTimeItem.CurrencyID, importMaxTimeItem.PriceAndCap); err != nil {
-			return err
-		}
-		time.Sleep(12 * time.Second)
-		if importMaxTimeItem.Concentration, err = s.concentration.ImportTx(ctx, tx, importMaxTimeItem.CurrencyID, importMaxTimeItem.Concentration); err != nil {
-			return err
-		}
+	return s.replicaSet.WriteRepo().MUpsertImportMaxTimeTx(ctx, tx, &[]ImportMaxTime{importMaxTimeItem})
+}
 
-		importMaxTimeMap[currency.ID] = importMaxTimeItem
+func (s *Service) ImportOraculAnalytics(ctx context.Context, listOfCurrencySlugs *[]string) (err error) {
+	const metricName = "currency.Service.ImportOraculAnalytics"
+	if listOfCurrencySlugs == nil || len(*listOfCurrencySlugs) == 0 {
+		return nil
 	}
 
-	if err = s.replicaSet.WriteRepo().MUpsertImportMaxTimeMapTx(ctx, tx, importMaxTimeMap); err != nil {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	currencyList, err := s.replicaSet.ReadRepo().MGetBySlug(ctx, listOfCurrencySlugs)
+	if err != nil {
 		return err
 	}
 
-	//tokenAddressList, err := s.replicaSet.ReadRepo().MGetTokenAddress(ctx, currencyList.IDs())
-	//if err != nil {
-	//	return err
-	//}
-	//
-	//fmt.Println("OraculAnalytics.Import")
-	//if err = s.oraculAnalytics.Import(ctx, TokenAddressList2OraculAnalyticsTokenAddressList(tokenAddressList)); err != nil {
-	//	return err
-	//}
+	tokenAddressList, err := s.replicaSet.ReadRepo().MGetTokenAddress(ctx, currencyList.IDs())
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
 
-	return nil
+	return s.oraculAnalytics.Import(ctx, TokenAddressList2OraculAnalyticsTokenAddressList(tokenAddressList.SortByPriority(currencyList)))
 }
 
 func (s *Service) baseImport(ctx context.Context, listOfCurrencySlugs *[]string) (currencyList *CurrencyList, err error) {
@@ -240,160 +366,227 @@ func (s *Service) createEmptyImportMaxTime(ctx context.Context, IDs *[]uint) err
 		return nil
 	}
 
-	maxTimeList := make([]ImportMaxTime, 0, len(*IDs))
+	maxTimeList := emptyImportMaxTimeList(IDs)
+	return s.replicaSet.WriteRepo().MCreateImportMaxTime(ctx, &maxTimeList)
+}
+
+func emptyImportMaxTimeList(IDs *[]uint) []ImportMaxTime {
+	res := make([]ImportMaxTime, 0, len(*IDs))
 	var ID uint
 	for _, ID = range *IDs {
-		maxTimeList = append(maxTimeList, ImportMaxTime{
+		res = append(res, ImportMaxTime{
 			CurrencyID: ID,
 		})
 	}
+	return res
+}
 
-	return s.replicaSet.WriteRepo().MCreateImportMaxTime(ctx, &maxTimeList)
+// Report_BiggestFall returns the page of the latest falls of the whales holdings (by default the biggest first) and the total number of the falls.
+func (s *Service) Report_BiggestFall(ctx context.Context, params *WhaleTrendParams) (*WhaleTrendList, uint, error) {
+	return s.reportWhaleTrend(ctx, trend.Direction_Fall, WhaleTrendSort_Value, params)
+}
+
+// Report_LongestFall returns the page of the latest falls of the whales holdings (by default the longest first) and the total number of the falls.
+func (s *Service) Report_LongestFall(ctx context.Context, params *WhaleTrendParams) (*WhaleTrendList, uint, error) {
+	return s.reportWhaleTrend(ctx, trend.Direction_Fall, WhaleTrendSort_Duration, params)
+}
+
+// Report_BiggestRise returns the page of the latest rises of the whales holdings (by default the biggest first) and the total number of the rises.
+func (s *Service) Report_BiggestRise(ctx context.Context, params *WhaleTrendParams) (*WhaleTrendList, uint, error) {
+	return s.reportWhaleTrend(ctx, trend.Direction_Rise, WhaleTrendSort_Value, params)
+}
+
+// Report_LongestRise returns the page of the latest rises of the whales holdings (by default the longest first) and the total number of the rises.
+func (s *Service) Report_LongestRise(ctx context.Context, params *WhaleTrendParams) (*WhaleTrendList, uint, error) {
+	return s.reportWhaleTrend(ctx, trend.Direction_Rise, WhaleTrendSort_Duration, params)
 }
 
-func (s *Service) Report_BiggestFall(ctx context.Context, limit uint) (*WhaleFallList, error) {
-	l, err := s.getWhaleFallList(ctx)
+func (s *Service) reportWhaleTrend(ctx context.Context, direction string, sort string, params *WhaleTrendParams) (*WhaleTrendList, uint, error) {
+	if params.Sort == "" {
+		params.Sort = sort
+	}
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("[%w] whale trend params error: %w", apperror.ErrBadRequest, err)
+	}
+
+	l, err := s.getWhaleTrendList(ctx, direction, params)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return l.SortByFallValueDesc().Limit(limit), nil
+	return l.Sort(params.Sort, params.Order).Page(params.Limit, params.Offset), uint(len(*l)), nil
 }
 
-func (s *Service) Report_LongestFall(ctx context.Context, limit uint) (*WhaleFallList, error) {
-	l, err := s.getWhaleFallList(ctx)
+func (s *Service) getWhaleTrendList(ctx context.Context, direction string, params *WhaleTrendParams) (*WhaleTrendList, error) {
+	data, err := s.getWhaleTrendData(ctx, params)
 	if err != nil {
 		return nil, err
 	}
-	return l.SortByFallDurationDesc().Limit(limit), nil
+	return data.calc(s, direction, params), nil
 }
 
-func (s *Service) getWhaleFallList(ctx context.Context) (*WhaleFallList, error) {
-	currencyList, err := s.replicaSet.ReadRepo().GetAll(ctx)
+// whaleTrendData is the currencies matched by the params of the whale trend report with their series;
+// the trends of both directions are calculated from the same data.
+type whaleTrendData struct {
+	currencyList     CurrencyList
+	priceAndCapMap   price_and_cap.PriceAndCapMap
+	concentrationMap concentration.ConcentrationMap
+}
+
+func (d *whaleTrendData) calc(s *Service, direction string, params *WhaleTrendParams) *WhaleTrendList {
+	if len(d.currencyList) == 0 {
+		return &WhaleTrendList{}
+	}
+	return s.calcWhaleTrendList(&d.currencyList, d.priceAndCapMap, d.concentrationMap, direction, params)
+}
+
+func (s *Service) getWhaleTrendData(ctx context.Context, params *WhaleTrendParams) (*whaleTrendData, error) {
+	var currencyList *CurrencyList
+	var err error
+	if params.WithNotObserved {
+		currencyList, err = s.replicaSet.ReadRepo().GetAllWithNotObserved(ctx)
+	} else {
+		currencyList, err = s.replicaSet.ReadRepo().GetAll(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
+
+	res := &whaleTrendData{
+		currencyList: make(CurrencyList, 0, len(*currencyList)),
+	}
+	var currency Currency
+	for _, currency = range *currencyList {
+		if params.IsMatched(&currency) {
+			res.currencyList = append(res.currencyList, currency)
+		}
+	}
+	if len(res.currencyList) == 0 {
+		return res, nil
+	}
+	currencyIDs := res.currencyList.IDs()
+
+	if res.priceAndCapMap, err = s.priceAndCap.MGet(ctx, currencyIDs); err != nil {
+		return nil, err
+	}
+	if res.concentrationMap, err = s.concentration.MGet(ctx, currencyIDs); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CurrentWhaleTrends returns the latest rise or fall of the whales (the one which ends later) of the currencies by the currency ID; the currencies without a trend are absent.
+func (s *Service) CurrentWhaleTrends(ctx context.Context, currencyList *CurrencyList) (map[uint]WhaleTrend, error) {
+	res := make(map[uint]WhaleTrend, len(*currencyList))
+	if len(*currencyList) == 0 {
+		return res, nil
+	}
+	params := &WhaleTrendParams{}
+	params.SetDefaults()
 	currencyIDs := currencyList.IDs()
 
 	priceAndCapMap, err := s.priceAndCap.MGet(ctx, currencyIDs)
 	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return res, nil
+		}
 		return nil, err
 	}
 
 	concentrationMap, err := s.concentration.MGet(ctx, currencyIDs)
 	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return res, nil
+		}
 		return nil, err
 	}
 
-	return s.calcWhaleFallList(currencyList, priceAndCapMap, concentrationMap), nil
+	var ok bool
+	var item, prev WhaleTrend
+	var direction string
+	for _, direction = range []string{trend.Direction_Fall, trend.Direction_Rise} {
+		for _, item = range *s.calcWhaleTrendList(currencyList, priceAndCapMap, concentrationMap, direction, params) {
+			if prev, ok = res[item.CurrencyID]; ok && !item.DayTo.After(prev.DayTo) {
+				continue
+			}
+			res[item.CurrencyID] = item
+		}
+	}
+	return res, nil
 }
 
-func (s *Service) calcWhaleFallList(currencyList *CurrencyList, priceAndCapMap price_and_cap.PriceAndCapMap, concentrationMap concentration.ConcentrationMap) *WhaleFallList {
-	if currencyList == nil || priceAndCapMap == nil || concentrationMap == nil {
-		return nil
+func (s *Service) calcWhaleTrendList(currencyList *CurrencyList, priceAndCapMap price_and_cap.PriceAndCapMap, concentrationMap concentration.ConcentrationMap, direction string, params *WhaleTrendParams) *WhaleTrendList {
+	res := make(WhaleTrendList, 0, len(*currencyList))
+	if priceAndCapMap == nil || concentrationMap == nil {
+		return &res
 	}
+	cfg := params.Config()
 	var ok bool
-	var i int
 	var currency Currency
 	var priceAndCapList price_and_cap.PriceAndCapList
 	var concentrationList concentration.ConcentrationList
-	var item *WhaleFall
-	res := make(WhaleFallList, 0, len(*currencyList))
+	var run *trend.Run
 
-	for i, currency = range *currencyList {
-		if i == 91 {
-			fmt.Println(".")
-		}
+	for _, currency = range *currencyList {
 		if priceAndCapList, ok = priceAndCapMap[currency.ID]; !ok {
 			continue
 		}
 		if concentrationList, ok = concentrationMap[currency.ID]; !ok {
 			continue
 		}
-		item = s.calcWhaleFall(&currency, &priceAndCapList, &concentrationList)
-		if item == nil {
-			currencyJson, _ := json.Marshal(currency)
-			fmt.Printf("calcWhaleFall: empty result for currency: %s", string(currencyJson))
+		series := concentrationList.Between(params.From, params.To).Series(func(e *concentration.Concentration) float64 { return e.Whales })
+		if run = trend.Detect(series, direction, cfg, params.To); run == nil {
 			continue
 		}
-		res = append(res, *item)
+		res = append(res, *s.calcWhaleTrend(&currency, &priceAndCapList, run))
 	}
 
 	return &res
 }
 
-func (s *Service) calcWhaleFall(currency *Currency, priceAndCapList *price_and_cap.PriceAndCapList, concentrationList *concentration.ConcentrationList) *WhaleFall {
-	if currency == nil || priceAndCapList == nil || concentrationList == nil {
-		return nil
-	}
-	const (
-		maxPeriod = time.Hour * 24 * 61 // Максимальный период времени, который смотрим
-		maxBreak  = time.Hour * 24 * 5  // Максимальный перерыв в тренде
-	)
-	now := time.Now()
-	// minTime: ограничение, дальше которого не смотрим
-	minTime := now.Add(-maxPeriod)
-	var inFall bool
-	var i int
-	var prev, next, valueFrom, valueTo, localStart *concentration.Concentration
-	// т.к. concentrationList отсортирован в порядке убывания по времени, в цикле next идёт перед prev
-	for i = range *concentrationList {
-		prev = &(*concentrationList)[i]
-		// первую итерацию просто пропустим
-		if i == 0 {
-			next = prev
-			continue
-		}
-		// дальше minTime не смотрим, останавливаем цикл
-		if prev.D.Before(minTime) {
-			break
-		}
-
-		if !inFall {
-			valueTo = next
-		}
-
-		// если это не спад, то пропустим
-		if valueTo.Whales >= prev.Whales {
-			// если уже нашли падение, то проверяем на maxBreak
-			if inFall && localStart != nil && localStart.D.Sub(prev.D) >= maxBreak {
-				break
-			}
-			next = prev
-			continue
-		}
-		inFall = true
-
-		localStart = prev
-		next = prev
-	}
-	if localStart != nil {
-		valueFrom = localStart
-	}
-	if !inFall || valueFrom == nil || valueTo == nil {
-		return nil
-	}
+func (s *Service) calcWhaleTrend(currency *Currency, priceAndCapList *price_and_cap.PriceAndCapList, run *trend.Run) *WhaleTrend {
+	// изменения считаем в направлении тренда
+	sign := 1.0
+	if run.Direction == trend.Direction_Fall {
+		sign = -1.0
+	}
+	res := &WhaleTrend{
+		CurrencyID:   currency.ID,
+		Symbol:       currency.Symbol,
+		CmcRank:      currency.CmcRank,
+		MarketCap:    currency.MarketCap(),
+		Direction:    run.Direction,
+		Duration:     run.Duration(),
+		DayFrom:      run.From.T,
+		DayTo:        run.To.T,
+		Value:        run.Change(),
+		ValueFrom:    run.From.V,
+		ValueTo:      run.To.V,
+		ValuePercent: percentOf(run.To.V, run.From.V),
+	}
+
+	priceAndCapFrom := priceAndCapList.AvgInDay(run.From.T)
+	priceAndCapTo := priceAndCapList.AvgInDay(run.To.T)
+	if priceAndCapFrom == nil || priceAndCapTo == nil {
+		return res
+	}
+	res.Cap = sign * (priceAndCapTo.Cap - priceAndCapFrom.Cap)
+	res.CapFrom = priceAndCapFrom.Cap
+	res.CapTo = priceAndCapTo.Cap
+	res.CapPercent = percentOf(priceAndCapTo.Cap, priceAndCapFrom.Cap)
+	res.Price = sign * (priceAndCapTo.Price - priceAndCapFrom.Price)
+	res.PriceFrom = priceAndCapFrom.Price
+	res.PriceTo = priceAndCapTo.Price
+	res.PricePercent = percentOf(priceAndCapTo.Price, priceAndCapFrom.Price)
+	return res
+}
 
-	priceAndCapFrom := priceAndCapList.AvgInDay(valueFrom.D)
-	priceAndCapTo := priceAndCapList.AvgInDay(valueTo.D)
-
-	return &WhaleFall{
-		Symbol:           currency.Symbol,
-		FallDuration:     valueTo.D.Sub(valueFrom.D),
-		DayFrom:          valueFrom.D,
-		DayTo:            valueTo.D,
-		FallValue:        valueFrom.Whales - valueTo.Whales,
-		ValueFrom:        valueFrom.Whales,
-		ValueTo:          valueTo.Whales,
-		FallValuePercent: round(((valueTo.Whales * 100) / valueFrom.Whales)),
-		FallCap:          priceAndCapFrom.Cap - priceAndCapTo.Cap,
-		CapFrom:          priceAndCapFrom.Cap,
-		CapTo:            priceAndCapTo.Cap,
-		FallCapPercent:   round(((priceAndCapTo.Cap * 100) / priceAndCapFrom.Cap)),
-		FallPrice:        priceAndCapFrom.Price - priceAndCapTo.Price,
-		PriceFrom:        priceAndCapFrom.Price,
-		PriceTo:          priceAndCapTo.Price,
-		FallPricePercent: round(((priceAndCapTo.Price * 100) / priceAndCapFrom.Price)),
+// percentOf returns v in percents of base; it is 0 if base is 0.
+func percentOf(v float64, base float64) float64 {
+	if base == 0 {
+		return 0
 	}
+	return round(v * 100 / base)
 }
 
 func round(v float64) float64 {
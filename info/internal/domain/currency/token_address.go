@@ -0,0 +1,75 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain"
+	"info/internal/pkg/apperror"
+	"runtime/debug"
+)
+
+// SyncTokenAddress replaces the token addresses of the currencies with the ones from CMC: the addresses on all the chains and the address on the main platform.
+func (s *Service) SyncTokenAddress(ctx context.Context, listOfCurrencySlugs *[]string) (tokenAddressList *TokenAddressList, err error) {
+	const metricName = "currency.Service.SyncTokenAddress"
+	if listOfCurrencySlugs == nil || len(*listOfCurrencySlugs) == 0 {
+		return &TokenAddressList{}, nil
+	}
+
+	currencyList, err := s.replicaSet.ReadRepo().MGetBySlug(ctx, listOfCurrencySlugs)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return &TokenAddressList{}, nil
+		}
+		return nil, err
+	}
+
+	tokenAddressList, err = s.cmcProApi.GetTokenAddresses(ctx, currencyList.IDs())
+	if err != nil {
+		return nil, err
+	}
+	var item Currency
+	for _, item = range *currencyList {
+		if item.Platform != nil && item.Platform.Symbol != "" && item.Platform.TokenAddress != "" {
+			*tokenAddressList = append(*tokenAddressList, TokenAddress{
+				CurrencyID: item.ID,
+				Blockchain: item.Platform.Symbol,
+				Address:    item.Platform.TokenAddress,
+			})
+		}
+	}
+	tokenAddressList = tokenAddressList.Unique()
+
+	var tx domain.Tx
+	tx, err = s.replicaSet.WriteRepo().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+
+		if err == nil {
+			if err = tx.Commit(ctx); err == nil {
+				return
+			}
+			err = fmt.Errorf("[%w] "+metricName+" Commit error: %w", apperror.ErrInternal, err)
+		}
+
+		if err2 := tx.Rollback(ctx); err2 != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Rollback error: %w", apperror.ErrInternal, err2))
+		}
+		tokenAddressList = nil
+	}()
+
+	if err = s.replicaSet.WriteRepo().MDeleteTokenAddressTx(ctx, tx, currencyList.IDs()); err != nil {
+		return nil, err
+	}
+	if err = s.replicaSet.WriteRepo().MCreateTokenAddressTx(ctx, tx, tokenAddressList); err != nil {
+		return nil, err
+	}
+
+	return tokenAddressList, nil
+}
@@ -0,0 +1,169 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain"
+	"info/internal/pkg/apperror"
+	"runtime/debug"
+	"slices"
+	"strings"
+)
+
+// Watchlist returns the observed currencies.
+func (s *Service) Watchlist(ctx context.Context) (*CurrencyList, error) {
+	return s.replicaSet.ReadRepo().GetAll(ctx)
+}
+
+// WatchlistSlugs returns the slugs of the observed currencies.
+// The watchlist is seeded with seedSlugs (the list from the config) once: while there are no currencies at all,
+// so the watchlist emptied by the removes stays empty.
+func (s *Service) WatchlistSlugs(ctx context.Context, seedSlugs *[]string) (*[]string, error) {
+	currencyList, err := s.Watchlist(ctx)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if currencyList != nil && len(*currencyList) > 0 {
+		return currencyList.Slugs(), nil
+	}
+	if seedSlugs == nil || len(*seedSlugs) == 0 {
+		return &[]string{}, nil
+	}
+	allList, err := s.replicaSet.ReadRepo().GetAllWithNotObserved(ctx)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if allList != nil && len(*allList) > 0 {
+		return &[]string{}, nil
+	}
+
+	if currencyList, err = s.WatchlistAdd(ctx, seedSlugs); err != nil {
+		return nil, err
+	}
+	return currencyList.Slugs(), nil
+}
+
+// WatchlistAdd resolves the slugs via CMC, upserts the currencies, creates their import times and puts them on the watchlist.
+func (s *Service) WatchlistAdd(ctx context.Context, slugs *[]string) (currencyList *CurrencyList, err error) {
+	const metricName = "currency.Service.WatchlistAdd"
+	if slugs == nil || len(*slugs) == 0 {
+		return nil, fmt.Errorf("[%w] slugs are required", apperror.ErrBadRequest)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	currencyMap, err := s.cmcProApi.GetCurrenciesBySlugs(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+	currencyList = currencyMap.List()
+	if notFound := notFoundSlugs(slugs, currencyList.Slugs()); len(notFound) > 0 {
+		return nil, fmt.Errorf("[%w] unknown slugs: %s", apperror.ErrNotFound, strings.Join(notFound, ", "))
+	}
+
+	return currencyList, s.WatchlistAddCurrencies(ctx, currencyList)
+}
+
+// WatchlistAddCurrencies upserts the already resolved currencies, creates their import times and puts them on the watchlist.
+func (s *Service) WatchlistAddCurrencies(ctx context.Context, currencyList *CurrencyList) (err error) {
+	const metricName = "currency.Service.WatchlistAddCurrencies"
+	if currencyList == nil || len(*currencyList) == 0 {
+		return nil
+	}
+
+	var tx domain.Tx
+	tx, err = s.replicaSet.WriteRepo().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+		if err == nil {
+			if err = tx.Commit(ctx); err == nil {
+				return
+			}
+			err = fmt.Errorf("[%w] "+metricName+" Commit error: %w", apperror.ErrInternal, err)
+		}
+		if err2 := tx.Rollback(ctx); err2 != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Rollback error: %w", apperror.ErrInternal, err2))
+		}
+	}()
+
+	return s.WatchlistAddCurrenciesTx(ctx, tx, currencyList)
+}
+
+// WatchlistAddCurrenciesTx is WatchlistAddCurrencies in the transaction of the caller.
+func (s *Service) WatchlistAddCurrenciesTx(ctx context.Context, tx domain.Tx, currencyList *CurrencyList) error {
+	if currencyList == nil || len(*currencyList) == 0 {
+		return nil
+	}
+
+	if err := s.replicaSet.WriteRepo().MUpsertTx(ctx, tx, currencyList); err != nil {
+		return err
+	}
+	maxTimeList := emptyImportMaxTimeList(currencyList.IDs())
+	if err := s.replicaSet.WriteRepo().MCreateImportMaxTimeTx(ctx, tx, &maxTimeList); err != nil {
+		return err
+	}
+	if _, err := s.replicaSet.WriteRepo().MSetIsForObservingTx(ctx, tx, currencyList.Slugs(), true); err != nil {
+		return err
+	}
+
+	for i := range *currencyList {
+		(*currencyList)[i].IsForObserving = true
+	}
+	return nil
+}
+
+// WatchlistRemove takes the currencies off the watchlist; their collected data is kept.
+func (s *Service) WatchlistRemove(ctx context.Context, slugs *[]string) error {
+	if slugs == nil || len(*slugs) == 0 {
+		return fmt.Errorf("[%w] slugs are required", apperror.ErrBadRequest)
+	}
+
+	removed, err := s.replicaSet.WriteRepo().MSetIsForObserving(ctx, slugs, false)
+	if err != nil {
+		return err
+	}
+	return removedError(slugs, removed)
+}
+
+// WatchlistRemoveTx is WatchlistRemove in the transaction of the caller.
+func (s *Service) WatchlistRemoveTx(ctx context.Context, tx domain.Tx, slugs *[]string) error {
+	if slugs == nil || len(*slugs) == 0 {
+		return fmt.Errorf("[%w] slugs are required", apperror.ErrBadRequest)
+	}
+
+	removed, err := s.replicaSet.WriteRepo().MSetIsForObservingTx(ctx, tx, slugs, false)
+	if err != nil {
+		return err
+	}
+	return removedError(slugs, removed)
+}
+
+// removedError returns ErrNotFound if some of the slugs have not been removed.
+func removedError(slugs *[]string, removed *[]string) error {
+	if notFound := notFoundSlugs(slugs, removed); len(notFound) > 0 {
+		return fmt.Errorf("[%w] unknown slugs: %s", apperror.ErrNotFound, strings.Join(notFound, ", "))
+	}
+	return nil
+}
+
+// notFoundSlugs returns the requested slugs which are absent in found.
+func notFoundSlugs(requested *[]string, found *[]string) []string {
+	res := make([]string, 0)
+	var slug string
+	for _, slug = range *requested {
+		if found == nil || !slices.Contains(*found, slug) {
+			res = append(res, slug)
+		}
+	}
+	return res
+}
@@ -0,0 +1,253 @@
+package data_audit
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	rule_None        = iota
+	rule_NonNegative // the value must not be negative
+	rule_Positive    // the value must be above zero
+)
+
+const day = 24 * time.Hour
+
+type field struct {
+	name string
+	rule int
+}
+
+// jump is the value checked for the change from the previous day
+type jump struct {
+	name  string
+	value func(values []float64) (float64, bool)
+	max   func(params *Params) float64
+}
+
+// spec is the fields of the series in the order of Point.Values
+type spec struct {
+	fields  []field
+	isDaily bool // the series of the dates: the intervals are not checked
+	jumps   []jump
+}
+
+// share returns the share of the first value in the sum of the values in percents; false if the sum is not positive.
+func share(values ...float64) (float64, bool) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	if sum <= 0 {
+		return 0, false
+	}
+	return values[0] * 100 / sum, true
+}
+
+var specs = map[string]spec{
+	Series_PriceAndCap: {
+		fields: []field{{"price", rule_Positive}, {"daily_volume", rule_NonNegative}, {"cap", rule_NonNegative}},
+		jumps: []jump{{
+			name:  "price",
+			value: func(values []float64) (float64, bool) { return values[0], values[0] > 0 },
+			max:   func(params *Params) float64 { return params.MaxPriceJump },
+		}},
+	},
+	Series_Concentration: {
+		fields:  []field{{"whales", rule_Positive}, {"investors", rule_NonNegative}, {"retail", rule_NonNegative}},
+		isDaily: true,
+		jumps: []jump{{
+			name:  "whale_share",
+			value: func(values []float64) (float64, bool) { return share(values[0], values[1], values[2]) },
+			max:   func(params *Params) float64 { return params.MaxShareJump },
+		}},
+	},
+	Series_OraculAnalytics: {
+		fields: []field{{"whales_concentration", rule_NonNegative}, {"worm_index", rule_None}, {"growth_fuel", rule_None}},
+	},
+	Series_OraculSpeedometers: {
+		fields: []field{{"whales_buy_rate", rule_NonNegative}, {"whales_sell_rate", rule_NonNegative}, {"whales_volume", rule_NonNegative},
+			{"investors_buy_rate", rule_NonNegative}, {"investors_sell_rate", rule_NonNegative}, {"investors_volume", rule_NonNegative},
+			{"retailers_buy_rate", rule_NonNegative}, {"retailers_sell_rate", rule_NonNegative}, {"retailers_volume", rule_NonNegative}},
+	},
+	Series_OraculHolderStats: {
+		fields: []field{{"whales_volume", rule_NonNegative}, {"whales_total_holders", rule_NonNegative}, {"investors_volume", rule_NonNegative},
+			{"investors_total_holders", rule_NonNegative}, {"retailers_volume", rule_NonNegative}, {"retailers_total_holders", rule_NonNegative}},
+	},
+	Series_OraculDailyBalanceStats: {
+		fields: []field{{"whales_balance", rule_Positive}, {"whales_total_holders", rule_NonNegative}, {"investors_balance", rule_NonNegative},
+			{"investors_total_holders", rule_NonNegative}, {"retailers_balance", rule_NonNegative}, {"retailers_total_holders", rule_NonNegative}},
+		isDaily: true,
+		jumps: []jump{{
+			name:  "whale_share",
+			value: func(values []float64) (float64, bool) { return share(values[0], values[2], values[4]) },
+			max:   func(params *Params) float64 { return params.MaxShareJump },
+		}},
+	},
+}
+
+// check returns the issues of the points of the series of the currency ordered by the time; from is the start of the window.
+func (s *spec) check(series string, currencyID uint, symbol string, points PointList, params *Params, from time.Time, now time.Time) IssueList {
+	res := make(IssueList, 0)
+	newIssue := func(kind string, ts time.Time) Issue {
+		return Issue{
+			Series:     series,
+			CurrencyID: currencyID,
+			Symbol:     symbol,
+			Kind:       kind,
+			Ts:         ts,
+		}
+	}
+
+	if len(points) == 0 {
+		item := newIssue(Kind_Stale, from)
+		item.Message = fmt.Sprintf("%s %s: no points since %s", symbol, series, from.Format(time.DateOnly))
+		return append(res, item)
+	}
+
+	res = append(res, s.checkValues(points, newIssue)...)
+	res = append(res, s.checkMissingDays(points, newIssue)...)
+	if !s.isDaily {
+		res = append(res, s.checkIntervals(points, newIssue)...)
+	}
+	res = append(res, s.checkJumps(points, params, newIssue)...)
+
+	last := points[len(points)-1].Ts
+	lastEnd := last
+	if s.isDaily {
+		// точка дня покрывает весь день
+		lastEnd = last.Add(day)
+	}
+	if age := now.Sub(lastEnd); age > params.StaleAfter {
+		item := newIssue(Kind_Stale, last)
+		item.Value = round(age.Hours())
+		item.Message = fmt.Sprintf("%s %s: the last point is %s old", symbol, series, age.Truncate(time.Minute))
+		res = append(res, item)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Ts.Before(res[j].Ts)
+	})
+	return res
+}
+
+func (s *spec) checkValues(points PointList, newIssue func(kind string, ts time.Time) Issue) IssueList {
+	res := make(IssueList, 0)
+	for _, p := range points {
+		for i, f := range s.fields {
+			v := p.Values[i]
+			if (f.rule == rule_Positive && v <= 0) || (f.rule == rule_NonNegative && v < 0) {
+				item := newIssue(Kind_NonPositive, p.Ts)
+				item.Field = f.name
+				item.Value = v
+				item.Message = fmt.Sprintf("%s %s: %s is %g at %s", item.Symbol, item.Series, f.name, v, p.Ts.Format(time.DateTime))
+				res = append(res, item)
+			}
+		}
+	}
+	return res
+}
+
+// checkMissingDays returns the ranges of the days without points between the first and the last point.
+func (s *spec) checkMissingDays(points PointList, newIssue func(kind string, ts time.Time) Issue) IssueList {
+	res := make(IssueList, 0)
+	prev := points[0].Ts.Truncate(day)
+	for _, p := range points[1:] {
+		d := p.Ts.Truncate(day)
+		if d.Sub(prev) > day {
+			first, last := prev.Add(day), d.Add(-day)
+			item := newIssue(Kind_MissingDay, first)
+			item.TsTo = &last
+			item.Value = float64(last.Sub(first)/day + 1)
+			item.Message = fmt.Sprintf("%s %s: %g days missing from %s to %s", item.Symbol, item.Series, item.Value, first.Format(time.DateOnly), last.Format(time.DateOnly))
+			res = append(res, item)
+		}
+		prev = d
+	}
+	return res
+}
+
+// checkIntervals compares the intervals between the points with the median one.
+func (s *spec) checkIntervals(points PointList, newIssue func(kind string, ts time.Time) Issue) IssueList {
+	res := make(IssueList, 0)
+	if len(points) < 3 {
+		return res
+	}
+	intervals := make([]time.Duration, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		intervals = append(intervals, points[i].Ts.Sub(points[i-1].Ts))
+	}
+	sorted := append([]time.Duration(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	median := sorted[len(sorted)/2]
+
+	for i, interval := range intervals {
+		prev, p := points[i], points[i+1]
+		switch {
+		case interval < median/2:
+			item := newIssue(Kind_Duplicate, p.Ts)
+			item.Value = round(interval.Hours())
+			item.Message = fmt.Sprintf("%s %s: the point at %s is %s after the previous one; the usual interval is %s", item.Symbol, item.Series, p.Ts.Format(time.DateTime), interval, median)
+			res = append(res, item)
+		case interval > median*3/2 && p.Ts.Truncate(day).Sub(prev.Ts.Truncate(day)) <= day:
+			// пропуски целых дней отмечены как missing-day
+			item := newIssue(Kind_Irregular, prev.Ts)
+			ts := p.Ts
+			item.TsTo = &ts
+			item.Value = round(interval.Hours())
+			item.Message = fmt.Sprintf("%s %s: %s between %s and %s; the usual interval is %s", item.Symbol, item.Series, interval, prev.Ts.Format(time.DateTime), p.Ts.Format(time.DateTime), median)
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// checkJumps compares the last value of every day with the last value of the previous day.
+func (s *spec) checkJumps(points PointList, params *Params, newIssue func(kind string, ts time.Time) Issue) IssueList {
+	res := make(IssueList, 0)
+	if len(s.jumps) == 0 {
+		return res
+	}
+	// последняя точка каждого дня
+	dayPoints := make(PointList, 0, len(points))
+	for i, p := range points {
+		if i+1 < len(points) && points[i+1].Ts.Truncate(day).Equal(p.Ts.Truncate(day)) {
+			continue
+		}
+		dayPoints = append(dayPoints, p)
+	}
+
+	for _, j := range s.jumps {
+		max := j.max(params)
+		for i := 1; i < len(dayPoints); i++ {
+			prev, p := dayPoints[i-1], dayPoints[i]
+			if p.Ts.Truncate(day).Sub(prev.Ts.Truncate(day)) != day {
+				continue
+			}
+			prevV, ok := j.value(prev.Values)
+			if !ok || prevV == 0 {
+				continue
+			}
+			v, ok := j.value(p.Values)
+			if !ok {
+				continue
+			}
+			change := (v - prevV) * 100 / prevV
+			if math.Abs(change) <= max {
+				continue
+			}
+			item := newIssue(Kind_Jump, p.Ts)
+			item.Field = j.name
+			item.Value = round(change)
+			item.Message = fmt.Sprintf("%s %s: %s changed by %.2f%% in a day: %g -> %g at %s", item.Symbol, item.Series, j.name, change, round(prevV), round(v), p.Ts.Format(time.DateTime))
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
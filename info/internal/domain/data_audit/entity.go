@@ -0,0 +1,168 @@
+package data_audit
+
+import (
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Series_PriceAndCap             = "cmc.price_and_cap"
+	Series_Concentration           = "cmc.concentration"
+	Series_OraculAnalytics         = "oracul.analytics"
+	Series_OraculSpeedometers      = "oracul.speedometers"
+	Series_OraculHolderStats       = "oracul.holder_stats"
+	Series_OraculDailyBalanceStats = "oracul.daily_balance_stats"
+
+	Kind_MissingDay  = "missing-day"  // the days without points between the first and the last point; Value is the number of the days
+	Kind_Duplicate   = "duplicate"    // the point closer to the previous one than the half of the usual interval; Value is the interval in hours
+	Kind_Irregular   = "irregular"    // the interval longer than 1.5 of the usual one without a whole missing day; Value is the interval in hours
+	Kind_NonPositive = "non-positive" // the zero or negative value of the Field
+	Kind_Jump        = "jump"         // the change of the Field from the previous day in percents above the max
+	Kind_Stale       = "stale"        // no points during StaleAfter; Value is the age of the last point in hours
+
+	DefaultDays         = 90
+	MaxDays             = 3650
+	DefaultStaleAfter   = 48 * time.Hour
+	DefaultMaxPriceJump = 80 // percents of the price in a day
+	DefaultMaxShareJump = 20 // percents of the whale share in a day
+	DefaultIssuesLimit  = 1000
+)
+
+var SeriesList = []interface{}{
+	Series_PriceAndCap,
+	Series_Concentration,
+	Series_OraculAnalytics,
+	Series_OraculSpeedometers,
+	Series_OraculHolderStats,
+	Series_OraculDailyBalanceStats,
+}
+
+var KindList = []interface{}{
+	Kind_MissingDay,
+	Kind_Duplicate,
+	Kind_Irregular,
+	Kind_NonPositive,
+	Kind_Jump,
+	Kind_Stale,
+}
+
+// IsQuarantinable returns true for the kinds of the issues of a single point.
+func IsQuarantinable(kind string) bool {
+	return kind == Kind_Duplicate || kind == Kind_NonPositive || kind == Kind_Jump
+}
+
+// Params of the audit of the series (all if empty) over the last Days days.
+// The points of the issues of the single point are quarantined if Quarantine is set.
+type Params struct {
+	Series       []string
+	Days         uint
+	StaleAfter   time.Duration
+	MaxPriceJump float64
+	MaxShareJump float64
+	Quarantine   bool
+}
+
+// SetDefaults sets the empty Series to all the series and the empty limits to the default ones.
+func (e *Params) SetDefaults() {
+	if len(e.Series) == 0 {
+		e.Series = make([]string, 0, len(SeriesList))
+		for _, s := range SeriesList {
+			e.Series = append(e.Series, s.(string))
+		}
+	}
+	if e.Days == 0 {
+		e.Days = DefaultDays
+	}
+	if e.StaleAfter == 0 {
+		e.StaleAfter = DefaultStaleAfter
+	}
+	if e.MaxPriceJump == 0 {
+		e.MaxPriceJump = DefaultMaxPriceJump
+	}
+	if e.MaxShareJump == 0 {
+		e.MaxShareJump = DefaultMaxShareJump
+	}
+}
+
+func (e *Params) Validate() error {
+	if err := validation.ValidateStruct(e,
+		validation.Field(&e.Days, validation.Required, validation.Max(uint(MaxDays))),
+		validation.Field(&e.StaleAfter, validation.Required, validation.Min(time.Duration(0))),
+		validation.Field(&e.MaxPriceJump, validation.Required, validation.Min(0.0)),
+		validation.Field(&e.MaxShareJump, validation.Required, validation.Min(0.0)),
+	); err != nil {
+		return err
+	}
+	for _, s := range e.Series {
+		if err := validation.Validate(s, validation.In(SeriesList...)); err != nil {
+			return fmt.Errorf("series %q: %w", s, err)
+		}
+	}
+	return nil
+}
+
+// Run is the audit of the series from the time From
+type Run struct {
+	ID            uint
+	Series        []string
+	From          time.Time
+	IssuesNb      uint
+	QuarantinedNb uint
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	Issues        IssueList // is not stored with the run
+}
+
+type RunList []Run
+
+// Issue is the problem of the series of the currency at Ts (from Ts to TsTo for the ranges)
+type Issue struct {
+	ID            uint
+	RunID         uint
+	Series        string
+	CurrencyID    uint
+	Symbol        string
+	Kind          string
+	Field         string
+	Ts            time.Time
+	TsTo          *time.Time
+	Value         float64
+	Message       string
+	IsQuarantined bool
+}
+
+type IssueList []Issue
+
+// IssueFilter of the issues; the zero RunID is the last run.
+type IssueFilter struct {
+	RunID      uint
+	Series     string
+	Kind       string
+	CurrencyID uint
+	Limit      uint
+}
+
+// Quarantine is the point excluded from the reads of the series
+type Quarantine struct {
+	Series     string
+	CurrencyID uint
+	Ts         time.Time
+	Reason     string
+	CreatedAt  time.Time
+}
+
+type QuarantineList []Quarantine
+
+// Point is the point of the series with the values of the fields of the series
+type Point struct {
+	CurrencyID uint
+	Ts         time.Time
+	Values     []float64
+}
+
+type PointList []Point
+
+// PointMap is the points of the series by the currency ID
+type PointMap map[uint]PointList
@@ -0,0 +1,31 @@
+package data_audit
+
+import (
+	"context"
+	"info/internal/domain"
+	"time"
+)
+
+type ReplicaSet interface {
+	WriteRepo() WriteRepository
+	ReadRepo() ReadRepository
+}
+
+type WriteRepository interface {
+	Begin(ctx context.Context) (domain.Tx, error)
+	CreateRunTx(ctx context.Context, tx domain.Tx, entity *Run) (ID uint, err error)
+	MCreateIssueTx(ctx context.Context, tx domain.Tx, entities *IssueList) error
+	MQuarantine(ctx context.Context, entities *QuarantineList) error
+	MQuarantineTx(ctx context.Context, tx domain.Tx, entities *QuarantineList) error
+	Release(ctx context.Context, series string, currencyID uint, ts time.Time) error
+}
+
+type ReadRepository interface {
+	GetRuns(ctx context.Context, limit uint) (*RunList, error)
+	GetLastRun(ctx context.Context) (*Run, error)
+	GetIssue(ctx context.Context, ID uint) (*Issue, error)
+	GetIssues(ctx context.Context, filter *IssueFilter) (*IssueList, error)
+	GetQuarantine(ctx context.Context) (*QuarantineList, error)
+	// GetSeries returns the not quarantined points of the series of the currencies from the time from, ordered by the time.
+	GetSeries(ctx context.Context, series string, currencyIDs *[]uint, from time.Time) (PointMap, error)
+}
@@ -0,0 +1,239 @@
+package data_audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain"
+	"info/internal/domain/currency"
+	"info/internal/pkg/apperror"
+	"runtime/debug"
+	"time"
+)
+
+type Service struct {
+	replicaSet ReplicaSet
+	currency   *currency.Service
+}
+
+func NewService(replicaSet ReplicaSet, currency *currency.Service) *Service {
+	return &Service{
+		replicaSet: replicaSet,
+		currency:   currency,
+	}
+}
+
+// Audit checks the series of the observed currencies over the last Days days, stores the run with its issues
+// and quarantines the points of the issues of the single point if Quarantine is set.
+// The already quarantined points are not checked.
+func (s *Service) Audit(ctx context.Context, params *Params) (res *Run, err error) {
+	const metricName = "data_audit.Service.Audit"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	params.SetDefaults()
+	if err = params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] data audit params error: %w", apperror.ErrBadRequest, err)
+	}
+
+	now := time.Now().UTC()
+	res = &Run{
+		Series:    params.Series,
+		From:      now.Truncate(day).AddDate(0, 0, -int(params.Days)),
+		StartedAt: now,
+		Issues:    IssueList{},
+	}
+
+	currencyList, err := s.currency.GetAll(ctx)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		currencyList = &currency.CurrencyList{}
+	}
+
+	for _, series := range params.Series {
+		if len(*currencyList) == 0 {
+			break
+		}
+		spec := specs[series]
+		pointMap, err := s.replicaSet.ReadRepo().GetSeries(ctx, series, currencyList.IDs(), res.From)
+		if err != nil {
+			if !errors.Is(err, apperror.ErrNotFound) {
+				return nil, err
+			}
+			pointMap = PointMap{}
+		}
+		for _, item := range *currencyList {
+			res.Issues = append(res.Issues, spec.check(series, item.ID, item.Symbol, pointMap[item.ID], params, res.From, now)...)
+		}
+	}
+
+	var quarantineList QuarantineList
+	if params.Quarantine {
+		quarantineList = s.quarantineList(res.Issues, now)
+		for i := range res.Issues {
+			res.Issues[i].IsQuarantined = IsQuarantinable(res.Issues[i].Kind)
+		}
+	}
+	res.IssuesNb = uint(len(res.Issues))
+	res.QuarantinedNb = uint(len(quarantineList))
+	res.FinishedAt = time.Now().UTC()
+
+	if err = s.save(ctx, res, &quarantineList); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// save stores the run with its issues and quarantines the points in one transaction.
+func (s *Service) save(ctx context.Context, run *Run, quarantineList *QuarantineList) (err error) {
+	const metricName = "data_audit.Service.save"
+	var tx domain.Tx
+
+	tx, err = s.replicaSet.WriteRepo().Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+
+		if err == nil {
+			if err = tx.Commit(ctx); err == nil {
+				return
+			}
+			err = fmt.Errorf("[%w] "+metricName+" Commit error: %w", apperror.ErrInternal, err)
+		}
+
+		if err2 := tx.Rollback(ctx); err2 != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Rollback error: %w", apperror.ErrInternal, err2))
+		}
+	}()
+
+	if run.ID, err = s.replicaSet.WriteRepo().CreateRunTx(ctx, tx, run); err != nil {
+		return err
+	}
+	for i := range run.Issues {
+		run.Issues[i].RunID = run.ID
+	}
+	if err = s.replicaSet.WriteRepo().MCreateIssueTx(ctx, tx, &run.Issues); err != nil {
+		return err
+	}
+	return s.replicaSet.WriteRepo().MQuarantineTx(ctx, tx, quarantineList)
+}
+
+// quarantineList returns the unique points of the issues of the single point.
+func (s *Service) quarantineList(issues IssueList, now time.Time) QuarantineList {
+	type key struct {
+		series     string
+		currencyID uint
+		ts         time.Time
+	}
+	res := make(QuarantineList, 0)
+	exists := make(map[key]struct{})
+	for _, item := range issues {
+		if !IsQuarantinable(item.Kind) {
+			continue
+		}
+		k := key{series: item.Series, currencyID: item.CurrencyID, ts: item.Ts}
+		if _, ok := exists[k]; ok {
+			continue
+		}
+		exists[k] = struct{}{}
+		res = append(res, Quarantine{
+			Series:     item.Series,
+			CurrencyID: item.CurrencyID,
+			Ts:         item.Ts,
+			Reason:     item.Message,
+			CreatedAt:  now,
+		})
+	}
+	return res
+}
+
+// Runs returns the last audit runs, the newest first.
+func (s *Service) Runs(ctx context.Context, limit uint) (*RunList, error) {
+	return s.replicaSet.ReadRepo().GetRuns(ctx, limit)
+}
+
+// Issues returns the issues by the filter; the issues of the last run if RunID is zero.
+func (s *Service) Issues(ctx context.Context, filter *IssueFilter) (*IssueList, error) {
+	if filter.Series != "" && !isSeries(filter.Series) {
+		return nil, fmt.Errorf("[%w] unknown series: %q", apperror.ErrBadRequest, filter.Series)
+	}
+	if filter.Kind != "" && !isKind(filter.Kind) {
+		return nil, fmt.Errorf("[%w] unknown kind: %q", apperror.ErrBadRequest, filter.Kind)
+	}
+	if filter.RunID == 0 {
+		run, err := s.replicaSet.ReadRepo().GetLastRun(ctx)
+		if err != nil {
+			return nil, err
+		}
+		filter.RunID = run.ID
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultIssuesLimit
+	}
+	return s.replicaSet.ReadRepo().GetIssues(ctx, filter)
+}
+
+// Quarantine returns the quarantined points, the newest first.
+func (s *Service) Quarantine(ctx context.Context) (*QuarantineList, error) {
+	return s.replicaSet.ReadRepo().GetQuarantine(ctx)
+}
+
+// QuarantineIssue quarantines the point of the issue; only the issues of the single point may be quarantined.
+func (s *Service) QuarantineIssue(ctx context.Context, issueID uint) (*Quarantine, error) {
+	issue, err := s.replicaSet.ReadRepo().GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsQuarantinable(issue.Kind) {
+		return nil, fmt.Errorf("[%w] the issue of the kind %q is not of a single point", apperror.ErrBadRequest, issue.Kind)
+	}
+	item := Quarantine{
+		Series:     issue.Series,
+		CurrencyID: issue.CurrencyID,
+		Ts:         issue.Ts,
+		Reason:     issue.Message,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err = s.replicaSet.WriteRepo().MQuarantine(ctx, &QuarantineList{item}); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ReleaseIssue returns the point of the issue to the reads of the series.
+func (s *Service) ReleaseIssue(ctx context.Context, issueID uint) error {
+	issue, err := s.replicaSet.ReadRepo().GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	return s.replicaSet.WriteRepo().Release(ctx, issue.Series, issue.CurrencyID, issue.Ts)
+}
+
+func isSeries(s string) bool {
+	for _, item := range SeriesList {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isKind(s string) bool {
+	for _, item := range KindList {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
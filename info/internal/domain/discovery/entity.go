@@ -0,0 +1,145 @@
+package discovery
+
+import (
+	"fmt"
+	"info/internal/domain/currency"
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Action_Promote = "promote"
+	Action_Demote  = "demote"
+
+	defaultListingLimit = 200
+	defaultGracePeriod  = 7 * 24 * time.Hour
+)
+
+var ActionList = []interface{}{
+	Action_Promote,
+	Action_Demote,
+}
+
+// ActionValidate validates the action of an event; the empty action means all the actions.
+func ActionValidate(s string) error {
+	return validation.Validate(s, validation.In(ActionList...))
+}
+
+// Config is the criteria of the discovery.
+// A coin matches if it is not excluded and it is in the top TopN by rank or its market cap or its daily volume is above the threshold.
+// A zero criterion is switched off.
+type Config struct {
+	TopN         uint
+	MinMarketCap float64
+	MinVolume24h float64
+	ListingLimit uint          // number of the coins requested from the listings, default 200
+	GracePeriod  time.Duration // a promoted coin out of the criteria during this time is demoted, default 7 days
+	ExcludeSlugs []string
+	ExcludeTags  []string // e.g. stablecoin, wrapped-tokens
+}
+
+func (c *Config) listingLimit() uint {
+	if c.ListingLimit > 0 {
+		return c.ListingLimit
+	}
+	if c.TopN > defaultListingLimit {
+		return c.TopN
+	}
+	return defaultListingLimit
+}
+
+func (c *Config) gracePeriod() time.Duration {
+	if c.GracePeriod == 0 {
+		return defaultGracePeriod
+	}
+	return c.GracePeriod
+}
+
+func (c *Config) isExcluded(item *Listing) bool {
+	if slices.Contains(c.ExcludeSlugs, item.Currency.Slug) {
+		return true
+	}
+	var tag string
+	for _, tag = range item.Tags {
+		if slices.Contains(c.ExcludeTags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the reason why the coin matches the criteria; the empty reason means that it does not match.
+func (c *Config) match(item *Listing) string {
+	if c.isExcluded(item) {
+		return ""
+	}
+	switch {
+	case c.TopN > 0 && item.Currency.CmcRank > 0 && item.Currency.CmcRank <= c.TopN:
+		return fmt.Sprintf("rank %d is in the top %d", item.Currency.CmcRank, c.TopN)
+	case c.MinMarketCap > 0 && item.MarketCap >= c.MinMarketCap:
+		return fmt.Sprintf("market cap %.0f is above %.0f", item.MarketCap, c.MinMarketCap)
+	case c.MinVolume24h > 0 && item.Volume24h >= c.MinVolume24h:
+		return fmt.Sprintf("daily volume %.0f is above %.0f", item.Volume24h, c.MinVolume24h)
+	}
+	return ""
+}
+
+// Listing is a coin from the listings of CMC
+type Listing struct {
+	Currency  currency.Currency
+	MarketCap float64
+	Volume24h float64
+	Tags      []string
+}
+
+type ListingList []Listing
+
+// State is the coin put on the watchlist by the discovery
+type State struct {
+	CurrencyID    uint
+	Slug          string
+	PromotedAt    time.Time
+	LastMatchedAt time.Time
+}
+
+type StateList []State
+
+type StateMap map[uint]State
+
+// Event is a promotion or a demotion of the coin
+type Event struct {
+	ID         uint
+	CurrencyID uint
+	Slug       string
+	Action     string
+	Reason     string
+	CmcRank    uint
+	MarketCap  float64
+	Volume24h  float64
+	CreatedAt  time.Time
+}
+
+func newEvent(action string, reason string, item *Listing, now time.Time) Event {
+	return Event{
+		CurrencyID: item.Currency.ID,
+		Slug:       item.Currency.Slug,
+		Action:     action,
+		Reason:     reason,
+		CmcRank:    item.Currency.CmcRank,
+		MarketCap:  item.MarketCap,
+		Volume24h:  item.Volume24h,
+		CreatedAt:  now,
+	}
+}
+
+type EventList []Event
+
+// EventFilter filters the events; the empty fields are not applied.
+type EventFilter struct {
+	Action string
+	Slug   string
+	Since  *time.Time
+	Limit  uint
+}
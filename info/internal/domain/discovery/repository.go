@@ -0,0 +1,23 @@
+package discovery
+
+import (
+	"context"
+	"info/internal/domain"
+)
+
+type ReplicaSet interface {
+	WriteRepo() WriteRepository
+	ReadRepo() ReadRepository
+}
+
+type WriteRepository interface {
+	Begin(ctx context.Context) (domain.Tx, error)
+	MUpsertStateTx(ctx context.Context, tx domain.Tx, entities *StateList) error
+	MDeleteStateTx(ctx context.Context, tx domain.Tx, currencyIDs *[]uint) error
+	MCreateEventTx(ctx context.Context, tx domain.Tx, entities *EventList) error
+}
+
+type ReadRepository interface {
+	GetStateMap(ctx context.Context) (StateMap, error)
+	GetEvents(ctx context.Context, filter *EventFilter) (*EventList, error)
+}
@@ -0,0 +1,204 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain"
+	"info/internal/domain/currency"
+	"info/internal/pkg/apperror"
+	"runtime/debug"
+	"time"
+)
+
+type CmcProApi interface {
+	GetListings(ctx context.Context, limit uint) (*ListingList, error)
+}
+
+type Service struct {
+	replicaSet ReplicaSet
+	currency   *currency.Service
+	cmcProApi  CmcProApi
+}
+
+func NewService(replicaSet ReplicaSet, currency *currency.Service, cmcProApi CmcProApi) *Service {
+	return &Service{
+		replicaSet: replicaSet,
+		currency:   currency,
+		cmcProApi:  cmcProApi,
+	}
+}
+
+// Discover puts on the watchlist the coins from the CMC listings matching the criteria of cfg
+// and takes off it the coins put by the discovery which have been out of the criteria during the grace period.
+// The coins put on the watchlist manually are never demoted. Returns the events of the run.
+func (s *Service) Discover(ctx context.Context, cfg *Config) (events *EventList, err error) {
+	const metricName = "discovery.Service.Discover"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	listings, err := s.cmcProApi.GetListings(ctx, cfg.listingLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	observed, err := s.observedIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stateMap, err := s.replicaSet.ReadRepo().GetStateMap(ctx)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		stateMap = make(StateMap)
+	}
+
+	now := time.Now().UTC()
+	res := make(EventList, 0)
+	promoted := make(currency.CurrencyList, 0)
+	upsertStates := make(StateList, 0, len(stateMap))
+	listingMap := make(map[uint]*Listing, len(*listings))
+	matched := make(map[uint]struct{})
+
+	var ok bool
+	var reason string
+	var state State
+	for i := range *listings {
+		item := &(*listings)[i]
+		listingMap[item.Currency.ID] = item
+		if reason = cfg.match(item); reason == "" {
+			continue
+		}
+		matched[item.Currency.ID] = struct{}{}
+
+		if _, ok = observed[item.Currency.ID]; ok {
+			if state, ok = stateMap[item.Currency.ID]; ok {
+				state.LastMatchedAt = now
+				upsertStates = append(upsertStates, state)
+			}
+			continue
+		}
+
+		promoted = append(promoted, item.Currency)
+		upsertStates = append(upsertStates, State{
+			CurrencyID:    item.Currency.ID,
+			Slug:          item.Currency.Slug,
+			PromotedAt:    now,
+			LastMatchedAt: now,
+		})
+		res = append(res, newEvent(Action_Promote, reason, item, now))
+	}
+
+	demotedSlugs := make([]string, 0)
+	deleteStateIDs := make([]uint, 0)
+	var currencyID uint
+	var item *Listing
+	for currencyID, state = range stateMap {
+		if _, ok = matched[currencyID]; ok {
+			continue
+		}
+		// снята с наблюдения вручную - дальше не отслеживаем
+		if _, ok = observed[currencyID]; !ok {
+			deleteStateIDs = append(deleteStateIDs, currencyID)
+			continue
+		}
+		if now.Sub(state.LastMatchedAt) < cfg.gracePeriod() {
+			continue
+		}
+
+		if item, ok = listingMap[currencyID]; !ok {
+			item = &Listing{
+				Currency: currency.Currency{
+					ID:   currencyID,
+					Slug: state.Slug,
+				},
+			}
+		}
+		demotedSlugs = append(demotedSlugs, state.Slug)
+		deleteStateIDs = append(deleteStateIDs, currencyID)
+		res = append(res, newEvent(Action_Demote, fmt.Sprintf("out of the criteria since %s", state.LastMatchedAt.Format(time.RFC3339)), item, now))
+	}
+
+	if err = s.save(ctx, &promoted, &upsertStates, &demotedSlugs, &deleteStateIDs, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// save applies the promotions, the demotions and the states and creates the events in one transaction,
+// so the watchlist is never changed without the events.
+func (s *Service) save(ctx context.Context, promoted *currency.CurrencyList, upsertStates *StateList, demotedSlugs *[]string, deleteStateIDs *[]uint, events *EventList) (err error) {
+	const metricName = "discovery.Service.save"
+
+	var tx domain.Tx
+	tx, err = s.replicaSet.WriteRepo().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+		if err == nil {
+			if err = tx.Commit(ctx); err == nil {
+				return
+			}
+			err = fmt.Errorf("[%w] "+metricName+" Commit error: %w", apperror.ErrInternal, err)
+		}
+		if err2 := tx.Rollback(ctx); err2 != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Rollback error: %w", apperror.ErrInternal, err2))
+		}
+	}()
+
+	if err = s.currency.WatchlistAddCurrenciesTx(ctx, tx, promoted); err != nil {
+		return err
+	}
+	if err = s.replicaSet.WriteRepo().MUpsertStateTx(ctx, tx, upsertStates); err != nil {
+		return err
+	}
+	if len(*demotedSlugs) > 0 {
+		if err = s.currency.WatchlistRemoveTx(ctx, tx, demotedSlugs); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+	}
+	if err = s.replicaSet.WriteRepo().MDeleteStateTx(ctx, tx, deleteStateIDs); err != nil {
+		return err
+	}
+	return s.replicaSet.WriteRepo().MCreateEventTx(ctx, tx, events)
+}
+
+// Events returns the promotions and the demotions, the newest first.
+func (s *Service) Events(ctx context.Context, filter *EventFilter) (*EventList, error) {
+	events, err := s.replicaSet.ReadRepo().GetEvents(ctx, filter)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return &EventList{}, nil
+		}
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Service) observedIDs(ctx context.Context) (map[uint]struct{}, error) {
+	currencyList, err := s.currency.Watchlist(ctx)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return map[uint]struct{}{}, nil
+		}
+		return nil, err
+	}
+
+	res := make(map[uint]struct{}, len(*currencyList))
+	var item currency.Currency
+	for _, item = range *currencyList {
+		res[item.ID] = struct{}{}
+	}
+	return res, nil
+}
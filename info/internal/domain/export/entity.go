@@ -0,0 +1,134 @@
+package export
+
+import (
+	"info/internal/pkg/parquet"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Dataset_PriceAndCap             = "price_and_cap"
+	Dataset_Concentration           = "concentration"
+	Dataset_OraculAnalytics         = "oracul_analytics"
+	Dataset_OraculSpeedometers      = "oracul_speedometers"
+	Dataset_OraculHolderStats       = "oracul_holder_stats"
+	Dataset_OraculDailyBalanceStats = "oracul_daily_balance_stats"
+	Dataset_PortfolioHistory        = "portfolio_history"
+
+	Format_CSV     = "csv"
+	Format_NDJSON  = "ndjson"
+	Format_Parquet = "parquet"
+)
+
+var DatasetList = []interface{}{
+	Dataset_PriceAndCap,
+	Dataset_Concentration,
+	Dataset_OraculAnalytics,
+	Dataset_OraculSpeedometers,
+	Dataset_OraculHolderStats,
+	Dataset_OraculDailyBalanceStats,
+	Dataset_PortfolioHistory,
+}
+
+var FormatList = []interface{}{
+	Format_CSV,
+	Format_NDJSON,
+	Format_Parquet,
+}
+
+var contentTypes = map[string]string{
+	Format_CSV:     "text/csv; charset=utf-8",
+	Format_NDJSON:  "application/x-ndjson",
+	Format_Parquet: "application/vnd.apache.parquet",
+}
+
+// Columns of the datasets in the order of the values of the rows of the repository
+var Columns = map[string][]parquet.Column{
+	Dataset_PriceAndCap: {
+		{Name: "currency_id", Type: parquet.Type_Int64}, {Name: "price", Type: parquet.Type_Double}, {Name: "daily_volume", Type: parquet.Type_Double}, {Name: "cap", Type: parquet.Type_Double},
+		{Name: "ts", Type: parquet.Type_Timestamp},
+	},
+	Dataset_Concentration: {
+		{Name: "currency_id", Type: parquet.Type_Int64}, {Name: "whales", Type: parquet.Type_Double}, {Name: "investors", Type: parquet.Type_Double}, {Name: "retail", Type: parquet.Type_Double},
+		{Name: "d", Type: parquet.Type_Date},
+	},
+	Dataset_OraculAnalytics: {
+		{Name: "currency_id", Type: parquet.Type_Int64}, {Name: "whales_concentration", Type: parquet.Type_Double}, {Name: "worm_index", Type: parquet.Type_Double}, {Name: "growth_fuel", Type: parquet.Type_Double},
+		{Name: "ts", Type: parquet.Type_Timestamp},
+	},
+	Dataset_OraculSpeedometers: {
+		{Name: "currency_id", Type: parquet.Type_Int64},
+		{Name: "whales_buy_rate", Type: parquet.Type_Double}, {Name: "whales_sell_rate", Type: parquet.Type_Double}, {Name: "whales_volume", Type: parquet.Type_Double},
+		{Name: "investors_buy_rate", Type: parquet.Type_Double}, {Name: "investors_sell_rate", Type: parquet.Type_Double}, {Name: "investors_volume", Type: parquet.Type_Double},
+		{Name: "retailers_buy_rate", Type: parquet.Type_Double}, {Name: "retailers_sell_rate", Type: parquet.Type_Double}, {Name: "retailers_volume", Type: parquet.Type_Double},
+		{Name: "ts", Type: parquet.Type_Timestamp},
+	},
+	Dataset_OraculHolderStats: {
+		{Name: "currency_id", Type: parquet.Type_Int64},
+		{Name: "whales_volume", Type: parquet.Type_Double}, {Name: "whales_total_holders", Type: parquet.Type_Int64},
+		{Name: "investors_volume", Type: parquet.Type_Double}, {Name: "investors_total_holders", Type: parquet.Type_Int64},
+		{Name: "retailers_volume", Type: parquet.Type_Double}, {Name: "retailers_total_holders", Type: parquet.Type_Int64},
+		{Name: "ts", Type: parquet.Type_Timestamp},
+	},
+	Dataset_OraculDailyBalanceStats: {
+		{Name: "currency_id", Type: parquet.Type_Int64},
+		{Name: "whales_balance", Type: parquet.Type_Double}, {Name: "whales_total_holders", Type: parquet.Type_Int64},
+		{Name: "investors_balance", Type: parquet.Type_Double}, {Name: "investors_total_holders", Type: parquet.Type_Int64},
+		{Name: "retailers_balance", Type: parquet.Type_Double}, {Name: "retailers_total_holders", Type: parquet.Type_Int64},
+		{Name: "d", Type: parquet.Type_Date},
+	},
+	Dataset_PortfolioHistory: {
+		{Name: "portfolio_source_id", Type: parquet.Type_String}, {Name: "currency_id", Type: parquet.Type_Int64},
+		{Name: "amount", Type: parquet.Type_Double}, {Name: "current_price", Type: parquet.Type_Double}, {Name: "crypto_holdings", Type: parquet.Type_Double}, {Name: "holdings_percent", Type: parquet.Type_Double},
+		{Name: "buy_avg_price", Type: parquet.Type_Double}, {Name: "pl_percent_value", Type: parquet.Type_Double}, {Name: "pl_value", Type: parquet.Type_Double}, {Name: "total_buy_spent", Type: parquet.Type_Double},
+		{Name: "updated_at", Type: parquet.Type_Timestamp}, {Name: "ts", Type: parquet.Type_Timestamp},
+	},
+}
+
+// Params of the export: the currencies by Slugs and IDs (all the currencies if both are empty) and the window, both ends are inclusive.
+type Params struct {
+	Dataset string
+	Format  string
+	Slugs   []string
+	IDs     []uint
+	From    time.Time
+	To      time.Time
+}
+
+// SetDefaults sets the empty Format to csv and the empty To to now; the empty From exports from the first row.
+func (e *Params) SetDefaults() {
+	if e.Format == "" {
+		e.Format = Format_CSV
+	}
+	if e.To.IsZero() {
+		e.To = time.Now().UTC()
+	}
+}
+
+func (e *Params) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.Dataset, validation.Required, validation.In(DatasetList...)),
+		validation.Field(&e.Format, validation.Required, validation.In(FormatList...)),
+		validation.Field(&e.To, validation.Required, validation.Min(e.From)),
+	)
+}
+
+// Filter of the rows of the dataset; the empty CurrencyIDs is all the currencies.
+type Filter struct {
+	Dataset     string
+	Format      string
+	CurrencyIDs []uint
+	From        time.Time
+	To          time.Time
+}
+
+// ContentType returns the MIME type of the format.
+func (e *Filter) ContentType() string {
+	return contentTypes[e.Format]
+}
+
+// FileName returns the name of the file of the export, e.g. price_and_cap.csv
+func (e *Filter) FileName() string {
+	return e.Dataset + "." + e.Format
+}
@@ -0,0 +1,13 @@
+package export
+
+import "context"
+
+type ReplicaSet interface {
+	ReadRepo() ReadRepository
+}
+
+type ReadRepository interface {
+	// Stream reads the rows of the dataset by the filter ordered by the currency and the time with the cursor and calls fn for every row;
+	// the values are in the order of Columns of the dataset, the row is reused between the calls.
+	Stream(ctx context.Context, filter *Filter, fn func(row []any) error) error
+}
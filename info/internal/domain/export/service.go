@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain/currency"
+	"info/internal/pkg/apperror"
+	"io"
+	"runtime/debug"
+)
+
+type Service struct {
+	replicaSet ReplicaSet
+	currency   *currency.Service
+}
+
+func NewService(replicaSet ReplicaSet, currency *currency.Service) *Service {
+	return &Service{
+		replicaSet: replicaSet,
+		currency:   currency,
+	}
+}
+
+// Prepare validates the params and returns the filter of the export with the IDs of the currencies.
+// It is separated from Export so the bad request is reported before the first byte of the export is written.
+func (s *Service) Prepare(ctx context.Context, params *Params) (*Filter, error) {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] export params error: %w", apperror.ErrBadRequest, err)
+	}
+
+	res := &Filter{
+		Dataset: params.Dataset,
+		Format:  params.Format,
+		From:    params.From,
+		To:      params.To,
+	}
+	if len(params.Slugs) == 0 && len(params.IDs) == 0 {
+		return res, nil
+	}
+
+	currencyList, err := s.currency.MGetBySlugsAndIDs(ctx, &params.Slugs, &params.IDs)
+	if err != nil {
+		return nil, err
+	}
+	res.CurrencyIDs = *currencyList.IDs()
+	return res, nil
+}
+
+// Export streams the rows of the dataset by the filter to w in the format of the filter and returns the number of the rows.
+// The rows are read with the cursor, so the memory does not depend on the size of the export.
+func (s *Service) Export(ctx context.Context, filter *Filter, w io.Writer) (rowsNb uint, err error) {
+	const metricName = "export.Service.Export"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	columns, ok := Columns[filter.Dataset]
+	if !ok {
+		return 0, fmt.Errorf("[%w] unknown dataset: %q", apperror.ErrBadRequest, filter.Dataset)
+	}
+	bw := bufio.NewWriter(w)
+	rw, err := NewRowWriter(filter.Format, bw, columns)
+	if err != nil {
+		return 0, fmt.Errorf("[%w] %w", apperror.ErrBadRequest, err)
+	}
+
+	err = s.replicaSet.ReadRepo().Stream(ctx, filter, func(row []any) error {
+		if err := rw.Write(row); err != nil {
+			return fmt.Errorf("[%w] %s write row error: %w", apperror.ErrInternal, metricName, err)
+		}
+		rowsNb++
+		return nil
+	})
+	if err != nil {
+		return rowsNb, err
+	}
+	if err = rw.Close(); err != nil {
+		return rowsNb, fmt.Errorf("[%w] %s close error: %w", apperror.ErrInternal, metricName, err)
+	}
+	if err = bw.Flush(); err != nil {
+		return rowsNb, fmt.Errorf("[%w] %s flush error: %w", apperror.ErrInternal, metricName, err)
+	}
+	return rowsNb, nil
+}
@@ -0,0 +1,124 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"info/internal/pkg/parquet"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RowWriter writes the rows of the dataset in the format; Close writes the rest of the file, it does not close the underlying writer.
+type RowWriter interface {
+	Write(row []any) error
+	Close() error
+}
+
+func NewRowWriter(format string, w io.Writer, columns []parquet.Column) (RowWriter, error) {
+	switch format {
+	case Format_CSV:
+		return newCsvWriter(w, columns)
+	case Format_NDJSON:
+		return &ndjsonWriter{w: w, columns: columns}, nil
+	case Format_Parquet:
+		return parquet.NewWriter(w, columns, parquet.DefaultRowGroupSize), nil
+	default:
+		return nil, fmt.Errorf("unknown export format: %q", format)
+	}
+}
+
+// formatValue returns the value as text: the times are in RFC3339 in UTC, the dates are in YYYY-MM-DD.
+func formatValue(c parquet.Column, value any) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	case time.Time:
+		if c.Type == parquet.Type_Date {
+			return v.Format(time.DateOnly)
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []parquet.Column
+	record  []string
+}
+
+func newCsvWriter(w io.Writer, columns []parquet.Column) (*csvWriter, error) {
+	res := &csvWriter{
+		w:       csv.NewWriter(w),
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}
+	for i, c := range columns {
+		res.record[i] = c.Name
+	}
+	if err := res.w.Write(res.record); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (w *csvWriter) Write(row []any) error {
+	for i, c := range w.columns {
+		w.record[i] = formatValue(c, row[i])
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// ndjsonWriter writes the row as the JSON object with the keys in the order of the columns; NaN and infinities are null.
+type ndjsonWriter struct {
+	w       io.Writer
+	columns []parquet.Column
+	buf     []byte
+}
+
+func (w *ndjsonWriter) Write(row []any) error {
+	w.buf = append(w.buf[:0], '{')
+	for i, c := range w.columns {
+		if i > 0 {
+			w.buf = append(w.buf, ',')
+		}
+		w.buf = strconv.AppendQuote(w.buf, c.Name)
+		w.buf = append(w.buf, ':')
+		switch v := row[i].(type) {
+		case int64:
+			w.buf = strconv.AppendInt(w.buf, v, 10)
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				w.buf = append(w.buf, "null"...)
+			} else {
+				w.buf = strconv.AppendFloat(w.buf, v, 'f', -1, 64)
+			}
+		default:
+			b, err := json.Marshal(formatValue(c, v))
+			if err != nil {
+				return err
+			}
+			w.buf = append(w.buf, b...)
+		}
+	}
+	w.buf = append(w.buf, '}', '\n')
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type ImportFailure struct {
+	Key string
+	Err error
+}
+
+// ImportError is returned by an import that continues after the failures of the single items.
+// It is safe to add the results from several goroutines.
+type ImportError struct {
+	mu        sync.Mutex
+	Succeeded []string
+	Failed    []ImportFailure
+}
+
+func NewImportError(capacity int) *ImportError {
+	return &ImportError{
+		Succeeded: make([]string, 0, capacity),
+	}
+}
+
+func (e *ImportError) AddSuccess(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Succeeded = append(e.Succeeded, key)
+}
+
+func (e *ImportError) AddFailure(key string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.Failed = append(e.Failed, ImportFailure{
+		Key: key,
+		Err: err,
+	})
+}
+
+// Err returns nil if there are no failures.
+func (e *ImportError) Err() error {
+	if e == nil || len(e.Failed) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ImportError) Error() string {
+	b := strings.Builder{}
+	b.WriteString("import completed with errors; succeeded: " + strconv.Itoa(len(e.Succeeded)) + " [" + strings.Join(e.Succeeded, ", ") + "]; failed: " + strconv.Itoa(len(e.Failed)) + " [")
+	for i, item := range e.Failed {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(item.Key + ": " + item.Err.Error())
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func (e *ImportError) Unwrap() []error {
+	res := make([]error, 0, len(e.Failed))
+	for _, item := range e.Failed {
+		res = append(res, item.Err)
+	}
+	return res
+}
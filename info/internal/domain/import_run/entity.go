@@ -0,0 +1,151 @@
+package import_run
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"info/internal/pkg/apperror"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Kind_Currency  = "currency"
+	Kind_Portfolio = "portfolio"
+	Kind_Oracul    = "oracul"
+
+	Status_Running = "running"
+	Status_Success = "success"
+	Status_Partial = "partial"
+	Status_Failed  = "failed"
+)
+
+var KindList = []interface{}{
+	Kind_Currency,
+	Kind_Portfolio,
+	Kind_Oracul,
+}
+
+// KindValidate validates the kind of import; the empty kind means all the kinds.
+func KindValidate(s string) error {
+	return validation.Validate(s, validation.In(KindList...))
+}
+
+type ImportRun struct {
+	ID             uint
+	Kind           string
+	Status         string
+	ItemsSucceeded uint
+	ItemsFailed    uint
+	Error          *string
+	StartedAt      time.Time
+	FinishedAt     *time.Time
+}
+
+type ImportRunList []ImportRun
+
+// RowsMap is the number of the upserted rows by the table name
+type RowsMap map[string]uint
+
+func (m RowsMap) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *RowsMap) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("[%w] type assertion to []byte failed for value: %v", apperror.ErrData, src)
+	}
+	return json.Unmarshal(data, m)
+}
+
+// ImportRunItem is the import of a single currency or portfolio in the import run
+type ImportRunItem struct {
+	ID          uint
+	ImportRunID uint
+	Kind        string
+	Key         string
+	CurrencyID  *uint
+	Rows        RowsMap
+	TimeFrom    *time.Time
+	TimeTo      *time.Time
+	Error       *string
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
+
+func NewImportRunItem(kind string, key string, currencyID *uint) *ImportRunItem {
+	return &ImportRunItem{
+		Kind:       kind,
+		Key:        key,
+		CurrencyID: currencyID,
+		Rows:       make(RowsMap),
+		StartedAt:  time.Now().UTC(),
+	}
+}
+
+// AddRows adds the number of the upserted rows of the table and extends the fetched time range. It does nothing with nil item.
+func (e *ImportRunItem) AddRows(table string, rows int, timeFrom *time.Time, timeTo *time.Time) {
+	if e == nil {
+		return
+	}
+	e.Rows[table] += uint(rows)
+	if timeFrom != nil && (e.TimeFrom == nil || timeFrom.Before(*e.TimeFrom)) {
+		e.TimeFrom = timeFrom
+	}
+	if timeTo != nil && (e.TimeTo == nil || timeTo.After(*e.TimeTo)) {
+		e.TimeTo = timeTo
+	}
+}
+
+func (e *ImportRunItem) finish(err error) {
+	e.FinishedAt = time.Now().UTC()
+	if err != nil {
+		errMsg := err.Error()
+		e.Error = &errMsg
+	}
+}
+
+// ItemStatus is the state of the imports of a single currency or portfolio
+type ItemStatus struct {
+	Kind           string
+	Key            string
+	CurrencyID     *uint
+	LastStartedAt  time.Time
+	LastFinishedAt time.Time
+	LastSuccessAt  *time.Time
+	LastError      *string
+	IsStale        bool
+}
+
+func (e *ItemStatus) IsFailing() bool {
+	return e.LastError != nil
+}
+
+type ItemStatusList []ItemStatus
+
+// Problems returns the stale and the failing items only
+func (l *ItemStatusList) Problems() *ItemStatusList {
+	if l == nil {
+		return nil
+	}
+	res := make(ItemStatusList, 0, len(*l))
+	var item ItemStatus
+	for _, item = range *l {
+		if item.IsStale || item.IsFailing() {
+			res = append(res, item)
+		}
+	}
+	return &res
+}
+
+type Status struct {
+	Runs  *ImportRunList
+	Items *ItemStatusList
+}
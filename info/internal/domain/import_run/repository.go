@@ -0,0 +1,21 @@
+package import_run
+
+import (
+	"context"
+)
+
+type ReplicaSet interface {
+	WriteRepo() WriteRepository
+	ReadRepo() ReadRepository
+}
+
+type WriteRepository interface {
+	Create(ctx context.Context, entity *ImportRun) (ID uint, err error)
+	Update(ctx context.Context, entity *ImportRun) error
+	CreateItem(ctx context.Context, entity *ImportRunItem) error
+}
+
+type ReadRepository interface {
+	GetLastRuns(ctx context.Context, kind string, limit uint) (*ImportRunList, error)
+	GetItemStatusList(ctx context.Context, kind string) (*ItemStatusList, error)
+}
@@ -0,0 +1,140 @@
+package import_run
+
+import (
+	"context"
+	"errors"
+	"info/internal/pkg/apperror"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLimit      = 10
+	DefaultStaleAfter = 48 * time.Hour
+)
+
+type Service struct {
+	replicaSet ReplicaSet
+}
+
+func NewService(replicaSet ReplicaSet) *Service {
+	return &Service{
+		replicaSet: replicaSet,
+	}
+}
+
+// Journal writes an import run and its items. It is safe to add the items from several goroutines.
+type Journal struct {
+	service *Service
+	mu      sync.Mutex
+	run     *ImportRun
+	errs    []error
+}
+
+// Start creates the import run. The import must not fail because of the journal, so the errors of the journal are kept
+// and returned by Finish.
+func (s *Service) Start(ctx context.Context, kind string) *Journal {
+	j := &Journal{
+		service: s,
+		run: &ImportRun{
+			Kind:      kind,
+			Status:    Status_Running,
+			StartedAt: time.Now().UTC(),
+		},
+	}
+	ID, err := s.replicaSet.WriteRepo().Create(ctx, j.run)
+	if err != nil {
+		j.errs = append(j.errs, err)
+		return j
+	}
+	j.run.ID = ID
+	return j
+}
+
+// AddItem finishes the item with the error of its import and writes it; the write is out of the lock, so the workers do not wait for each other.
+func (j *Journal) AddItem(ctx context.Context, item *ImportRunItem, importErr error) {
+	item.finish(importErr)
+
+	j.mu.Lock()
+	if importErr != nil {
+		j.run.ItemsFailed++
+	} else {
+		j.run.ItemsSucceeded++
+	}
+	runID := j.run.ID
+	j.mu.Unlock()
+
+	if runID == 0 {
+		return
+	}
+	item.ImportRunID = runID
+	if err := j.service.replicaSet.WriteRepo().CreateItem(context.WithoutCancel(ctx), item); err != nil {
+		j.mu.Lock()
+		j.errs = append(j.errs, err)
+		j.mu.Unlock()
+	}
+}
+
+// Finish writes the result of the import run and returns the errors of the journal.
+func (j *Journal) Finish(ctx context.Context, importErr error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	finishedAt := time.Now().UTC()
+	j.run.FinishedAt = &finishedAt
+	switch {
+	case importErr == nil && j.run.ItemsFailed == 0:
+		j.run.Status = Status_Success
+	case j.run.ItemsSucceeded > 0:
+		j.run.Status = Status_Partial
+	default:
+		j.run.Status = Status_Failed
+	}
+	if importErr != nil {
+		errMsg := importErr.Error()
+		j.run.Error = &errMsg
+	}
+
+	if j.run.ID != 0 {
+		if err := j.service.replicaSet.WriteRepo().Update(context.WithoutCancel(ctx), j.run); err != nil {
+			j.errs = append(j.errs, err)
+		}
+	}
+	return errors.Join(j.errs...)
+}
+
+// Status returns the last import runs and the state of every imported item; an item without a successful import during staleAfter is stale.
+func (s *Service) Status(ctx context.Context, kind string, limit uint, staleAfter time.Duration) (*Status, error) {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if staleAfter == 0 {
+		staleAfter = DefaultStaleAfter
+	}
+
+	runs, err := s.replicaSet.ReadRepo().GetLastRuns(ctx, kind, limit)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		runs = &ImportRunList{}
+	}
+
+	items, err := s.replicaSet.ReadRepo().GetItemStatusList(ctx, kind)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		items = &ItemStatusList{}
+	}
+
+	staleTime := time.Now().UTC().Add(-staleAfter)
+	for i := range *items {
+		(*items)[i].IsStale = (*items)[i].LastSuccessAt == nil || (*items)[i].LastSuccessAt.Before(staleTime)
+	}
+
+	return &Status{
+		Runs:  runs,
+		Items: items,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package oracul
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	defaultHistoryPeriod = time.Hour * 24 * 90
+)
+
+// HistoryParams is the window [From, To] of the history of the currency in any Oracul dataset.
+type HistoryParams struct {
+	CurrencyID uint
+	From       time.Time
+	To         time.Time
+}
+
+// SetDefaults sets the empty To to now and the empty From to 90 days before To.
+func (e *HistoryParams) SetDefaults() {
+	if e.To.IsZero() {
+		e.To = time.Now().UTC()
+	}
+	if e.From.IsZero() {
+		e.From = e.To.Add(-defaultHistoryPeriod)
+	}
+}
+
+func (e *HistoryParams) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.CurrencyID, validation.Required),
+		validation.Field(&e.From, validation.Required),
+		validation.Field(&e.To, validation.Required, validation.Min(e.From)),
+	)
+}
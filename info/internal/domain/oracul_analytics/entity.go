@@ -1,6 +1,7 @@
 package oracul_analytics
 
 import (
+	"strconv"
 	"time"
 )
 
@@ -18,10 +19,17 @@ func (e *OraculAnalytics) Validate() error {
 	return nil
 }
 
+type OraculAnalyticsList []OraculAnalytics
+
 type TokenAddress struct {
 	CurrencyID uint
 	Blockchain string
 	Address    string
 }
 
+// Key identifies the token in the import journal and in the import errors.
+func (e *TokenAddress) Key() string {
+	return strconv.FormatUint(uint64(e.CurrencyID), 10) + "/" + e.Blockchain + "/" + e.Address
+}
+
 type TokenAddressList []TokenAddress
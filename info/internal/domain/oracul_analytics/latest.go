@@ -0,0 +1,44 @@
+package oracul_analytics
+
+import (
+	"context"
+	"errors"
+	"info/internal/domain/oracul_daily_balance_stats"
+	"info/internal/domain/oracul_holder_stats"
+	"info/internal/domain/oracul_speedometers"
+	"info/internal/pkg/apperror"
+)
+
+// Latest is the latest data of every Oracul dataset of the currency; the dataset without the data is nil.
+type Latest struct {
+	CurrencyID        uint
+	Analytics         *OraculAnalytics
+	Speedometers      *oracul_speedometers.OraculSpeedometers
+	HolderStats       *oracul_holder_stats.OraculHolderStats
+	DailyBalanceStats *oracul_daily_balance_stats.OraculDailyBalanceStats
+}
+
+// Latest returns the latest data of every Oracul dataset of the currency; it returns ErrNotFound if there is no data at all.
+func (s *Service) Latest(ctx context.Context, currencyID uint) (*Latest, error) {
+	res := &Latest{
+		CurrencyID: currencyID,
+	}
+	var err error
+	if res.Analytics, err = s.GetLast(ctx, currencyID); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if res.Speedometers, err = s.oraculSpeedometers.GetLast(ctx, currencyID); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if res.HolderStats, err = s.oraculHolderStats.GetLast(ctx, currencyID); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if res.DailyBalanceStats, err = s.oraculDailyBalanceStats.GetLast(ctx, currencyID); err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+
+	if res.Analytics == nil && res.Speedometers == nil && res.HolderStats == nil && res.DailyBalanceStats == nil {
+		return nil, apperror.ErrNotFound
+	}
+	return res, nil
+}
@@ -2,6 +2,7 @@ package oracul_analytics
 
 import (
 	"context"
+	"time"
 )
 
 type ReplicaSet interface {
@@ -14,4 +15,7 @@ type WriteRepository interface {
 }
 
 type ReadRepository interface {
+	GetLast(ctx context.Context, currencyID uint) (*OraculAnalytics, error)
+	GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*OraculAnalyticsList, error)
+	MGetLast(ctx context.Context, currencyIDs *[]uint) (*OraculAnalyticsList, error)
 }
@@ -2,12 +2,25 @@ package oracul_analytics
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"info/internal/domain"
+	"info/internal/domain/import_run"
+	"info/internal/domain/oracul"
 	"info/internal/domain/oracul_daily_balance_stats"
 	"info/internal/domain/oracul_holder_stats"
 	"info/internal/domain/oracul_speedometers"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/workerpool"
+	"runtime/debug"
 	"sort"
-	"time"
+)
+
+const (
+	table_Analytics         = "oracul.analytics"
+	table_Speedometers      = "oracul.speedometers"
+	table_HolderStats       = "oracul.holder_stats"
+	table_DailyBalanceStats = "oracul.daily_balance_stats"
 )
 
 type OraculAnalyticsAPIClient interface {
@@ -28,9 +41,11 @@ type Service struct {
 	oraculHolderStats        *oracul_holder_stats.Service
 	oraculDailyBalanceStats  *oracul_daily_balance_stats.Service
 	supportedBlockchains     *sort.StringSlice
+	importRun                *import_run.Service
+	concurrency              uint
 }
 
-func NewService(replicaSet ReplicaSet, oraculAnalyticsAPIClient OraculAnalyticsAPIClient, oraculSpeedometers *oracul_speedometers.Service, oraculHolderStats *oracul_holder_stats.Service, oraculDailyBalanceStats *oracul_daily_balance_stats.Service) *Service {
+func NewService(replicaSet ReplicaSet, oraculAnalyticsAPIClient OraculAnalyticsAPIClient, oraculSpeedometers *oracul_speedometers.Service, oraculHolderStats *oracul_holder_stats.Service, oraculDailyBalanceStats *oracul_daily_balance_stats.Service, importRun *import_run.Service, concurrency uint) *Service {
 	s := sort.StringSlice([]string{"ETH", "BNB", "POL", "FTM", "OP"})
 	s.Sort()
 	return &Service{
@@ -40,6 +55,8 @@ func NewService(replicaSet ReplicaSet, oraculAnalyticsAPIClient OraculAnalyticsA
 		oraculHolderStats:        oraculHolderStats,
 		oraculDailyBalanceStats:  oraculDailyBalanceStats,
 		supportedBlockchains:     &s,
+		importRun:                importRun,
+		concurrency:              concurrency,
 	}
 }
 
@@ -59,56 +76,125 @@ func (s *Service) Import(ctx context.Context, tokenAddressList *TokenAddressList
 	if tokenAddressList == nil || len(*tokenAddressList) == 0 {
 		return nil
 	}
+	// данные Oracul хранятся по монете, поэтому для каждой монеты берётся первый токен в поддерживаемой сети;
+	// список упорядочен по приоритету (см. currency.TokenAddressList.SortByPriority): сначала основная платформа, затем по сети
+	supported := make([]TokenAddress, 0, len(*tokenAddressList))
+	currencyIDs := make(map[uint]struct{}, len(*tokenAddressList))
+	var ok bool
 	var tokenAddress TokenAddress
-	var importData *ImportData
-
-	fmt.Println("oracul_analytics.Service.Import - Started:")
 	for _, tokenAddress = range *tokenAddressList {
-		if !s.IsBlockchainSupported(tokenAddress.Blockchain) {
+		if _, ok = currencyIDs[tokenAddress.CurrencyID]; ok || !s.IsBlockchainSupported(tokenAddress.Blockchain) {
 			continue
 		}
-		fmt.Printf("	%d	...\n", tokenAddress.CurrencyID)
+		currencyIDs[tokenAddress.CurrencyID] = struct{}{}
+		supported = append(supported, tokenAddress)
+	}
 
-		time.Sleep(5 * time.Second)
-		importData, err = s.oraculAnalyticsAPIClient.GetHoldersStats(ctx, tokenAddress.CurrencyID, tokenAddress.Blockchain, tokenAddress.Address)
+	journal := s.importRun.Start(ctx, import_run.Kind_Oracul)
+	defer func() {
+		err = errors.Join(err, journal.Finish(ctx, err))
+	}()
+
+	importErr := domain.NewImportError(len(supported))
+	workerpool.Run(ctx, s.concurrency, supported, func(ctx context.Context, tokenAddress TokenAddress) {
+		key := tokenAddress.Key()
+		runItem := import_run.NewImportRunItem(import_run.Kind_Oracul, key, &tokenAddress.CurrencyID)
+		err := s.importWorkerItem(ctx, &tokenAddress, runItem)
+		// монеты без данных в Oracul - обычное дело, поэтому отсутствие данных не считается ошибкой ни импорта, ни журнала:
+		// токен импортирован без строк
+		if errors.Is(err, apperror.ErrNotFound) {
+			err = nil
+		}
+		journal.AddItem(ctx, runItem, err)
 		if err != nil {
-			fmt.Printf("oraculAnalyticsAPIClient.GetHoldersStats error CurrencyID: %d, error: %w\n", tokenAddress.CurrencyID, err)
-			continue
+			importErr.AddFailure(key, err)
+			return
 		}
-		if err = s.upsertImportData(ctx, importData); err != nil {
-			return err
+		importErr.AddSuccess(key)
+	})
+
+	return importErr.Err()
+}
+
+// importWorkerItem imports the data of the token in the worker of the pool: the panic is returned as the error of the token,
+// so it fails this token only and does not kill the whole import.
+func (s *Service) importWorkerItem(ctx context.Context, tokenAddress *TokenAddress, runItem *import_run.ImportRunItem) (err error) {
+	const metricName = "oracul_analytics.Service.importWorkerItem"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
 		}
-	}
-	fmt.Println("oracul_analytics.Service.Import - Complited!")
+	}()
 
-	return nil
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	importData, err := s.oraculAnalyticsAPIClient.GetHoldersStats(ctx, tokenAddress.CurrencyID, tokenAddress.Blockchain, tokenAddress.Address)
+	if err != nil {
+		return err
+	}
+	return s.upsertImportData(ctx, importData, runItem)
 }
 
-func (s *Service) upsertImportData(ctx context.Context, importData *ImportData) (err error) {
+func (s *Service) upsertImportData(ctx context.Context, importData *ImportData, runItem *import_run.ImportRunItem) (err error) {
 
 	if importData.OraculAnalytics != nil {
 		if err = s.replicaSet.WriteRepo().Upsert(ctx, importData.OraculAnalytics); err != nil {
 			return err
 		}
+		runItem.AddRows(table_Analytics, 1, nil, nil)
 	}
 
 	if importData.OraculSpeedometers != nil {
 		if err = s.oraculSpeedometers.Create(ctx, importData.OraculSpeedometers); err != nil {
 			return err
 		}
+		runItem.AddRows(table_Speedometers, 1, nil, nil)
 	}
 
 	if importData.OraculHolderStats != nil {
 		if err = s.oraculHolderStats.Create(ctx, importData.OraculHolderStats); err != nil {
 			return err
 		}
+		runItem.AddRows(table_HolderStats, 1, nil, nil)
 	}
 
 	if importData.OraculDailyBalanceStatsList != nil && len(*importData.OraculDailyBalanceStatsList) > 0 {
 		if err = s.oraculDailyBalanceStats.MCreate(ctx, importData.OraculDailyBalanceStatsList); err != nil {
 			return err
 		}
+		runItem.AddRows(table_DailyBalanceStats, len(*importData.OraculDailyBalanceStatsList), importData.OraculDailyBalanceStatsList.MinTime(), importData.OraculDailyBalanceStatsList.MaxTime())
 	}
 
 	return nil
 }
+
+// GetLast returns the latest analytics of the currency.
+func (s *Service) GetLast(ctx context.Context, currencyID uint) (*OraculAnalytics, error) {
+	return s.replicaSet.ReadRepo().GetLast(ctx, currencyID)
+}
+
+// MGetLast returns the latest analytics of every currency.
+func (s *Service) MGetLast(ctx context.Context, currencyIDs *[]uint) (*OraculAnalyticsList, error) {
+	return s.replicaSet.ReadRepo().MGetLast(ctx, currencyIDs)
+}
+
+// GetLastSpeedometers returns the latest speedometers of the currency.
+func (s *Service) GetLastSpeedometers(ctx context.Context, currencyID uint) (*oracul_speedometers.OraculSpeedometers, error) {
+	return s.oraculSpeedometers.GetLast(ctx, currencyID)
+}
+
+// GetLastHolderStats returns the latest holder stats of the currency.
+func (s *Service) GetLastHolderStats(ctx context.Context, currencyID uint) (*oracul_holder_stats.OraculHolderStats, error) {
+	return s.oraculHolderStats.GetLast(ctx, currencyID)
+}
+
+// History returns the analytics of the currency in the window [From, To], from the oldest to the newest.
+func (s *Service) History(ctx context.Context, params *oracul.HistoryParams) (*OraculAnalyticsList, error) {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] analytics history params error: %w", apperror.ErrBadRequest, err)
+	}
+	return s.replicaSet.ReadRepo().GetBetween(ctx, params.CurrencyID, params.From, params.To)
+}
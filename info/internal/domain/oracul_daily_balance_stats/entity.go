@@ -22,3 +22,31 @@ func (e *OraculDailyBalanceStats) Validate() error {
 }
 
 type OraculDailyBalanceStatsList []OraculDailyBalanceStats
+
+func (l *OraculDailyBalanceStatsList) MinTime() *time.Time {
+	if l == nil || len(*l) == 0 {
+		return nil
+	}
+	min := (*l)[0].D
+	var item OraculDailyBalanceStats
+	for _, item = range *l {
+		if item.D.Before(min) {
+			min = item.D
+		}
+	}
+	return &min
+}
+
+func (l *OraculDailyBalanceStatsList) MaxTime() *time.Time {
+	if l == nil || len(*l) == 0 {
+		return nil
+	}
+	max := (*l)[0].D
+	var item OraculDailyBalanceStats
+	for _, item = range *l {
+		if item.D.After(max) {
+			max = item.D
+		}
+	}
+	return &max
+}
@@ -3,6 +3,7 @@ package oracul_daily_balance_stats
 import (
 	"context"
 	"info/internal/domain"
+	"time"
 )
 
 type ReplicaSet interface {
@@ -16,4 +17,7 @@ type WriteRepository interface {
 }
 
 type ReadRepository interface {
+	GetLast(ctx context.Context, currencyID uint) (*OraculDailyBalanceStats, error)
+	GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*OraculDailyBalanceStatsList, error)
+	MGetLast(ctx context.Context, currencyIDs *[]uint) (*OraculDailyBalanceStatsList, error)
 }
@@ -2,6 +2,9 @@ package oracul_daily_balance_stats
 
 import (
 	"context"
+	"fmt"
+	"info/internal/domain/oracul"
+	"info/internal/pkg/apperror"
 )
 
 type Service struct {
@@ -17,3 +20,22 @@ func NewService(replicaSet ReplicaSet) *Service {
 func (s *Service) MCreate(ctx context.Context, entities *OraculDailyBalanceStatsList) error {
 	return s.replicaSet.WriteRepo().MUpsert(ctx, entities)
 }
+
+// GetLast returns the latest daily balance stats of the currency.
+func (s *Service) GetLast(ctx context.Context, currencyID uint) (*OraculDailyBalanceStats, error) {
+	return s.replicaSet.ReadRepo().GetLast(ctx, currencyID)
+}
+
+// MGetLast returns the latest daily balance stats of every currency.
+func (s *Service) MGetLast(ctx context.Context, currencyIDs *[]uint) (*OraculDailyBalanceStatsList, error) {
+	return s.replicaSet.ReadRepo().MGetLast(ctx, currencyIDs)
+}
+
+// History returns the daily balance stats of the currency in the window [From, To], from the oldest to the newest.
+func (s *Service) History(ctx context.Context, params *oracul.HistoryParams) (*OraculDailyBalanceStatsList, error) {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] daily balance stats history params error: %w", apperror.ErrBadRequest, err)
+	}
+	return s.replicaSet.ReadRepo().GetBetween(ctx, params.CurrencyID, params.From, params.To)
+}
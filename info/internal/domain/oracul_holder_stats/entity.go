@@ -20,3 +20,5 @@ type OraculHolderStats struct {
 func (e *OraculHolderStats) Validate() error {
 	return nil
 }
+
+type OraculHolderStatsList []OraculHolderStats
@@ -23,3 +23,5 @@ type OraculSpeedometers struct {
 func (e *OraculSpeedometers) Validate() error {
 	return nil
 }
+
+type OraculSpeedometersList []OraculSpeedometers
@@ -2,6 +2,7 @@ package oracul_speedometers
 
 import (
 	"context"
+	"time"
 )
 
 type ReplicaSet interface {
@@ -14,4 +15,7 @@ type WriteRepository interface {
 }
 
 type ReadRepository interface {
+	GetLast(ctx context.Context, currencyID uint) (*OraculSpeedometers, error)
+	GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*OraculSpeedometersList, error)
+	MGetLast(ctx context.Context, currencyIDs *[]uint) (*OraculSpeedometersList, error)
 }
@@ -0,0 +1,66 @@
+package portfolio
+
+import (
+	"info/internal/domain/currency"
+	"info/internal/domain/portfolio_item"
+	"math"
+	"slices"
+)
+
+// Position is the item of the portfolio with the currency and its current whale-concentration trend; Currency and WhaleTrend are nil if they are unknown.
+type Position struct {
+	portfolio_item.PortfolioItem
+	Currency   *currency.Currency
+	WhaleTrend *currency.WhaleTrend
+}
+
+type PositionList []Position
+
+// Totals are the sums over the positions; PlPercent is the P&L in percents of the invested amount.
+type Totals struct {
+	Holdings  float64
+	Invested  float64
+	PlValue   float64
+	PlPercent float64
+}
+
+type Portfolio struct {
+	SourceID  string
+	Totals    Totals
+	Positions PositionList
+}
+
+type PortfolioList []Portfolio
+
+// NewPortfolio returns the portfolio with the positions sorted by the holdings descending and with the totals.
+func NewPortfolio(sourceID string, positions PositionList) *Portfolio {
+	slices.SortStableFunc(positions, func(a, b Position) int {
+		switch {
+		case a.CryptoHoldings < b.CryptoHoldings:
+			return 1
+		case a.CryptoHoldings > b.CryptoHoldings:
+			return -1
+		default:
+			return 0
+		}
+	})
+	return &Portfolio{
+		SourceID:  sourceID,
+		Totals:    positions.Totals(),
+		Positions: positions,
+	}
+}
+
+func (l PositionList) Totals() Totals {
+	res := Totals{}
+	var item Position
+	for _, item = range l {
+		res.Holdings += item.CryptoHoldings
+		res.Invested += item.TotalBuySpent
+		res.PlValue += item.PlValue
+	}
+	if res.Invested != 0 {
+		res.PlPercent = math.Round(res.PlValue*10000/res.Invested) / 100
+	}
+	return res
+}
@@ -0,0 +1,264 @@
+package portfolio
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain/currency"
+	"info/internal/domain/portfolio_item"
+	"info/internal/pkg/apperror"
+	"math"
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	DefaultHistoryDays = 365
+
+	Benchmark_BTC = "btc"
+	Benchmark_ETH = "eth"
+)
+
+// benchmarkSlugMap is the slug of the currency by the benchmark
+var benchmarkSlugMap = map[string]string{
+	Benchmark_BTC: "bitcoin",
+	Benchmark_ETH: "ethereum",
+}
+
+var BenchmarkList = []interface{}{
+	Benchmark_BTC,
+	Benchmark_ETH,
+}
+
+type HistoryParams struct {
+	From time.Time
+	To   time.Time
+}
+
+// SetDefaults sets the empty To to now and the empty From to DefaultHistoryDays before To.
+func (e *HistoryParams) SetDefaults() {
+	if e.To.IsZero() {
+		e.To = time.Now().UTC()
+	}
+	if e.From.IsZero() {
+		e.From = e.To.AddDate(0, 0, -DefaultHistoryDays)
+	}
+}
+
+func (e *HistoryParams) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.To, validation.Required, validation.Min(e.From)),
+	)
+}
+
+// ValuePoint is the totals of the portfolio snapshot at Ts.
+type ValuePoint struct {
+	Ts time.Time
+	Totals
+}
+
+type ValueSeries []ValuePoint
+
+// PositionPoint is the position in the portfolio snapshot at Ts.
+type PositionPoint struct {
+	Ts        time.Time
+	Amount    float64
+	Price     float64
+	Holdings  float64
+	Invested  float64
+	PlValue   float64
+	PlPercent float64
+}
+
+// PositionHistory is the P&L evolution of the position; Currency is nil if it is unknown.
+type PositionHistory struct {
+	CurrencyID uint
+	Currency   *currency.Currency
+	Points     []PositionPoint
+}
+
+type PositionHistoryList []PositionHistory
+
+// BenchmarkPoint is the value of the portfolio and the value of the same money kept in the benchmark currency at Ts.
+// The percents are the changes from the first point.
+type BenchmarkPoint struct {
+	Ts               time.Time
+	Value            float64
+	ValuePercent     float64
+	BenchmarkValue   float64
+	BenchmarkPercent float64
+	BenchmarkPrice   float64
+}
+
+// BenchmarkComparison compares the value curve of the portfolio with holding the benchmark currency over the same period.
+// The holdings of the first snapshot are converted into the benchmark at its price; every later change of the invested amount
+// is converted too, so both curves have the same deposits and withdrawals.
+type BenchmarkComparison struct {
+	SourceID          string
+	Benchmark         string
+	BenchmarkCurrency currency.Currency
+	BenchmarkAmount   float64 // the amount of the benchmark coins at the last point
+	Points            []BenchmarkPoint
+}
+
+// History returns the value of the portfolio over time by its snapshots.
+func (s *Service) History(ctx context.Context, sourceID string, params *HistoryParams) (ValueSeries, error) {
+	l, err := s.history(ctx, sourceID, params)
+	if err != nil {
+		return nil, err
+	}
+	return valueSeries(l), nil
+}
+
+// PositionsHistory returns the P&L evolution of every position of the portfolio by its snapshots; the positions are sorted by the currency ID.
+func (s *Service) PositionsHistory(ctx context.Context, sourceID string, params *HistoryParams) (PositionHistoryList, error) {
+	l, err := s.history(ctx, sourceID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(PositionHistoryList, 0)
+	indexMap := make(map[uint]int)
+	var item portfolio_item.PortfolioItemHistory
+	var i int
+	var ok bool
+	for _, item = range *l {
+		if i, ok = indexMap[item.CurrencyID]; !ok {
+			i = len(res)
+			indexMap[item.CurrencyID] = i
+			res = append(res, PositionHistory{
+				CurrencyID: item.CurrencyID,
+				Points:     make([]PositionPoint, 0),
+			})
+		}
+		res[i].Points = append(res[i].Points, PositionPoint{
+			Ts:        item.Ts,
+			Amount:    item.Amount,
+			Price:     item.CurrentPrice,
+			Holdings:  item.CryptoHoldings,
+			Invested:  item.TotalBuySpent,
+			PlValue:   item.PlValue,
+			PlPercent: item.PlPercentValue,
+		})
+	}
+
+	IDs := make([]uint, 0, len(res))
+	for i = range res {
+		IDs = append(IDs, res[i].CurrencyID)
+	}
+	currencyList, err := s.currency.MGet(ctx, &IDs)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if currencyList != nil {
+		for j := range *currencyList {
+			if i, ok = indexMap[(*currencyList)[j].ID]; ok {
+				res[i].Currency = &(*currencyList)[j]
+			}
+		}
+	}
+
+	slices.SortFunc(res, func(a, b PositionHistory) int {
+		return cmp.Compare(a.CurrencyID, b.CurrencyID)
+	})
+	return res, nil
+}
+
+// Benchmark returns the comparison of the value curve of the portfolio with holding the benchmark (btc or eth) over the same period.
+// The snapshots before the first known price of the benchmark are skipped.
+func (s *Service) Benchmark(ctx context.Context, sourceID string, benchmark string, params *HistoryParams) (*BenchmarkComparison, error) {
+	if err := validation.Validate(benchmark, validation.Required, validation.In(BenchmarkList...)); err != nil {
+		return nil, fmt.Errorf("[%w] benchmark error: %w", apperror.ErrBadRequest, err)
+	}
+
+	l, err := s.history(ctx, sourceID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	benchmarkCurrency, err := s.currency.GetByKey(ctx, benchmarkSlugMap[benchmark])
+	if err != nil {
+		return nil, err
+	}
+	priceMap, err := s.priceAndCap.MGet(ctx, &[]uint{benchmarkCurrency.ID})
+	if err != nil {
+		return nil, err
+	}
+	priceList := priceMap[benchmarkCurrency.ID]
+
+	res := &BenchmarkComparison{
+		SourceID:          sourceID,
+		Benchmark:         benchmark,
+		BenchmarkCurrency: *benchmarkCurrency,
+		Points:            make([]BenchmarkPoint, 0),
+	}
+	var first *BenchmarkPoint
+	var invested float64
+	var item ValuePoint
+	for _, item = range valueSeries(l) {
+		price := priceList.LastNotAfter(item.Ts)
+		if price == nil || price.Price == 0 {
+			continue
+		}
+		if first == nil {
+			res.BenchmarkAmount = item.Holdings / price.Price
+		} else {
+			res.BenchmarkAmount += (item.Invested - invested) / price.Price
+		}
+		invested = item.Invested
+
+		point := BenchmarkPoint{
+			Ts:             item.Ts,
+			Value:          item.Holdings,
+			BenchmarkValue: res.BenchmarkAmount * price.Price,
+			BenchmarkPrice: price.Price,
+		}
+		if first == nil {
+			first = &point
+		}
+		point.ValuePercent = changePercent(first.Value, point.Value)
+		point.BenchmarkPercent = changePercent(first.BenchmarkValue, point.BenchmarkValue)
+		res.Points = append(res.Points, point)
+	}
+	return res, nil
+}
+
+func (s *Service) history(ctx context.Context, sourceID string, params *HistoryParams) (*portfolio_item.PortfolioItemHistoryList, error) {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] portfolio history params error: %w", apperror.ErrBadRequest, err)
+	}
+	return s.portfolioItem.GetHistory(ctx, sourceID, params.From, params.To)
+}
+
+// valueSeries returns the totals of the snapshots; the items must be sorted by the time of the snapshot.
+func valueSeries(l *portfolio_item.PortfolioItemHistoryList) ValueSeries {
+	res := make(ValueSeries, 0)
+	var from int
+	for i := range *l {
+		if i < len(*l)-1 && (*l)[i+1].Ts.Equal((*l)[i].Ts) {
+			continue
+		}
+		positions := make(PositionList, 0, i+1-from)
+		for j := from; j <= i; j++ {
+			positions = append(positions, Position{PortfolioItem: (*l)[j].PortfolioItem})
+		}
+		res = append(res, ValuePoint{
+			Ts:     (*l)[i].Ts,
+			Totals: positions.Totals(),
+		})
+		from = i + 1
+	}
+	return res
+}
+
+// changePercent returns the change from the from value to the to value in percents; it is 0 if the from value is 0.
+func changePercent(from float64, to float64) float64 {
+	if from == 0 {
+		return 0
+	}
+	return math.Round((to-from)*10000/from) / 100
+}
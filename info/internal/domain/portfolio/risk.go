@@ -0,0 +1,440 @@
+package portfolio
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain/currency"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/trend"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	DefaultRiskDays             = 365
+	DefaultVolatilityWindowDays = 30
+	MaxRiskDays                 = 3650
+
+	// daysInYear is used for the annualization: the crypto is traded every day
+	daysInYear = 365
+
+	platformKey_Unknown = "unknown"
+)
+
+type RiskParams struct {
+	From         time.Time
+	To           time.Time
+	WindowDays   uint    // the window of the rolling volatility
+	RiskFreeRate float64 // annual, in percents
+}
+
+// SetDefaults sets the empty To to now, the empty From to DefaultRiskDays before To and the empty WindowDays to the default one.
+func (e *RiskParams) SetDefaults() {
+	if e.To.IsZero() {
+		e.To = time.Now().UTC()
+	}
+	if e.From.IsZero() {
+		e.From = e.To.AddDate(0, 0, -DefaultRiskDays)
+	}
+	if e.WindowDays == 0 {
+		e.WindowDays = DefaultVolatilityWindowDays
+	}
+}
+
+func (e *RiskParams) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.To, validation.Required, validation.Min(e.From), validation.Max(e.From.AddDate(0, 0, MaxRiskDays))),
+		validation.Field(&e.WindowDays, validation.Required, validation.Min(uint(2)), validation.Max(uint(MaxRiskDays))),
+		validation.Field(&e.RiskFreeRate, validation.Min(0.0)),
+	)
+}
+
+// Allocation is the share of the holdings; Key is the slug of the currency or of the platform.
+type Allocation struct {
+	Key      string
+	Name     string
+	Holdings float64
+	Percent  float64
+}
+
+type AllocationList []Allocation
+
+// Drawdown is the biggest fall of the value from the peak to the trough in percents of the peak.
+type Drawdown struct {
+	Percent     float64
+	PeakAt      time.Time
+	PeakValue   float64
+	TroughAt    time.Time
+	TroughValue float64
+}
+
+// VolatilityPoint is the annualized volatility in percents over the window ending at the day D.
+type VolatilityPoint struct {
+	D          time.Time
+	Volatility float64
+}
+
+// PositionRisk is the risk of the position; the positions without the prices in the window are not in the risk calculation.
+type PositionRisk struct {
+	CurrencyID       uint
+	Symbol           string
+	Platform         string
+	Holdings         float64
+	PlPercent        float64
+	Weight           float64 // percents of the value at the last day
+	Volatility       float64 // annualized, percents
+	RiskContribution float64 // percents of the variance of the portfolio
+	IsPriced         bool
+	IsWhaleFalling   bool // the current whale trend of the currency is the fall
+	WhaleTrend       *currency.WhaleTrend
+}
+
+// Risk is the risk analytics of the portfolio. The value series is the current amounts of the positions by the daily prices
+// from the first day having the prices of all the positions. The returns and the volatilities are annualized, in percents.
+type Risk struct {
+	SourceID             string
+	From                 time.Time
+	To                   time.Time
+	DaysNb               uint
+	Totals               Totals
+	AllocationByCoin     AllocationList
+	AllocationByPlatform AllocationList
+	Drawdown             Drawdown
+	Return               float64
+	Volatility           float64
+	Sharpe               float64
+	Sortino              float64
+	CorrelatedVolatility float64 // by the covariance of the positions with their weights
+	DiversificationRatio float64 // the weighted sum of the volatilities of the positions divided by CorrelatedVolatility
+	RollingVolatility    []VolatilityPoint
+	Positions            []PositionRisk
+	WhaleFallingNb       uint
+}
+
+type RiskList []Risk
+
+// Risk returns the risk analytics of the portfolio by the source ID.
+func (s *Service) Risk(ctx context.Context, sourceID string, params *RiskParams) (*Risk, error) {
+	if err := riskParamsPrepare(params); err != nil {
+		return nil, err
+	}
+	item, err := s.Get(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.risk(ctx, item, params)
+}
+
+// RiskAll returns the risk analytics of all the portfolios.
+func (s *Service) RiskAll(ctx context.Context, params *RiskParams) (RiskList, error) {
+	if err := riskParamsPrepare(params); err != nil {
+		return nil, err
+	}
+	portfolioList, err := s.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// цены всех портфелей - одним запросом
+	items := make([]*Portfolio, 0, len(*portfolioList))
+	for i := range *portfolioList {
+		items = append(items, &(*portfolioList)[i])
+	}
+	priceMap, err := s.riskPrices(ctx, items, params)
+	if err != nil {
+		return nil, err
+	}
+	res := make(RiskList, 0, len(items))
+	for _, item := range items {
+		res = append(res, *calcRisk(item, priceMap, params))
+	}
+	return res, nil
+}
+
+func riskParamsPrepare(params *RiskParams) error {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("[%w] portfolio risk params error: %w", apperror.ErrBadRequest, err)
+	}
+	return nil
+}
+
+func (s *Service) risk(ctx context.Context, item *Portfolio, params *RiskParams) (*Risk, error) {
+	priceMap, err := s.riskPrices(ctx, []*Portfolio{item}, params)
+	if err != nil {
+		return nil, err
+	}
+	return calcRisk(item, priceMap, params), nil
+}
+
+// riskPrices returns the prices of the currencies of the portfolios in the period of params with the last price before it.
+func (s *Service) riskPrices(ctx context.Context, items []*Portfolio, params *RiskParams) (price_and_cap.PriceAndCapMap, error) {
+	IDs := make([]uint, 0)
+	var position Position
+	for _, item := range items {
+		for _, position = range item.Positions {
+			if !slices.Contains(IDs, position.CurrencyID) {
+				IDs = append(IDs, position.CurrencyID)
+			}
+		}
+	}
+	if len(IDs) == 0 {
+		return price_and_cap.PriceAndCapMap{}, nil
+	}
+
+	priceMap, err := s.priceAndCap.MGetBetween(ctx, &IDs, params.From, params.To)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return price_and_cap.PriceAndCapMap{}, nil
+		}
+		return nil, err
+	}
+	return priceMap, nil
+}
+
+// calcRisk returns the risk of the portfolio by the prices of its currencies.
+func calcRisk(item *Portfolio, priceMap price_and_cap.PriceAndCapMap, params *RiskParams) *Risk {
+	res := &Risk{
+		SourceID:             item.SourceID,
+		Totals:               item.Totals,
+		AllocationByCoin:     allocation(item.Positions, coinKey),
+		AllocationByPlatform: allocation(item.Positions, platformKey),
+		RollingVolatility:    make([]VolatilityPoint, 0),
+		Positions:            make([]PositionRisk, 0, len(item.Positions)),
+	}
+
+	days := price_and_cap.DayList(params.From, params.To)
+	closes := make([][]float64, len(item.Positions))
+	start := 0
+	for i, position := range item.Positions {
+		priceList := priceMap[position.CurrencyID]
+		closes[i] = priceList.DailyCloses(days)
+		_, platformName := platformKey(&position)
+		positionRisk := PositionRisk{
+			CurrencyID: position.CurrencyID,
+			Platform:   platformName,
+			Holdings:   position.CryptoHoldings,
+			PlPercent:  position.PlPercentValue,
+			WhaleTrend: position.WhaleTrend,
+		}
+		if position.Currency != nil {
+			positionRisk.Symbol = position.Currency.Symbol
+		}
+		if position.WhaleTrend != nil && position.WhaleTrend.Direction == trend.Direction_Fall {
+			positionRisk.IsWhaleFalling = true
+			res.WhaleFallingNb++
+		}
+		if first := slices.IndexFunc(closes[i], func(v float64) bool { return v > 0 }); first >= 0 && position.Amount > 0 {
+			positionRisk.IsPriced = true
+			if first > start {
+				start = first
+			}
+		}
+		res.Positions = append(res.Positions, positionRisk)
+	}
+
+	priced := make([]int, 0, len(res.Positions))
+	for i := range res.Positions {
+		if res.Positions[i].IsPriced {
+			priced = append(priced, i)
+		}
+	}
+	// для доходностей нужно хотя бы два дня
+	if len(priced) == 0 || start >= len(days)-1 {
+		return res
+	}
+	days = days[start:]
+	res.From = days[0]
+	res.To = days[len(days)-1]
+	res.DaysNb = uint(len(days))
+
+	values := make([]float64, len(days))
+	for d := range days {
+		for _, i := range priced {
+			values[d] += item.Positions[i].Amount * closes[i][start+d]
+		}
+	}
+	res.Drawdown = maxDrawdown(days, values)
+
+	returns := dailyReturns(values)
+	res.Return = roundPercent(mean(returns) * daysInYear * 100)
+	res.Volatility = annualizedVolatility(returns)
+	if res.Volatility > 0 {
+		res.Sharpe = roundPercent((res.Return - params.RiskFreeRate) / res.Volatility)
+	}
+	if downside := downsideDeviation(returns); downside > 0 {
+		res.Sortino = roundPercent((res.Return - params.RiskFreeRate) / downside)
+	}
+	// returns[k] - доходность дня days[k+1]
+	window := int(params.WindowDays)
+	for k := window - 1; k < len(returns); k++ {
+		res.RollingVolatility = append(res.RollingVolatility, VolatilityPoint{
+			D:          days[k+1],
+			Volatility: annualizedVolatility(returns[k-window+1 : k+1]),
+		})
+	}
+
+	// риск с учётом корреляций: дисперсия портфеля по ковариациям позиций с их весами на последний день
+	assetReturns := make([][]float64, len(priced))
+	weights := make([]float64, len(priced))
+	last := len(values) - 1
+	for k, i := range priced {
+		assetReturns[k] = dailyReturns(closes[i][start:])
+		if values[last] > 0 {
+			weights[k] = item.Positions[i].Amount * closes[i][start+last] / values[last]
+		}
+	}
+	marginal := make([]float64, len(priced))
+	var variance float64
+	for k := range priced {
+		for j := range priced {
+			marginal[k] += covariance(assetReturns[k], assetReturns[j]) * weights[j]
+		}
+		variance += weights[k] * marginal[k]
+	}
+	res.CorrelatedVolatility = roundPercent(math.Sqrt(variance*daysInYear) * 100)
+
+	var weightedVolatility float64
+	for k, i := range priced {
+		volatility := math.Sqrt(covariance(assetReturns[k], assetReturns[k])*daysInYear) * 100
+		weightedVolatility += weights[k] * volatility
+		res.Positions[i].Weight = roundPercent(weights[k] * 100)
+		res.Positions[i].Volatility = roundPercent(volatility)
+		if variance > 0 {
+			res.Positions[i].RiskContribution = roundPercent(weights[k] * marginal[k] * 100 / variance)
+		}
+	}
+	if res.CorrelatedVolatility > 0 {
+		res.DiversificationRatio = roundPercent(weightedVolatility / res.CorrelatedVolatility)
+	}
+	return res
+}
+
+// allocation returns the holdings grouped by the key sorted by the holdings descending.
+func allocation(positions PositionList, key func(position *Position) (string, string)) AllocationList {
+	res := make(AllocationList, 0, len(positions))
+	indexMap := make(map[string]int, len(positions))
+	var total float64
+	for i := range positions {
+		k, name := key(&positions[i])
+		j, ok := indexMap[k]
+		if !ok {
+			j = len(res)
+			indexMap[k] = j
+			res = append(res, Allocation{
+				Key:  k,
+				Name: name,
+			})
+		}
+		res[j].Holdings += positions[i].CryptoHoldings
+		total += positions[i].CryptoHoldings
+	}
+	if total > 0 {
+		for i := range res {
+			res[i].Percent = roundPercent(res[i].Holdings * 100 / total)
+		}
+	}
+	slices.SortStableFunc(res, func(a, b Allocation) int {
+		return cmp.Compare(b.Holdings, a.Holdings)
+	})
+	return res
+}
+
+func coinKey(position *Position) (string, string) {
+	if position.Currency == nil {
+		return strconv.FormatUint(uint64(position.CurrencyID), 10), ""
+	}
+	return position.Currency.Slug, position.Currency.Name
+}
+
+// platformKey returns the platform (chain) of the token; the coin without the platform is the chain itself.
+func platformKey(position *Position) (string, string) {
+	switch {
+	case position.Currency == nil:
+		return platformKey_Unknown, platformKey_Unknown
+	case position.Currency.Platform != nil:
+		return position.Currency.Platform.Slug, position.Currency.Platform.Name
+	default:
+		return position.Currency.Slug, position.Currency.Name
+	}
+}
+
+// maxDrawdown returns the biggest fall of the values by the days.
+func maxDrawdown(days []time.Time, values []float64) Drawdown {
+	percent, peak, trough := trend.MaxDrawdown(values)
+	if percent == 0 {
+		return Drawdown{}
+	}
+	return Drawdown{
+		Percent:     roundPercent(percent),
+		PeakAt:      days[peak],
+		PeakValue:   values[peak],
+		TroughAt:    days[trough],
+		TroughValue: values[trough],
+	}
+}
+
+func dailyReturns(values []float64) []float64 {
+	res := make([]float64, 0, len(values))
+	for i := 1; i < len(values); i++ {
+		if values[i-1] == 0 {
+			res = append(res, 0)
+			continue
+		}
+		res = append(res, values[i]/values[i-1]-1)
+	}
+	return res
+}
+
+func mean(l []float64) float64 {
+	if len(l) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range l {
+		sum += v
+	}
+	return sum / float64(len(l))
+}
+
+// covariance returns the sample covariance of the series of the same length.
+func covariance(a []float64, b []float64) float64 {
+	if len(a) < 2 || len(a) != len(b) {
+		return 0
+	}
+	meanA, meanB := mean(a), mean(b)
+	var sum float64
+	for i := range a {
+		sum += (a[i] - meanA) * (b[i] - meanB)
+	}
+	return sum / float64(len(a)-1)
+}
+
+// annualizedVolatility returns the annualized standard deviation of the daily returns in percents.
+func annualizedVolatility(returns []float64) float64 {
+	return roundPercent(math.Sqrt(covariance(returns, returns)*daysInYear) * 100)
+}
+
+// downsideDeviation returns the annualized deviation of the negative daily returns in percents.
+func downsideDeviation(returns []float64) float64 {
+	if len(returns) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range returns {
+		if v < 0 {
+			sum += v * v
+		}
+	}
+	return roundPercent(math.Sqrt(sum/float64(len(returns))*daysInYear) * 100)
+}
+
+func roundPercent(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,109 @@
+package portfolio
+
+import (
+	"context"
+	"errors"
+	"info/internal/domain/currency"
+	"info/internal/domain/portfolio_item"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+)
+
+// Service joins the portfolio items with the currencies and their whale trends and compares the portfolio history with the benchmarks.
+type Service struct {
+	portfolioItem *portfolio_item.Service
+	currency      *currency.Service
+	priceAndCap   *price_and_cap.Service
+}
+
+func NewService(portfolioItem *portfolio_item.Service, currency *currency.Service, priceAndCap *price_and_cap.Service) *Service {
+	return &Service{
+		portfolioItem: portfolioItem,
+		currency:      currency,
+		priceAndCap:   priceAndCap,
+	}
+}
+
+// GetAll returns all the portfolios sorted by the source ID.
+func (s *Service) GetAll(ctx context.Context) (*PortfolioList, error) {
+	items, err := s.portfolioItem.GetAll(ctx)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return &PortfolioList{}, nil
+		}
+		return nil, err
+	}
+
+	positions, err := s.positions(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	// items отсортированы по портфелю, поэтому позиции портфеля идут подряд
+	res := make(PortfolioList, 0)
+	var from int
+	for i := range positions {
+		if i == len(positions)-1 || positions[i+1].PortfolioSourceID != positions[i].PortfolioSourceID {
+			res = append(res, *NewPortfolio(positions[i].PortfolioSourceID, positions[from:i+1]))
+			from = i + 1
+		}
+	}
+	return &res, nil
+}
+
+// Get returns the portfolio by the source ID.
+func (s *Service) Get(ctx context.Context, sourceID string) (*Portfolio, error) {
+	itemMap, err := s.portfolioItem.MGetByPortfolioSourceId(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.positions(ctx, itemMap.List())
+	if err != nil {
+		return nil, err
+	}
+	return NewPortfolio(sourceID, positions), nil
+}
+
+// positions returns the items in the same order joined with the currencies and their current whale trends.
+func (s *Service) positions(ctx context.Context, items *portfolio_item.PortfolioItemList) (PositionList, error) {
+	res := make(PositionList, 0, len(*items))
+	if len(*items) == 0 {
+		return res, nil
+	}
+
+	IDs := make([]uint, 0, len(*items))
+	var item portfolio_item.PortfolioItem
+	for _, item = range *items {
+		IDs = append(IDs, item.CurrencyID)
+	}
+
+	currencyList, err := s.currency.MGet(ctx, &IDs)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+	if currencyList == nil {
+		currencyList = &currency.CurrencyList{}
+	}
+	currencyMap := make(map[uint]*currency.Currency, len(*currencyList))
+	for i := range *currencyList {
+		currencyMap[(*currencyList)[i].ID] = &(*currencyList)[i]
+	}
+
+	trendMap, err := s.currency.CurrentWhaleTrends(ctx, currencyList)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item = range *items {
+		position := Position{
+			PortfolioItem: item,
+			Currency:      currencyMap[item.CurrencyID],
+		}
+		if whaleTrend, ok := trendMap[item.CurrencyID]; ok {
+			position.WhaleTrend = &whaleTrend
+		}
+		res = append(res, position)
+	}
+	return res, nil
+}
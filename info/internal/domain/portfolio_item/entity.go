@@ -24,6 +24,14 @@ func (e *PortfolioItem) Validate() error {
 
 type PortfolioItemList []PortfolioItem
 
+// PortfolioItemHistory is the item as it was imported at Ts.
+type PortfolioItemHistory struct {
+	PortfolioItem
+	Ts time.Time
+}
+
+type PortfolioItemHistoryList []PortfolioItemHistory
+
 func (l *PortfolioItemList) Slice() *[]PortfolioItem {
 	if l == nil || len(*l) == 0 {
 		return nil
@@ -2,6 +2,8 @@ package portfolio_item
 
 import (
 	"context"
+	"info/internal/domain"
+	"time"
 )
 
 type ReplicaSet interface {
@@ -10,9 +12,14 @@ type ReplicaSet interface {
 }
 
 type WriteRepository interface {
+	Begin(ctx context.Context) (domain.Tx, error)
 	MUpsert(ctx context.Context, entities *PortfolioItemList) error
+	MUpsertTx(ctx context.Context, tx domain.Tx, entities *PortfolioItemList) error
+	MCreateHistoryTx(ctx context.Context, tx domain.Tx, ts time.Time, entities *PortfolioItemList) error
 }
 
 type ReadRepository interface {
-	MGetByPortfolioSourceId(ctx context.Context, portfolioSourceId uint) (*PortfolioItemMap, error)
+	MGetByPortfolioSourceId(ctx context.Context, portfolioSourceId string) (*PortfolioItemMap, error)
+	GetAll(ctx context.Context) (*PortfolioItemList, error)
+	GetHistory(ctx context.Context, portfolioSourceId string, from time.Time, to time.Time) (*PortfolioItemHistoryList, error)
 }
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"info/internal/domain"
+	"info/internal/domain/import_run"
 	"info/internal/pkg/apperror"
+	"info/internal/pkg/workerpool"
 	"runtime/debug"
 	"time"
 )
@@ -14,25 +17,41 @@ type CmcApi interface {
 }
 
 type Service struct {
-	replicaSet ReplicaSet
-	cmcApi     CmcApi
+	replicaSet  ReplicaSet
+	importRun   *import_run.Service
+	cmcApi      CmcApi
+	concurrency uint
 }
 
-func NewService(replicaSet ReplicaSet, cmcApi CmcApi) *Service {
+func NewService(replicaSet ReplicaSet, importRun *import_run.Service, cmcApi CmcApi, concurrency uint) *Service {
 	return &Service{
-		replicaSet: replicaSet,
-		cmcApi:     cmcApi,
+		replicaSet:  replicaSet,
+		importRun:   importRun,
+		cmcApi:      cmcApi,
+		concurrency: concurrency,
 	}
 }
 
 const (
-	defaultCapacity = 100
+	defaultCapacity  = 100
+	TableName        = "cmc.portfolio_item"
+	HistoryTableName = "cmc.portfolio_item_history"
 )
 
-func (s *Service) MGetByPortfolioSourceId(ctx context.Context, portfolioSourceId uint) (*PortfolioItemMap, error) {
+func (s *Service) MGetByPortfolioSourceId(ctx context.Context, portfolioSourceId string) (*PortfolioItemMap, error) {
 	return s.replicaSet.ReadRepo().MGetByPortfolioSourceId(ctx, portfolioSourceId)
 }
 
+// GetAll returns the items of all the portfolios.
+func (s *Service) GetAll(ctx context.Context) (*PortfolioItemList, error) {
+	return s.replicaSet.ReadRepo().GetAll(ctx)
+}
+
+// GetHistory returns the snapshots of the portfolio from the window [from, to] sorted by the time of the snapshot.
+func (s *Service) GetHistory(ctx context.Context, portfolioSourceId string, from time.Time, to time.Time) (*PortfolioItemHistoryList, error) {
+	return s.replicaSet.ReadRepo().GetHistory(ctx, portfolioSourceId, from, to)
+}
+
 func (s *Service) mUpsert(ctx context.Context, entities *PortfolioItemList) error {
 	return s.replicaSet.WriteRepo().MUpsert(ctx, entities)
 }
@@ -44,23 +63,36 @@ func (s *Service) Import(ctx context.Context, portfolioSourceIDs *[]string) (err
 		return nil
 	}
 
+	journal := s.importRun.Start(ctx, import_run.Kind_Portfolio)
+	defer func() {
+		err = errors.Join(err, journal.Finish(ctx, err))
+	}()
+
 	defer func() {
 		if r := recover(); r != nil {
 			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
 		}
 	}()
 
-	var portfolioSourceID string
-	for _, portfolioSourceID = range *portfolioSourceIDs {
-		if err = s.importItem(ctx, portfolioSourceID); err != nil {
-			return err
+	importErr := domain.NewImportError(len(*portfolioSourceIDs))
+	workerpool.Run(ctx, s.concurrency, *portfolioSourceIDs, func(ctx context.Context, portfolioSourceID string) {
+		runItem := import_run.NewImportRunItem(import_run.Kind_Portfolio, portfolioSourceID, nil)
+		err := ctx.Err()
+		if err == nil {
+			err = s.importItem(ctx, portfolioSourceID, runItem)
 		}
-	}
+		journal.AddItem(ctx, runItem, err)
+		if err != nil {
+			importErr.AddFailure(portfolioSourceID, err)
+			return
+		}
+		importErr.AddSuccess(portfolioSourceID)
+	})
 
-	return nil
+	return importErr.Err()
 }
 
-func (s *Service) importItem(ctx context.Context, portfolioSourceID string) (err error) {
+func (s *Service) importItem(ctx context.Context, portfolioSourceID string, runItem *import_run.ImportRunItem) (err error) {
 	const metricName = "portfolio_item.Service.importItem"
 
 	if portfolioSourceID == "" {
@@ -73,11 +105,42 @@ func (s *Service) importItem(ctx context.Context, portfolioSourceID string) (err
 		}
 	}()
 
-	time.Sleep(3 * time.Second)
 	l, err := s.cmcApi.GetPortfolioSummary(ctx, portfolioSourceID)
 	if err != nil {
 		return fmt.Errorf("[%w] cmcApi.GetPortfolioSummary error: %w", apperror.ErrInternal, err)
 	}
 
-	return s.replicaSet.WriteRepo().MUpsert(ctx, l)
+	// текущее состояние перезаписывается, поэтому каждый импорт дополнительно сохраняем снимком в историю
+	ts := time.Now().UTC()
+	tx, err := s.replicaSet.WriteRepo().Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+
+		if err == nil {
+			if err = tx.Commit(ctx); err == nil {
+				return
+			}
+			err = fmt.Errorf("[%w] "+metricName+" Commit error: %w", apperror.ErrInternal, err)
+		}
+
+		if err2 := tx.Rollback(ctx); err2 != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Rollback error: %w", apperror.ErrInternal, err2))
+		}
+	}()
+
+	if err = s.replicaSet.WriteRepo().MUpsertTx(ctx, tx, l); err != nil {
+		return err
+	}
+	if err = s.replicaSet.WriteRepo().MCreateHistoryTx(ctx, tx, ts, l); err != nil {
+		return err
+	}
+	runItem.AddRows(TableName, len(*l), nil, nil)
+	runItem.AddRows(HistoryTableName, len(*l), &ts, &ts)
+	return nil
 }
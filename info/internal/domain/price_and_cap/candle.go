@@ -0,0 +1,113 @@
+package price_and_cap
+
+import (
+	"context"
+	"fmt"
+	"info/internal/pkg/apperror"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Interval_Hour = "1h"
+	Interval_Day  = "1d"
+	Interval_Week = "1w"
+
+	DefaultCandlesLimit = 500
+	MaxCandlesLimit     = 5000
+)
+
+var IntervalList = []interface{}{
+	Interval_Hour,
+	Interval_Day,
+	Interval_Week,
+}
+
+// intervalMap is the interval of time_bucket by the interval of the API
+var intervalMap = map[string]string{
+	Interval_Hour: "1 hour",
+	Interval_Day:  "1 day",
+	Interval_Week: "1 week",
+}
+
+var intervalDurationMap = map[string]time.Duration{
+	Interval_Hour: time.Hour,
+	Interval_Day:  time.Hour * 24,
+	Interval_Week: time.Hour * 24 * 7,
+}
+
+// Candle is the aggregate of the prices in the bucket: OHLC price, average cap and summed volume.
+type Candle struct {
+	Ts       time.Time
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Cap      float64
+	Volume   float64
+	PointsNb uint
+}
+
+type CandleList []Candle
+
+type CandleParams struct {
+	CurrencyID uint
+	Interval   string
+	From       time.Time
+	To         time.Time
+	Limit      uint
+	Offset     uint
+}
+
+// SetDefaults sets the empty To to now, the empty From to the Limit of the intervals before To and the empty Limit to the default one.
+func (e *CandleParams) SetDefaults() {
+	if e.Limit == 0 {
+		e.Limit = DefaultCandlesLimit
+	}
+	if e.To.IsZero() {
+		e.To = time.Now().UTC()
+	}
+	if e.From.IsZero() {
+		if d, ok := intervalDurationMap[e.Interval]; ok {
+			e.From = e.To.Add(-d * time.Duration(e.Limit))
+		}
+	}
+}
+
+func (e *CandleParams) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.CurrencyID, validation.Required),
+		validation.Field(&e.Interval, validation.Required, validation.In(IntervalList...)),
+		validation.Field(&e.From, validation.Required),
+		validation.Field(&e.To, validation.Required, validation.Min(e.From)),
+		validation.Field(&e.Limit, validation.Max(uint(MaxCandlesLimit))),
+	)
+}
+
+// BucketInterval returns the interval for time_bucket.
+func (e *CandleParams) BucketInterval() string {
+	return intervalMap[e.Interval]
+}
+
+// Candles returns the page of the candles of the currency and the total number of the candles in the window.
+func (s *Service) Candles(ctx context.Context, params *CandleParams) (*CandleList, uint, error) {
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("[%w] candle params error: %w", apperror.ErrBadRequest, err)
+	}
+
+	totalNb, err := s.replicaSet.ReadRepo().CountCandles(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	if totalNb == 0 || params.Offset >= totalNb {
+		return &CandleList{}, totalNb, nil
+	}
+
+	candles, err := s.replicaSet.ReadRepo().GetCandles(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return candles, totalNb, nil
+}
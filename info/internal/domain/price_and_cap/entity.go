@@ -1,6 +1,10 @@
 package price_and_cap
 
-import "time"
+import (
+	"info/internal/pkg/trend"
+	"slices"
+	"time"
+)
 
 const ()
 
@@ -26,6 +30,35 @@ func (l *PriceAndCapList) Slice() *[]PriceAndCap {
 	return &res
 }
 
+// Between returns the items from the window [from, to]
+func (l *PriceAndCapList) Between(from time.Time, to time.Time) *PriceAndCapList {
+	if l == nil {
+		return nil
+	}
+	res := make(PriceAndCapList, 0, len(*l))
+	var item PriceAndCap
+	for _, item = range *l {
+		if !item.Ts.Before(from) && !item.Ts.After(to) {
+			res = append(res, item)
+		}
+	}
+	return &res
+}
+
+func (l *PriceAndCapList) MinTime() *time.Time {
+	if l == nil || len(*l) == 0 {
+		return nil
+	}
+	min := (*l)[0].Ts
+	var item PriceAndCap
+	for _, item = range *l {
+		if item.Ts.Before(min) {
+			min = item.Ts
+		}
+	}
+	return &min
+}
+
 func (l *PriceAndCapList) MaxTime() *time.Time {
 	if l == nil || len(*l) == 0 {
 		return nil
@@ -40,6 +73,23 @@ func (l *PriceAndCapList) MaxTime() *time.Time {
 	return &max
 }
 
+// LastNotAfter returns the latest item which is not after the t; it returns nil if there is no such item.
+func (l *PriceAndCapList) LastNotAfter(t time.Time) *PriceAndCap {
+	if l == nil {
+		return nil
+	}
+	var res *PriceAndCap
+	for i := range *l {
+		if (*l)[i].Ts.After(t) {
+			continue
+		}
+		if res == nil || (*l)[i].Ts.After(res.Ts) {
+			res = &(*l)[i]
+		}
+	}
+	return res
+}
+
 func (l *PriceAndCapList) AvgInDay(d time.Time) *PriceAndCap {
 	if l == nil || len(*l) == 0 || d.IsZero() {
 		return nil
@@ -81,4 +131,51 @@ func (l *PriceAndCapList) Avg() *PriceAndCap {
 	return &res
 }
 
+// DailyCloses returns the last price not after the end of every day; it is 0 for the days before the first price.
+func (l *PriceAndCapList) DailyCloses(days []time.Time) []float64 {
+	res := make([]float64, len(days))
+	if l == nil || len(*l) == 0 {
+		return res
+	}
+	items := slices.Clone(*l)
+	slices.SortFunc(items, func(a, b PriceAndCap) int {
+		return a.Ts.Compare(b.Ts)
+	})
+	var j int
+	var price float64
+	for i, d := range days {
+		end := d.AddDate(0, 0, 1)
+		for ; j < len(items) && items[j].Ts.Before(end); j++ {
+			price = items[j].Price
+		}
+		res[i] = price
+	}
+	return res
+}
+
+// DayList returns the days from the day of the from till the day of the to inclusive.
+func DayList(from time.Time, to time.Time) []time.Time {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	res := make([]time.Time, 0, int(to.Sub(from).Hours()/24)+1)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		res = append(res, d)
+	}
+	return res
+}
+
 type PriceAndCapMap map[uint]PriceAndCapList
+
+// Series returns the series of the value (price, cap or daily volume) by the time.
+func (l *PriceAndCapList) Series(value func(e *PriceAndCap) float64) trend.Series {
+	if l == nil {
+		return nil
+	}
+	res := make(trend.Series, 0, len(*l))
+	for i := range *l {
+		res = append(res, trend.Point{
+			T: (*l)[i].Ts,
+			V: value(&(*l)[i]),
+		})
+	}
+	return res
+}
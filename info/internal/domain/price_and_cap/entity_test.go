@@ -0,0 +1,65 @@
+package price_and_cap
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func day(n int) time.Time {
+	return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n)
+}
+
+func TestDayList(t *testing.T) {
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		want int
+	}{
+		{name: "same day", from: day(0).Add(5 * time.Hour), to: day(0).Add(20 * time.Hour), want: 1},
+		{name: "from is truncated", from: day(0).Add(23 * time.Hour), to: day(2), want: 3},
+		{name: "to is inclusive", from: day(0), to: day(9).Add(time.Hour), want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DayList(tt.from, tt.to)
+			if len(got) != tt.want {
+				t.Fatalf("len(DayList()) = %d, want %d", len(got), tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(day(i)) {
+					t.Errorf("DayList()[%d] = %v, want %v", i, got[i], day(i))
+				}
+			}
+		})
+	}
+}
+
+func TestPriceAndCapList_DailyCloses(t *testing.T) {
+	days := DayList(day(0), day(3))
+	tests := []struct {
+		name string
+		l    *PriceAndCapList
+		want []float64
+	}{
+		{name: "nil", l: nil, want: []float64{0, 0, 0, 0}},
+		{
+			name: "last price of the day in any order",
+			l:    &PriceAndCapList{{Price: 2, Ts: day(1).Add(20 * time.Hour)}, {Price: 1, Ts: day(1).Add(time.Hour)}, {Price: 3, Ts: day(3)}},
+			want: []float64{0, 2, 2, 3},
+		},
+		{
+			name: "price before the days is carried forward",
+			l:    &PriceAndCapList{{Price: 5, Ts: day(-10)}, {Price: 6, Ts: day(2).Add(time.Hour)}},
+			want: []float64{5, 5, 6, 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.DailyCloses(days); !slices.Equal(got, tt.want) {
+				t.Errorf("DailyCloses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
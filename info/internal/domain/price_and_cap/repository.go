@@ -3,6 +3,7 @@ package price_and_cap
 import (
 	"context"
 	"info/internal/domain"
+	"time"
 )
 
 type ReplicaSet interface {
@@ -16,5 +17,10 @@ type WriteRepository interface {
 }
 
 type ReadRepository interface {
+	GetLast(ctx context.Context, currencyID uint) (*PriceAndCap, error)
 	MGet(ctx context.Context, currencyIDs *[]uint) (PriceAndCapMap, error)
+	MGetBetween(ctx context.Context, currencyIDs *[]uint, from time.Time, to time.Time) (PriceAndCapMap, error)
+	MGetLast(ctx context.Context, currencyIDs *[]uint, limit uint) (PriceAndCapMap, error)
+	GetCandles(ctx context.Context, params *CandleParams) (*CandleList, error)
+	CountCandles(ctx context.Context, params *CandleParams) (uint, error)
 }
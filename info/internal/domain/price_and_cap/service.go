@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"info/internal/domain"
+	"info/internal/domain/import_run"
 	"info/internal/pkg/apperror"
 	"runtime/debug"
 	"time"
@@ -30,6 +31,7 @@ func NewService(replicaSet ReplicaSet, cmcApi CmcApi) *Service {
 
 const (
 	defaultCapacity = 100
+	TableName       = "cmc.price_and_cap"
 
 	TimeRange_1M  = "1M"
 	TimeRange_1Y  = "1Y"
@@ -46,15 +48,39 @@ func TimeRangeValidate(s string) error {
 	return validation.Validate(s, validation.Required, validation.In(TimeRangeList...))
 }
 
+// TimeRangeFor returns the smallest upstream time range which covers the time from the from till now.
+func TimeRangeFor(from time.Time) string {
+	switch {
+	case from.After(time.Now().Add(-time.Hour * 24 * 30)):
+		return TimeRange_1M
+	case from.After(time.Now().Add(-time.Hour * 24 * 365)):
+		return TimeRange_1Y
+	default:
+		return TimeRange_All
+	}
+}
+
 func (s *Service) MGet(ctx context.Context, currencyIDs *[]uint) (PriceAndCapMap, error) {
 	return s.replicaSet.ReadRepo().MGet(ctx, currencyIDs)
 }
 
+// MGetBetween returns the prices of the currencies in [from, to] with the last price of every currency before from,
+// so the price known at from can be carried forward.
+func (s *Service) MGetBetween(ctx context.Context, currencyIDs *[]uint, from time.Time, to time.Time) (PriceAndCapMap, error) {
+	return s.replicaSet.ReadRepo().MGetBetween(ctx, currencyIDs, from, to)
+}
+
+// MGetLast returns the last limit prices of every currency.
+func (s *Service) MGetLast(ctx context.Context, currencyIDs *[]uint, limit uint) (PriceAndCapMap, error) {
+	return s.replicaSet.ReadRepo().MGetLast(ctx, currencyIDs, limit)
+}
+
 func (s *Service) Upsert(ctx context.Context, entity *PriceAndCap) error {
 	return s.replicaSet.WriteRepo().Upsert(ctx, entity)
 }
 
-func (s *Service) ImportTx(ctx context.Context, tx domain.Tx, currencyID uint, importLastTime *time.Time) (maxTime *time.Time, err error) {
+// ImportTx imports the ranges needed after importLastTime and adds the upserted rows to runItem (it may be nil).
+func (s *Service) ImportTx(ctx context.Context, tx domain.Tx, currencyID uint, importLastTime *time.Time, runItem *import_run.ImportRunItem) (maxTime *time.Time, err error) {
 	const metricName = "price_and_cap.Service.ImportTx"
 	defer func() {
 		if r := recover(); r != nil {
@@ -63,7 +89,7 @@ func (s *Service) ImportTx(ctx context.Context, tx domain.Tx, currencyID uint, i
 	}()
 
 	if importLastTime == nil || time.Now().Add(-time.Hour*24*365).After(*importLastTime) {
-		maxT, err := s.importTx(ctx, tx, currencyID, TimeRange_All)
+		maxT, err := s.importTx(ctx, tx, currencyID, runItem, TimeRange_All)
 		if err != nil {
 			return nil, err
 		}
@@ -73,7 +99,7 @@ func (s *Service) ImportTx(ctx context.Context, tx domain.Tx, currencyID uint, i
 	}
 
 	if importLastTime == nil || time.Now().Add(-time.Hour*24*31).After(*importLastTime) {
-		maxT, err := s.importTx(ctx, tx, currencyID, TimeRange_1Y)
+		maxT, err := s.importTx(ctx, tx, currencyID, runItem, TimeRange_1Y)
 		if err != nil {
 			return nil, err
 		}
@@ -82,7 +108,7 @@ func (s *Service) ImportTx(ctx context.Context, tx domain.Tx, currencyID uint, i
 		}
 	}
 
-	maxT, err := s.importTx(ctx, tx, currencyID, TimeRange_1M)
+	maxT, err := s.importTx(ctx, tx, currencyID, runItem, TimeRange_1M)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +119,7 @@ func (s *Service) ImportTx(ctx context.Context, tx domain.Tx, currencyID uint, i
 	return maxTime, nil
 }
 
-func (s *Service) importTx(ctx context.Context, tx domain.Tx, currencyID uint, timeRange string) (maxTime *time.Time, err error) {
+func (s *Service) importTx(ctx context.Context, tx domain.Tx, currencyID uint, runItem *import_run.ImportRunItem, timeRange string) (maxTime *time.Time, err error) {
 	const metricName = "price_and_cap.Service.importTx"
 	defer func() {
 		if r := recover(); r != nil {
@@ -113,5 +139,36 @@ func (s *Service) importTx(ctx context.Context, tx domain.Tx, currencyID uint, t
 	if err = s.replicaSet.WriteRepo().MUpsertTx(ctx, tx, item.Slice()); err != nil {
 		return nil, err
 	}
+	runItem.AddRows(TableName, len(*item), item.MinTime(), item.MaxTime())
 	return item.MaxTime(), nil
 }
+
+// BackfillTx fetches the time range and upserts only the data of the window [from, to].
+func (s *Service) BackfillTx(ctx context.Context, tx domain.Tx, currencyID uint, timeRange string, from time.Time, to time.Time) (maxTime *time.Time, rowsNb int, err error) {
+	const metricName = "price_and_cap.Service.BackfillTx"
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	if err = TimeRangeValidate(timeRange); err != nil {
+		return nil, 0, err
+	}
+
+	item, err := s.cmcApi.GetDetailChart(ctx, currencyID, timeRange)
+	if err != nil {
+		return nil, 0, err
+	}
+	item = item.Between(from, to)
+
+	if err = s.replicaSet.WriteRepo().MUpsertTx(ctx, tx, item.Slice()); err != nil {
+		return nil, 0, err
+	}
+	return item.MaxTime(), len(*item), nil
+}
+
+// GetLast returns the latest price and cap of the currency.
+func (s *Service) GetLast(ctx context.Context, currencyID uint) (*PriceAndCap, error) {
+	return s.replicaSet.ReadRepo().GetLast(ctx, currencyID)
+}
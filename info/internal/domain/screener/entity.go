@@ -0,0 +1,201 @@
+package screener
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"info/internal/pkg/apperror"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	Metric_WhaleShare          = "whale-share"          // the whale share of the holdings at the last day in percents (CMC)
+	Metric_WhaleShareChange    = "whale-share-change"   // the change of the whale share over Days days in percentage points (CMC)
+	Metric_PriceChange         = "price-change"         // the change of the price over Days days in percents (CMC)
+	Metric_Cap                 = "cap"                  // the last market cap (CMC)
+	Metric_WhalesConcentration = "whales-concentration" // Oracul analytics
+	Metric_WormIndex           = "worm-index"           // Oracul analytics
+	Metric_GrowthFuel          = "growth-fuel"          // Oracul analytics
+	Metric_WhalesBuyRate       = "whales-buy-rate"      // Oracul speedometers
+	Metric_WhalesSellRate      = "whales-sell-rate"     // Oracul speedometers
+	Metric_WhalesNetRate       = "whales-net-rate"      // the buy rate minus the sell rate of the whales (Oracul speedometers)
+	Metric_InvestorsNetRate    = "investors-net-rate"   // Oracul speedometers
+	Metric_RetailersNetRate    = "retailers-net-rate"   // Oracul speedometers
+
+	Normalization_ZScore     = "zscore"     // the number of the standard deviations from the mean; the missing value is 0
+	Normalization_Percentile = "percentile" // the percent rank from 0 to 100; the missing value is 50
+
+	DefaultDays   = 7
+	DefaultLimit  = 50
+	MaxDays       = 365
+	MaxLimit      = 1000
+	MaxCriteriaNb = 20
+)
+
+var MetricList = []interface{}{
+	Metric_WhaleShare,
+	Metric_WhaleShareChange,
+	Metric_PriceChange,
+	Metric_Cap,
+	Metric_WhalesConcentration,
+	Metric_WormIndex,
+	Metric_GrowthFuel,
+	Metric_WhalesBuyRate,
+	Metric_WhalesSellRate,
+	Metric_WhalesNetRate,
+	Metric_InvestorsNetRate,
+	Metric_RetailersNetRate,
+}
+
+var NormalizationList = []interface{}{
+	Normalization_ZScore,
+	Normalization_Percentile,
+}
+
+// Criterion is the metric with its weight; the negative weight prefers the low values. Days is used by the change metrics only.
+type Criterion struct {
+	Metric string
+	Weight float64
+	Days   uint
+}
+
+// SetDefaults sets the empty Days of the change metrics to the default one and resets it for the others.
+func (e *Criterion) SetDefaults() {
+	if !e.isChange() {
+		e.Days = 0
+		return
+	}
+	if e.Days == 0 {
+		e.Days = DefaultDays
+	}
+}
+
+func (e *Criterion) Validate() error {
+	return validation.ValidateStruct(e,
+		validation.Field(&e.Metric, validation.Required, validation.In(MetricList...)),
+		validation.Field(&e.Weight, validation.Required),
+		validation.Field(&e.Days, validation.Max(uint(MaxDays))),
+	)
+}
+
+func (e *Criterion) isChange() bool {
+	return e.Metric == Metric_WhaleShareChange || e.Metric == Metric_PriceChange
+}
+
+type CriterionList []Criterion
+
+func (l CriterionList) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+func (l *CriterionList) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("[%w] type assertion to []byte failed for value: %v", apperror.ErrData, src)
+	}
+	return json.Unmarshal(data, l)
+}
+
+// Params of the screener run over the observed currencies; Limit is the number of the top ranks returned.
+type Params struct {
+	Normalization string
+	Criteria      CriterionList
+	Limit         uint
+}
+
+// SetDefaults sets the empty Normalization to zscore, the empty Limit to the default one and the defaults of the criteria.
+func (e *Params) SetDefaults() {
+	if e.Normalization == "" {
+		e.Normalization = Normalization_ZScore
+	}
+	if e.Limit == 0 {
+		e.Limit = DefaultLimit
+	}
+	for i := range e.Criteria {
+		e.Criteria[i].SetDefaults()
+	}
+}
+
+func (e *Params) Validate() error {
+	if err := validation.ValidateStruct(e,
+		validation.Field(&e.Normalization, validation.Required, validation.In(NormalizationList...)),
+		validation.Field(&e.Limit, validation.Max(uint(MaxLimit))),
+	); err != nil {
+		return err
+	}
+	// CriterionList is the driver.Valuer: ozzo-validation would check the length of its JSON
+	if len(e.Criteria) == 0 || len(e.Criteria) > MaxCriteriaNb {
+		return fmt.Errorf("criteria: the length must be between 1 and %d", MaxCriteriaNb)
+	}
+	exists := make(map[Criterion]struct{}, len(e.Criteria))
+	for i := range e.Criteria {
+		if err := e.Criteria[i].Validate(); err != nil {
+			return fmt.Errorf("criterion %d: %w", i, err)
+		}
+		key := Criterion{Metric: e.Criteria[i].Metric, Days: e.Criteria[i].Days}
+		if _, ok := exists[key]; ok {
+			return fmt.Errorf("criterion %d: duplicate metric %q", i, e.Criteria[i].Metric)
+		}
+		exists[key] = struct{}{}
+	}
+	return nil
+}
+
+// Preset is the saved screener params
+type Preset struct {
+	ID            uint
+	Name          string
+	Normalization string
+	Criteria      CriterionList
+	Limit         uint
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type PresetList []Preset
+
+func (e *Preset) Params() *Params {
+	return &Params{
+		Normalization: e.Normalization,
+		Criteria:      e.Criteria,
+		Limit:         e.Limit,
+	}
+}
+
+// Contribution is the part of the score given by the criterion: Weight * Normalized / the sum of the absolute weights.
+// Value is the raw value of the metric; it is nil if the currency has no data for the metric.
+type Contribution struct {
+	Metric       string
+	Days         uint
+	Weight       float64
+	Value        *float64
+	Normalized   float64
+	Contribution float64
+}
+
+type Rank struct {
+	Position      uint
+	CurrencyID    uint
+	Symbol        string
+	Slug          string
+	Name          string
+	Score         float64
+	Contributions []Contribution
+}
+
+type RankList []Rank
+
+// Result is the top Limit of the ranked currencies; CurrenciesNb is the number of all the ranked currencies.
+type Result struct {
+	Normalization string
+	Criteria      CriterionList
+	CurrenciesNb  uint
+	Ranks         RankList
+}
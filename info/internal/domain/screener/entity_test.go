@@ -0,0 +1,60 @@
+package screener
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCriterionListScan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want CriterionList
+	}{
+		{
+			name: "lowercase keys",
+			src:  `[{"metric":"price-change","weight":2,"days":7},{"metric":"cap","weight":-1}]`,
+			want: CriterionList{{Metric: Metric_PriceChange, Weight: 2, Days: 7}, {Metric: Metric_Cap, Weight: -1}},
+		},
+		{
+			name: "field names",
+			src:  []byte(`[{"Metric":"price-change","Weight":2,"Days":7},{"Metric":"cap","Weight":-1,"Days":0}]`),
+			want: CriterionList{{Metric: Metric_PriceChange, Weight: 2, Days: 7}, {Metric: Metric_Cap, Weight: -1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got CriterionList
+			if err := got.Scan(tt.src); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Scan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria CriterionList
+		wantErr  bool
+	}{
+		{name: "valid", criteria: CriterionList{{Metric: Metric_Cap, Weight: 1}, {Metric: Metric_WormIndex, Weight: -1}}},
+		{name: "empty", criteria: CriterionList{}, wantErr: true},
+		{name: "unknown metric", criteria: CriterionList{{Metric: "unknown", Weight: 1}}, wantErr: true},
+		{name: "duplicate metric", criteria: CriterionList{{Metric: Metric_Cap, Weight: 1}, {Metric: Metric_Cap, Weight: 2}}, wantErr: true},
+		{name: "same change metric over other days", criteria: CriterionList{{Metric: Metric_PriceChange, Weight: 1, Days: 7}, {Metric: Metric_PriceChange, Weight: 1, Days: 30}}},
+		{name: "same change metric over default days", criteria: CriterionList{{Metric: Metric_PriceChange, Weight: 1}, {Metric: Metric_PriceChange, Weight: 1, Days: DefaultDays}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &Params{Criteria: tt.criteria}
+			params.SetDefaults()
+			if err := params.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
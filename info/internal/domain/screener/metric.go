@@ -0,0 +1,193 @@
+package screener
+
+import (
+	"context"
+	"errors"
+	"info/internal/domain/concentration"
+	"info/internal/domain/currency"
+	"info/internal/domain/oracul_analytics"
+	"info/internal/domain/oracul_speedometers"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"math"
+	"time"
+)
+
+// windowSlackDays is read before the largest window of the change criteria for the days without the data.
+const windowSlackDays = 7
+
+// data is the stored data of the currencies needed by the criteria
+type data struct {
+	concentrationMap concentration.ConcentrationMap
+	priceAndCapMap   price_and_cap.PriceAndCapMap
+	analyticsMap     map[uint]oracul_analytics.OraculAnalytics
+	speedometersMap  map[uint]oracul_speedometers.OraculSpeedometers
+}
+
+// data reads only the sources used by the criteria; a source without data is empty.
+// The concentration and the prices are read for the largest Days of the criteria plus windowSlackDays before now
+// with the last point before, which is carried forward.
+func (s *Service) data(ctx context.Context, currencyList *currency.CurrencyList, criteria CriterionList, now time.Time) (*data, error) {
+	res := &data{
+		concentrationMap: concentration.ConcentrationMap{},
+		priceAndCapMap:   price_and_cap.PriceAndCapMap{},
+		analyticsMap:     map[uint]oracul_analytics.OraculAnalytics{},
+		speedometersMap:  map[uint]oracul_speedometers.OraculSpeedometers{},
+	}
+	var isConcentration, isPriceAndCap, isAnalytics, isSpeedometers bool
+	var concentrationDays, priceAndCapDays uint
+	var item Criterion
+	for _, item = range criteria {
+		switch item.Metric {
+		case Metric_WhaleShare, Metric_WhaleShareChange:
+			isConcentration = true
+			if item.Days > concentrationDays {
+				concentrationDays = item.Days
+			}
+		case Metric_PriceChange, Metric_Cap:
+			isPriceAndCap = true
+			if item.Days > priceAndCapDays {
+				priceAndCapDays = item.Days
+			}
+		case Metric_WhalesConcentration, Metric_WormIndex, Metric_GrowthFuel:
+			isAnalytics = true
+		default:
+			isSpeedometers = true
+		}
+	}
+
+	IDs := currencyList.IDs()
+	var err error
+	if isConcentration {
+		from := now.AddDate(0, 0, -int(concentrationDays)-windowSlackDays)
+		if res.concentrationMap, err = s.concentration.MGetBetween(ctx, IDs, from, now); err != nil {
+			if !errors.Is(err, apperror.ErrNotFound) {
+				return nil, err
+			}
+			res.concentrationMap = concentration.ConcentrationMap{}
+		}
+	}
+	if isPriceAndCap {
+		from := now.AddDate(0, 0, -int(priceAndCapDays)-windowSlackDays)
+		if res.priceAndCapMap, err = s.priceAndCap.MGetBetween(ctx, IDs, from, now); err != nil {
+			if !errors.Is(err, apperror.ErrNotFound) {
+				return nil, err
+			}
+			res.priceAndCapMap = price_and_cap.PriceAndCapMap{}
+		}
+	}
+	if isAnalytics {
+		l, err := s.oraculAnalytics.MGetLast(ctx, IDs)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		if l != nil {
+			for _, analytics := range *l {
+				res.analyticsMap[analytics.CurrencyID] = analytics
+			}
+		}
+	}
+	if isSpeedometers {
+		l, err := s.oraculSpeedometers.MGetLast(ctx, IDs)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		if l != nil {
+			for _, speedometers := range *l {
+				res.speedometersMap[speedometers.CurrencyID] = speedometers
+			}
+		}
+	}
+	return res, nil
+}
+
+// values returns the values of the criterion metric by the currency ID; the currency without the data is absent.
+func (d *data) values(currencyList *currency.CurrencyList, criterion *Criterion, now time.Time) map[uint]float64 {
+	res := make(map[uint]float64, len(*currencyList))
+	for _, item := range *currencyList {
+		if v, ok := d.value(item.ID, criterion, now); ok && !math.IsNaN(v) && !math.IsInf(v, 0) {
+			res[item.ID] = v
+		}
+	}
+	return res
+}
+
+func (d *data) value(currencyID uint, criterion *Criterion, now time.Time) (float64, bool) {
+	switch criterion.Metric {
+	case Metric_WhaleShare, Metric_WhaleShareChange:
+		l, ok := d.concentrationMap[currencyID]
+		if !ok {
+			return 0, false
+		}
+		current := l.LastNotAfter(now)
+		if current == nil {
+			return 0, false
+		}
+		if criterion.Metric == Metric_WhaleShare {
+			return current.Shares().Whales, true
+		}
+		// начало окна прочитано, только если последний день не старее windowSlackDays
+		if current.D.Before(now.AddDate(0, 0, -windowSlackDays)) {
+			return 0, false
+		}
+		start := l.LastNotAfter(current.D.AddDate(0, 0, -int(criterion.Days)))
+		if start == nil {
+			return 0, false
+		}
+		return current.Shares().Whales - start.Shares().Whales, true
+
+	case Metric_PriceChange, Metric_Cap:
+		l, ok := d.priceAndCapMap[currencyID]
+		if !ok {
+			return 0, false
+		}
+		current := l.LastNotAfter(now)
+		if current == nil {
+			return 0, false
+		}
+		if criterion.Metric == Metric_Cap {
+			return current.Cap, true
+		}
+		if current.Ts.Before(now.AddDate(0, 0, -windowSlackDays)) {
+			return 0, false
+		}
+		start := l.LastNotAfter(current.Ts.AddDate(0, 0, -int(criterion.Days)))
+		if start == nil || start.Price == 0 {
+			return 0, false
+		}
+		return (current.Price - start.Price) * 100 / start.Price, true
+
+	case Metric_WhalesConcentration, Metric_WormIndex, Metric_GrowthFuel:
+		item, ok := d.analyticsMap[currencyID]
+		if !ok {
+			return 0, false
+		}
+		switch criterion.Metric {
+		case Metric_WhalesConcentration:
+			return item.WhalesConcentration, true
+		case Metric_WormIndex:
+			return item.WormIndex, true
+		default:
+			return item.GrowthFuel, true
+		}
+
+	default:
+		item, ok := d.speedometersMap[currencyID]
+		if !ok {
+			return 0, false
+		}
+		switch criterion.Metric {
+		case Metric_WhalesBuyRate:
+			return item.WhalesBuyRate, true
+		case Metric_WhalesSellRate:
+			return item.WhalesSellRate, true
+		case Metric_WhalesNetRate:
+			return item.WhalesBuyRate - item.WhalesSellRate, true
+		case Metric_InvestorsNetRate:
+			return item.InvestorsBuyRate - item.InvestorsSellRate, true
+		case Metric_RetailersNetRate:
+			return item.RetailersBuyRate - item.RetailersSellRate, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,135 @@
+package screener
+
+import (
+	"info/internal/domain/currency"
+	"math"
+	"sort"
+)
+
+// rank returns all the currencies sorted by the score; values are the metric values of the criteria in the same order.
+// The currency without the value of the metric gets the neutral normalized value: 0 for zscore and 50 for percentile.
+func rank(currencyList *currency.CurrencyList, params *Params, values []map[uint]float64) RankList {
+	var weightSum float64
+	for i := range params.Criteria {
+		weightSum += math.Abs(params.Criteria[i].Weight)
+	}
+
+	normalized := make([]map[uint]float64, len(values))
+	for i := range values {
+		if params.Normalization == Normalization_Percentile {
+			normalized[i] = percentile(values[i])
+		} else {
+			normalized[i] = zScore(values[i])
+		}
+	}
+	neutral := 0.0
+	if params.Normalization == Normalization_Percentile {
+		neutral = 50
+	}
+
+	res := make(RankList, 0, len(*currencyList))
+	for _, item := range *currencyList {
+		r := Rank{
+			CurrencyID:    item.ID,
+			Symbol:        item.Symbol,
+			Slug:          item.Slug,
+			Name:          item.Name,
+			Contributions: make([]Contribution, 0, len(params.Criteria)),
+		}
+		for i, criterion := range params.Criteria {
+			c := Contribution{
+				Metric:     criterion.Metric,
+				Days:       criterion.Days,
+				Weight:     criterion.Weight,
+				Normalized: neutral,
+			}
+			if v, ok := values[i][item.ID]; ok {
+				c.Value = &v
+				c.Normalized = normalized[i][item.ID]
+			}
+			c.Contribution = criterion.Weight * c.Normalized / weightSum
+			r.Score += c.Contribution
+			c.Normalized = round(c.Normalized)
+			c.Contribution = round(c.Contribution)
+			r.Contributions = append(r.Contributions, c)
+		}
+		r.Score = round(r.Score)
+		res = append(res, r)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Symbol < res[j].Symbol
+	})
+	for i := range res {
+		res[i].Position = uint(i + 1)
+	}
+	return res
+}
+
+// zScore returns the number of the standard deviations from the mean of every value; all are 0 if the values do not differ.
+func zScore(values map[uint]float64) map[uint]float64 {
+	res := make(map[uint]float64, len(values))
+	if len(values) == 0 {
+		return res
+	}
+	var mean, variance float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance / float64(len(values)))
+	for ID, v := range values {
+		if std == 0 {
+			res[ID] = 0
+			continue
+		}
+		res[ID] = (v - mean) / std
+	}
+	return res
+}
+
+// percentile returns the percent rank of every value from 0 (the lowest) to 100 (the highest); the equal values get the same average rank
+// and the single value gets 50.
+func percentile(values map[uint]float64) map[uint]float64 {
+	res := make(map[uint]float64, len(values))
+	if len(values) == 0 {
+		return res
+	}
+	if len(values) == 1 {
+		for ID := range values {
+			res[ID] = 50
+		}
+		return res
+	}
+	IDs := make([]uint, 0, len(values))
+	for ID := range values {
+		IDs = append(IDs, ID)
+	}
+	sort.Slice(IDs, func(i, j int) bool {
+		return values[IDs[i]] < values[IDs[j]]
+	})
+	n := float64(len(IDs) - 1)
+	for i := 0; i < len(IDs); {
+		j := i
+		for j+1 < len(IDs) && values[IDs[j+1]] == values[IDs[i]] {
+			j++
+		}
+		p := float64(i+j) / 2 * 100 / n
+		for k := i; k <= j; k++ {
+			res[IDs[k]] = p
+		}
+		i = j + 1
+	}
+	return res
+}
+
+// round rounds to 2 decimals; the negative zero is returned as 0.
+func round(v float64) float64 {
+	return math.Round(v*100)/100 + 0
+}
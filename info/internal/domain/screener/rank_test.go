@@ -0,0 +1,167 @@
+package screener
+
+import (
+	"info/internal/domain/currency"
+	"math"
+	"testing"
+)
+
+func roundMap(m map[uint]float64) map[uint]float64 {
+	res := make(map[uint]float64, len(m))
+	for ID, v := range m {
+		res[ID] = round(v)
+	}
+	return res
+}
+
+func equalMaps(a, b map[uint]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for ID, v := range a {
+		if w, ok := b[ID]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[uint]float64
+		want   map[uint]float64
+	}{
+		{name: "empty", values: map[uint]float64{}, want: map[uint]float64{}},
+		{name: "single value", values: map[uint]float64{1: 7}, want: map[uint]float64{1: 50}},
+		{name: "two values", values: map[uint]float64{1: 7, 2: -3}, want: map[uint]float64{1: 100, 2: 0}},
+		{name: "ordered", values: map[uint]float64{1: 10, 2: 20, 3: 30, 4: 40, 5: 50}, want: map[uint]float64{1: 0, 2: 25, 3: 50, 4: 75, 5: 100}},
+		{name: "duplicates get the average rank", values: map[uint]float64{1: 10, 2: 20, 3: 20, 4: 40}, want: map[uint]float64{1: 0, 2: 50, 3: 50, 4: 100}},
+		{name: "all equal", values: map[uint]float64{1: 5, 2: 5, 3: 5}, want: map[uint]float64{1: 50, 2: 50, 3: 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roundMap(percentile(tt.values)); !equalMaps(got, tt.want) {
+				t.Errorf("percentile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[uint]float64
+		want   map[uint]float64
+	}{
+		{name: "empty", values: map[uint]float64{}, want: map[uint]float64{}},
+		{name: "single value", values: map[uint]float64{1: 7}, want: map[uint]float64{1: 0}},
+		{name: "all equal", values: map[uint]float64{1: 5, 2: 5, 3: 5}, want: map[uint]float64{1: 0, 2: 0, 3: 0}},
+		{name: "symmetric", values: map[uint]float64{1: 1, 2: 3}, want: map[uint]float64{1: -1, 2: 1}},
+		{name: "duplicates", values: map[uint]float64{1: 2, 2: 4, 3: 4, 4: 4, 5: 5, 6: 5, 7: 7, 8: 9}, want: map[uint]float64{1: -1.5, 2: -0.5, 3: -0.5, 4: -0.5, 5: 0, 6: 0, 7: 1, 8: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roundMap(zScore(tt.values)); !equalMaps(got, tt.want) {
+				t.Errorf("zScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	currencyList := &currency.CurrencyList{
+		{ID: 1, Symbol: "AAA"},
+		{ID: 2, Symbol: "BBB"},
+		{ID: 3, Symbol: "CCC"},
+	}
+	tests := []struct {
+		name      string
+		params    *Params
+		values    []map[uint]float64
+		wantOrder []string
+		wantScore []float64
+	}{
+		{
+			name: "percentile with the missing value",
+			params: &Params{Normalization: Normalization_Percentile, Criteria: CriterionList{
+				{Metric: Metric_WormIndex, Weight: 1},
+			}},
+			values:    []map[uint]float64{{1: 10, 3: 30}},
+			wantOrder: []string{"CCC", "BBB", "AAA"},
+			wantScore: []float64{100, 50, 0},
+		},
+		{
+			name: "zscore with the missing value",
+			params: &Params{Normalization: Normalization_ZScore, Criteria: CriterionList{
+				{Metric: Metric_WormIndex, Weight: 1},
+			}},
+			values:    []map[uint]float64{{1: 10, 3: 30}},
+			wantOrder: []string{"CCC", "BBB", "AAA"},
+			wantScore: []float64{1, 0, -1},
+		},
+		{
+			name: "negative weight prefers the low values",
+			params: &Params{Normalization: Normalization_Percentile, Criteria: CriterionList{
+				{Metric: Metric_Cap, Weight: -1},
+			}},
+			values:    []map[uint]float64{{1: 10, 2: 20, 3: 30}},
+			wantOrder: []string{"AAA", "BBB", "CCC"},
+			wantScore: []float64{0, -50, -100},
+		},
+		{
+			name: "weighted criteria",
+			params: &Params{Normalization: Normalization_Percentile, Criteria: CriterionList{
+				{Metric: Metric_WormIndex, Weight: 3},
+				{Metric: Metric_Cap, Weight: -1},
+			}},
+			values:    []map[uint]float64{{1: 10, 2: 20, 3: 30}, {1: 10, 2: 20, 3: 30}},
+			wantOrder: []string{"CCC", "BBB", "AAA"},
+			wantScore: []float64{50, 25, 0},
+		},
+		{
+			name: "equal scores go by the symbol",
+			params: &Params{Normalization: Normalization_ZScore, Criteria: CriterionList{
+				{Metric: Metric_WormIndex, Weight: 1},
+			}},
+			values:    []map[uint]float64{{3: 5, 2: 5, 1: 5}},
+			wantOrder: []string{"AAA", "BBB", "CCC"},
+			wantScore: []float64{0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rank(currencyList, tt.params, tt.values)
+			if len(got) != len(tt.wantOrder) {
+				t.Fatalf("len(rank()) = %d, want %d", len(got), len(tt.wantOrder))
+			}
+			for i := range got {
+				if got[i].Position != uint(i+1) || got[i].Symbol != tt.wantOrder[i] || got[i].Score != tt.wantScore[i] {
+					t.Errorf("rank()[%d] = %d %s %v, want %d %s %v", i, got[i].Position, got[i].Symbol, got[i].Score, i+1, tt.wantOrder[i], tt.wantScore[i])
+				}
+				if math.Signbit(got[i].Score) && got[i].Score == 0 {
+					t.Errorf("rank()[%d] score is the negative zero", i)
+				}
+			}
+		})
+	}
+}
+
+func TestRankMissingValue(t *testing.T) {
+	currencyList := &currency.CurrencyList{{ID: 1, Symbol: "AAA"}, {ID: 2, Symbol: "BBB"}}
+	params := &Params{Normalization: Normalization_Percentile, Criteria: CriterionList{{Metric: Metric_WormIndex, Weight: 1}}}
+	got := rank(currencyList, params, []map[uint]float64{{1: 10}})
+	for _, r := range got {
+		c := r.Contributions[0]
+		switch r.CurrencyID {
+		case 1:
+			if c.Value == nil || *c.Value != 10 || c.Normalized != 50 {
+				t.Errorf("contribution of the present value = %+v, want the value 10 normalized to 50", c)
+			}
+		case 2:
+			if c.Value != nil || c.Normalized != 50 {
+				t.Errorf("contribution of the missing value = %+v, want the nil value normalized to 50", c)
+			}
+		}
+	}
+}
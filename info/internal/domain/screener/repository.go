@@ -0,0 +1,22 @@
+package screener
+
+import (
+	"context"
+)
+
+type ReplicaSet interface {
+	WriteRepo() WriteRepository
+	ReadRepo() ReadRepository
+}
+
+type WriteRepository interface {
+	CreatePreset(ctx context.Context, entity *Preset) (ID uint, err error)
+	UpdatePreset(ctx context.Context, entity *Preset) error
+	DeletePreset(ctx context.Context, ID uint) error
+}
+
+type ReadRepository interface {
+	GetPreset(ctx context.Context, ID uint) (*Preset, error)
+	GetPresetByName(ctx context.Context, name string) (*Preset, error)
+	GetPresets(ctx context.Context) (*PresetList, error)
+}
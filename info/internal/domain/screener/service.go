@@ -0,0 +1,175 @@
+package screener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"info/internal/domain/concentration"
+	"info/internal/domain/currency"
+	"info/internal/domain/oracul_analytics"
+	"info/internal/domain/oracul_speedometers"
+	"info/internal/domain/price_and_cap"
+	"info/internal/pkg/apperror"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Service struct {
+	replicaSet         ReplicaSet
+	currency           *currency.Service
+	concentration      *concentration.Service
+	priceAndCap        *price_and_cap.Service
+	oraculAnalytics    *oracul_analytics.Service
+	oraculSpeedometers *oracul_speedometers.Service
+}
+
+func NewService(replicaSet ReplicaSet, currency *currency.Service, concentration *concentration.Service, priceAndCap *price_and_cap.Service, oraculAnalytics *oracul_analytics.Service, oraculSpeedometers *oracul_speedometers.Service) *Service {
+	return &Service{
+		replicaSet:         replicaSet,
+		currency:           currency,
+		concentration:      concentration,
+		priceAndCap:        priceAndCap,
+		oraculAnalytics:    oraculAnalytics,
+		oraculSpeedometers: oraculSpeedometers,
+	}
+}
+
+func (s *Service) GetPresets(ctx context.Context) (*PresetList, error) {
+	return s.replicaSet.ReadRepo().GetPresets(ctx)
+}
+
+func (s *Service) GetPreset(ctx context.Context, ID uint) (*Preset, error) {
+	return s.replicaSet.ReadRepo().GetPreset(ctx, ID)
+}
+
+// GetPresetByKey returns the preset by its ID or by its name.
+func (s *Service) GetPresetByKey(ctx context.Context, key string) (*Preset, error) {
+	if ID, err := strconv.ParseUint(key, 10, 64); err == nil {
+		return s.replicaSet.ReadRepo().GetPreset(ctx, uint(ID))
+	}
+	return s.replicaSet.ReadRepo().GetPresetByName(ctx, key)
+}
+
+func (s *Service) CreatePreset(ctx context.Context, entity *Preset) (*Preset, error) {
+	if err := s.validatePreset(ctx, entity); err != nil {
+		return nil, err
+	}
+	entity.CreatedAt = time.Now().UTC()
+	entity.UpdatedAt = entity.CreatedAt
+
+	ID, err := s.replicaSet.WriteRepo().CreatePreset(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	entity.ID = ID
+	return entity, nil
+}
+
+// UpdatePreset replaces the preset.
+func (s *Service) UpdatePreset(ctx context.Context, entity *Preset) (*Preset, error) {
+	old, err := s.replicaSet.ReadRepo().GetPreset(ctx, entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.validatePreset(ctx, entity); err != nil {
+		return nil, err
+	}
+	entity.CreatedAt = old.CreatedAt
+	entity.UpdatedAt = time.Now().UTC()
+
+	if err = s.replicaSet.WriteRepo().UpdatePreset(ctx, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (s *Service) DeletePreset(ctx context.Context, ID uint) error {
+	return s.replicaSet.WriteRepo().DeletePreset(ctx, ID)
+}
+
+// validatePreset sets the defaults and validates the preset; the name must be unique and must not be a number, as the presets are run by the ID or by the name.
+func (s *Service) validatePreset(ctx context.Context, entity *Preset) error {
+	entity.Name = strings.TrimSpace(entity.Name)
+	if entity.Name == "" {
+		return fmt.Errorf("[%w] screener preset error: name: cannot be blank", apperror.ErrBadRequest)
+	}
+	if _, err := strconv.ParseUint(entity.Name, 10, 64); err == nil {
+		return fmt.Errorf("[%w] screener preset error: name: must not be a number", apperror.ErrBadRequest)
+	}
+	params := entity.Params()
+	params.SetDefaults()
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("[%w] screener preset error: %w", apperror.ErrBadRequest, err)
+	}
+	entity.Normalization, entity.Criteria, entity.Limit = params.Normalization, params.Criteria, params.Limit
+
+	item, err := s.replicaSet.ReadRepo().GetPresetByName(ctx, entity.Name)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if item.ID != entity.ID {
+		return fmt.Errorf("[%w] screener preset error: the preset %q already exists", apperror.ErrAlreadyExists, entity.Name)
+	}
+	return nil
+}
+
+// RunPreset runs the screener with the params of the preset found by its ID or by its name.
+func (s *Service) RunPreset(ctx context.Context, key string) (*Result, error) {
+	preset, err := s.GetPresetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.Run(ctx, preset.Params())
+}
+
+// Run ranks the observed currencies by the weighted sum of the criteria metrics normalized across the currencies.
+func (s *Service) Run(ctx context.Context, params *Params) (res *Result, err error) {
+	const metricName = "screener.Service.Run"
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Join(err, fmt.Errorf("[%w] "+metricName+" Recover from panic: %v; stacktrace from panic: %s", apperror.ErrInternal, r, string(debug.Stack())))
+		}
+	}()
+
+	params.SetDefaults()
+	if err = params.Validate(); err != nil {
+		return nil, fmt.Errorf("[%w] screener params error: %w", apperror.ErrBadRequest, err)
+	}
+
+	res = &Result{
+		Normalization: params.Normalization,
+		Criteria:      params.Criteria,
+		Ranks:         RankList{},
+	}
+	currencyList, err := s.currency.GetAll(ctx)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	d, err := s.data(ctx, currencyList, params.Criteria, now)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]map[uint]float64, len(params.Criteria))
+	for i := range params.Criteria {
+		values[i] = d.values(currencyList, &params.Criteria[i], now)
+	}
+
+	ranks := rank(currencyList, params, values)
+	res.CurrenciesNb = uint(len(ranks))
+	if uint(len(ranks)) > params.Limit {
+		ranks = ranks[:params.Limit]
+	}
+	res.Ranks = ranks
+	return res, nil
+}
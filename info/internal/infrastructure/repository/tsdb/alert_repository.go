@@ -0,0 +1,346 @@
+package tsdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	"info/internal/pkg/apperror"
+
+	"info/internal/domain/alert"
+)
+
+type AlertRepository struct {
+	*Repository
+}
+
+var _ alert.WriteRepository = (*AlertRepository)(nil)
+var _ alert.ReadRepository = (*AlertRepository)(nil)
+
+func NewAlertRepository(repository *Repository) *AlertRepository {
+	return &AlertRepository{
+		Repository: repository,
+	}
+}
+
+const (
+	alert_rule_sql_fields        = "id, name, kind, currency_id, threshold, days, direction, report, top_n, cooldown_sec, notifiers, is_enabled, matched_currency_ids, created_at, updated_at"
+	alert_rule_sql_Get           = "SELECT " + alert_rule_sql_fields + " FROM cmc.alert_rule WHERE id = $1;"
+	alert_rule_sql_GetAll        = "SELECT " + alert_rule_sql_fields + " FROM cmc.alert_rule ORDER BY id;"
+	alert_rule_sql_Create        = "INSERT INTO cmc.alert_rule(name, kind, currency_id, threshold, days, direction, report, top_n, cooldown_sec, notifiers, is_enabled, matched_currency_ids, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id;"
+	alert_rule_sql_Update        = "UPDATE cmc.alert_rule SET name = $2, kind = $3, currency_id = $4, threshold = $5, days = $6, direction = $7, report = $8, top_n = $9, cooldown_sec = $10, notifiers = $11, is_enabled = $12, matched_currency_ids = $13, updated_at = $14 WHERE id = $1;"
+	alert_rule_sql_UpdateMatched = "UPDATE cmc.alert_rule SET matched_currency_ids = $2 WHERE id = $1;"
+	alert_rule_sql_Delete        = "DELETE FROM cmc.alert_rule WHERE id = $1;"
+	alert_sql_MCreate            = "INSERT INTO cmc.alert(rule_id, rule_name, kind, currency_id, symbol, value, message, notified, error, fired_at) VALUES "
+	alert_sql_MCreate_Returning  = " RETURNING id;"
+	alert_sql_UpdateNotified     = "UPDATE cmc.alert SET notified = $2, error = $3 WHERE id = $1;"
+	alert_sql_GetFiredMap        = "SELECT rule_id, currency_id, max(fired_at) FROM cmc.alert WHERE fired_at >= $1 GROUP BY rule_id, currency_id;"
+	alert_sql_Get                = `SELECT id, rule_id, rule_name, kind, currency_id, symbol, value, message, notified, error, fired_at FROM cmc.alert
+		WHERE ($1 = 0 OR rule_id = $1) AND ($2 = 0 OR currency_id = $2) AND ($3::timestamp IS NULL OR fired_at >= $3::timestamp) ORDER BY fired_at DESC, id DESC LIMIT $4;`
+)
+
+func (r *AlertRepository) GetRule(ctx context.Context, ID uint) (*alert.Rule, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "AlertRepository.GetRule"
+
+	entity := &alert.Rule{}
+	var cooldownSec int64
+	start := time.Now().UTC()
+
+	err := r.db.QueryRow(ctx, alert_rule_sql_Get, ID).Scan(&entity.ID, &entity.Name, &entity.Kind, &entity.CurrencyID, &entity.Threshold, &entity.Days, &entity.Direction, &entity.Report, &entity.TopN, &cooldownSec, &entity.Notifiers, &entity.IsEnabled, &entity.Matched, &entity.CreatedAt, &entity.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_rule_sql_Get, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	entity.Cooldown = time.Duration(cooldownSec) * time.Second
+	return entity, nil
+}
+
+func (r *AlertRepository) GetRules(ctx context.Context) (*alert.RuleList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "AlertRepository.GetRules"
+
+	var entity alert.Rule
+	var cooldownSec int64
+	res := make(alert.RuleList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, alert_rule_sql_GetAll)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_rule_sql_GetAll, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity = alert.Rule{}
+		if err = rows.Scan(&entity.ID, &entity.Name, &entity.Kind, &entity.CurrencyID, &entity.Threshold, &entity.Days, &entity.Direction, &entity.Report, &entity.TopN, &cooldownSec, &entity.Notifiers, &entity.IsEnabled, &entity.Matched, &entity.CreatedAt, &entity.UpdatedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_rule_sql_GetAll, err)
+		}
+		entity.Cooldown = time.Duration(cooldownSec) * time.Second
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
+func (r *AlertRepository) CreateRule(ctx context.Context, entity *alert.Rule) (ID uint, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "AlertRepository.CreateRule"
+	start := time.Now().UTC()
+
+	if err = r.db.QueryRow(ctx, alert_rule_sql_Create, entity.Name, entity.Kind, entity.CurrencyID, entity.Threshold, entity.Days, entity.Direction, entity.Report, entity.TopN, int64(entity.Cooldown/time.Second), entity.Notifiers, entity.IsEnabled, entity.Matched, entity.CreatedAt, entity.UpdatedAt).Scan(&ID); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return 0, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_rule_sql_Create, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return ID, nil
+}
+
+func (r *AlertRepository) UpdateRule(ctx context.Context, entity *alert.Rule) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "AlertRepository.UpdateRule"
+	start := time.Now().UTC()
+
+	tag, err := r.db.Exec(ctx, alert_rule_sql_Update, entity.ID, entity.Name, entity.Kind, entity.CurrencyID, entity.Threshold, entity.Days, entity.Direction, entity.Report, entity.TopN, int64(entity.Cooldown/time.Second), entity.Notifiers, entity.IsEnabled, entity.Matched, entity.UpdatedAt)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_rule_sql_Update, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AlertRepository) UpdateRuleMatched(ctx context.Context, ID uint, matched []uint) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "AlertRepository.UpdateRuleMatched"
+	start := time.Now().UTC()
+
+	if _, err := r.db.Exec(ctx, alert_rule_sql_UpdateMatched, ID, matched); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_rule_sql_UpdateMatched, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *AlertRepository) DeleteRule(ctx context.Context, ID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "AlertRepository.DeleteRule"
+	start := time.Now().UTC()
+
+	tag, err := r.db.Exec(ctx, alert_rule_sql_Delete, ID)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_rule_sql_Delete, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+// MCreateAlert creates the alerts and sets their IDs.
+func (r *AlertRepository) MCreateAlert(ctx context.Context, entities *alert.AlertList) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "AlertRepository.MCreateAlert"
+	const fields_nb = 10
+	if entities == nil || len(*entities) == 0 {
+		return nil
+	}
+	b := strings.Builder{}
+	params := make([]interface{}, 0, len(*entities)*fields_nb)
+	b.WriteString(alert_sql_MCreate)
+	for i, entity := range *entities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("($" + strconv.Itoa(i*fields_nb+1) + ", $" + strconv.Itoa(i*fields_nb+2) + ", $" + strconv.Itoa(i*fields_nb+3) + ", $" + strconv.Itoa(i*fields_nb+4) + ", $" + strconv.Itoa(i*fields_nb+5) + ", $" + strconv.Itoa(i*fields_nb+6) + ", $" + strconv.Itoa(i*fields_nb+7) + ", $" + strconv.Itoa(i*fields_nb+8) + ", $" + strconv.Itoa(i*fields_nb+9) + ", $" + strconv.Itoa(i*fields_nb+10) + ")")
+		params = append(params, entity.RuleID, entity.RuleName, entity.Kind, entity.CurrencyID, entity.Symbol, entity.Value, entity.Message, entity.Notified, entity.Error, entity.FiredAt)
+	}
+	b.WriteString(alert_sql_MCreate_Returning)
+	start := time.Now().UTC()
+	defer func() {
+		success := metricsSuccess
+		if err != nil {
+			success = metricsFail
+		}
+		r.metrics.SqlMetrics.Inc(metricName, success)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, success)
+	}()
+
+	rows, err := r.db.Query(ctx, b.String(), params...)
+	if err != nil {
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, b.String(), err)
+	}
+	defer rows.Close()
+
+	// RETURNING возвращает строки в порядке VALUES
+	var ID uint
+	for i := 0; rows.Next(); i++ {
+		if err = rows.Scan(&ID); err != nil {
+			return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, b.String(), err)
+		}
+		if i < len(*entities) {
+			(*entities)[i].ID = ID
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, b.String(), err)
+	}
+	return nil
+}
+
+// UpdateAlertNotified sets the result of the delivery of the alert.
+func (r *AlertRepository) UpdateAlertNotified(ctx context.Context, entity *alert.Alert) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "AlertRepository.UpdateAlertNotified"
+	start := time.Now().UTC()
+
+	if _, err := r.db.Exec(ctx, alert_sql_UpdateNotified, entity.ID, entity.Notified, entity.Error); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_sql_UpdateNotified, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *AlertRepository) GetAlerts(ctx context.Context, filter *alert.AlertFilter) (*alert.AlertList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "AlertRepository.GetAlerts"
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultCapacityForResult
+	}
+	var entity alert.Alert
+	res := make(alert.AlertList, 0, limit)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, alert_sql_Get, filter.RuleID, filter.CurrencyID, filter.Since, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_sql_Get, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity = alert.Alert{}
+		if err = rows.Scan(&entity.ID, &entity.RuleID, &entity.RuleName, &entity.Kind, &entity.CurrencyID, &entity.Symbol, &entity.Value, &entity.Message, &entity.Notified, &entity.Error, &entity.FiredAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_sql_Get, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
+// GetFiredMap returns the time of the last alert by the rule and the currency of the alerts fired since the since.
+func (r *AlertRepository) GetFiredMap(ctx context.Context, since time.Time) (alert.FiredMap, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "AlertRepository.GetFiredMap"
+
+	var key alert.FiredKey
+	var firedAt time.Time
+	res := make(alert.FiredMap, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, alert_sql_GetFiredMap, since)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_sql_GetFiredMap, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&key.RuleID, &key.CurrencyID, &firedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, alert_sql_GetFiredMap, err)
+		}
+		res[key] = firedAt
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return res, nil
+}
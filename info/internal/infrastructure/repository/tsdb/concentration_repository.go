@@ -35,22 +35,39 @@ func NewConcentrationRepository(repository *Repository) *ConcentrationRepository
 const (
 	MUpsertConcentration_Limit = 11000 // 6 пар-ра * 13т = 65т ~= max
 
-	concentration_sql_MGet                       = "SELECT currency_id, whales, investors, retail, d FROM cmc.concentration WHERE currency_id = any($1) ORDER BY d DESC;"
+	concentration_sql_MGet                       = "SELECT currency_id, whales, investors, retail, d FROM cmc.concentration t WHERE currency_id = any($1) AND " + data_quarantine_sql_NotQuarantined_Concentration + " ORDER BY d DESC;"
 	concentration_sql_Upsert                     = "INSERT INTO cmc.concentration(currency_id, whales, investors, retail, d) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (currency_id, d) DO UPDATE SET whales = EXCLUDED.whales, investors = EXCLUDED.investors, retail = EXCLUDED.retail;"
 	concentration_sql_MUpsert                    = "INSERT INTO cmc.concentration(currency_id, whales, investors, retail, d) VALUES "
 	concentration_sql_MUpsert_OnConflictDoUpdate = " ON CONFLICT (currency_id, d) DO UPDATE SET whales = EXCLUDED.whales, investors = EXCLUDED.investors, retail = EXCLUDED.retail;"
+	concentration_sql_GetBetween                 = "SELECT currency_id, whales, investors, retail, d FROM cmc.concentration t WHERE currency_id = $1 AND d >= $2::date AND d <= $3::date AND " + data_quarantine_sql_NotQuarantined_Concentration + " ORDER BY d;"
+	concentration_sql_GetLast                    = "SELECT currency_id, whales, investors, retail, d FROM cmc.concentration t WHERE currency_id = $1 AND " + data_quarantine_sql_NotQuarantined_Concentration + " ORDER BY d DESC LIMIT 1;"
+	// дни окна и последний день до окна - для переноса долей на первые дни окна
+	concentration_sql_MGetBetween = `SELECT currency_id, whales, investors, retail, d FROM cmc.concentration t WHERE currency_id = any($1) AND d >= $2::date AND d <= $3::date AND ` + data_quarantine_sql_NotQuarantined_Concentration + `
+		UNION ALL
+		SELECT p.currency_id, p.whales, p.investors, p.retail, p.d FROM unnest($1::bigint[]) c(id) CROSS JOIN LATERAL (
+			SELECT currency_id, whales, investors, retail, d FROM cmc.concentration t WHERE currency_id = c.id AND d < $2::date AND ` + data_quarantine_sql_NotQuarantined_Concentration + ` ORDER BY d DESC LIMIT 1
+		) p
+		ORDER BY d DESC;`
 )
 
 func (r *ConcentrationRepository) MGet(ctx context.Context, currencyIDs *[]uint) (concentration.ConcentrationMap, error) {
+	return r.mGet(ctx, "ConcentrationRepository.MGet", concentration_sql_MGet, *currencyIDs)
+}
+
+// MGetBetween returns the days of the currencies in [from, to] and the last day of every currency before from.
+func (r *ConcentrationRepository) MGetBetween(ctx context.Context, currencyIDs *[]uint, from time.Time, to time.Time) (concentration.ConcentrationMap, error) {
+	return r.mGet(ctx, "ConcentrationRepository.MGetBetween", concentration_sql_MGetBetween, *currencyIDs, from, to)
+}
+
+func (r *ConcentrationRepository) mGet(ctx context.Context, metricName string, query string, args ...any) (concentration.ConcentrationMap, error) {
 	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	//defer cancel()
-	const metricName = "ConcentrationRepository.MGet"
 
 	var entity concentration.Concentration
-	res := make(concentration.ConcentrationMap, len(*currencyIDs))
+	res := make(concentration.ConcentrationMap)
 
 	start := time.Now().UTC()
-	rows, err := r.db.Query(ctx, concentration_sql_MGet, *currencyIDs)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
 			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
@@ -59,7 +76,7 @@ func (r *ConcentrationRepository) MGet(ctx context.Context, currencyIDs *[]uint)
 		}
 		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
 		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
-		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, concentration_sql_MGet, err)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
 	}
 	defer rows.Close()
 
@@ -67,7 +84,7 @@ func (r *ConcentrationRepository) MGet(ctx context.Context, currencyIDs *[]uint)
 		if err = rows.Scan(&entity.CurrencyID, &entity.Whales, &entity.Investors, &entity.Retail, &entity.D); err != nil {
 			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
 			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
-			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, concentration_sql_MGet, err)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
 		}
 		if _, ok := res[entity.CurrencyID]; !ok {
 			res[entity.CurrencyID] = make(concentration.ConcentrationList, 0, defaultCapacityForResult)
@@ -158,3 +175,62 @@ func (r *ConcentrationRepository) mUpsertTx(ctx context.Context, tx domain.Tx, e
 	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
 	return nil
 }
+
+func (r *ConcentrationRepository) GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*concentration.ConcentrationList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "ConcentrationRepository.GetBetween"
+
+	var entity concentration.Concentration
+	res := make(concentration.ConcentrationList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, concentration_sql_GetBetween, currencyID, from, to)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, concentration_sql_GetBetween, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.CurrencyID, &entity.Whales, &entity.Investors, &entity.Retail, &entity.D); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, concentration_sql_GetBetween, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	return &res, nil
+}
+
+// GetLast returns the concentration of the latest day of the currency.
+func (r *ConcentrationRepository) GetLast(ctx context.Context, currencyID uint) (*concentration.Concentration, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "ConcentrationRepository.GetLast"
+	start := time.Now().UTC()
+
+	entity := &concentration.Concentration{}
+	if err := r.db.QueryRow(ctx, concentration_sql_GetLast, currencyID).Scan(&entity.CurrencyID, &entity.Whales, &entity.Investors, &entity.Retail, &entity.D); err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, concentration_sql_GetLast, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return entity, nil
+}
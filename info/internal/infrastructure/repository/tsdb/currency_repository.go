@@ -36,15 +36,20 @@ const (
 	currency_sql_Get                       = "SELECT id, symbol, slug, name, is_for_observing, circulating_supply, self_reported_circulating_supply, total_supply, max_supply, latest_price, cmc_rank, date_added, platform FROM cmc.currency WHERE id = $1;"
 	currency_sql_GetBySlug                 = "SELECT id, symbol, slug, name, is_for_observing, circulating_supply, self_reported_circulating_supply, total_supply, max_supply, latest_price, cmc_rank, date_added, platform FROM cmc.currency WHERE slug = $1;"
 	currency_sql_GetImportMaxTimeForUpdate = "SELECT currency_id, price_and_cap, concentration FROM cmc.import_max_time WHERE currency_id = ANY($1) FOR UPDATE;"
+	currency_sql_MGetImportMaxTime         = "SELECT currency_id, price_and_cap, concentration FROM cmc.import_max_time WHERE currency_id = ANY($1);"
 	currency_sql_MGet                      = "SELECT id, symbol, slug, name, is_for_observing, circulating_supply, self_reported_circulating_supply, total_supply, max_supply, latest_price, cmc_rank, date_added, platform FROM cmc.currency WHERE id = any($1);"
-	currency_sql_MGetTokenAddress          = "SELECT currency_id, blockchain, address FROM cmc.token_address WHERE currency_id = any($1);"
+	currency_sql_MGetTokenAddress          = "SELECT currency_id, blockchain, address FROM cmc.token_address WHERE currency_id = any($1) ORDER BY currency_id, blockchain, address;"
+	currency_sql_MCreateTokenAddress       = "INSERT INTO cmc.token_address(currency_id, blockchain, address) VALUES "
+	currency_sql_MDeleteTokenAddress       = "DELETE FROM cmc.token_address WHERE currency_id = any($1);"
 	currency_sql_MGetBySlug                = "SELECT id, symbol, slug, name, is_for_observing, circulating_supply, self_reported_circulating_supply, total_supply, max_supply, latest_price, cmc_rank, date_added, platform FROM cmc.currency WHERE slug = any($1);"
 	currency_sql_GetAll                    = "SELECT id, symbol, slug, name, is_for_observing, circulating_supply, self_reported_circulating_supply, total_supply, max_supply, latest_price, cmc_rank, date_added, platform FROM cmc.currency WHERE is_for_observing = TRUE;"
+	currency_sql_GetAllWithNotObserved     = "SELECT id, symbol, slug, name, is_for_observing, circulating_supply, self_reported_circulating_supply, total_supply, max_supply, latest_price, cmc_rank, date_added, platform FROM cmc.currency;"
 	currency_sql_Create                    = "INSERT INTO cmc.currency(id, symbol, slug, name, is_for_observing) VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING RETURNING id;"
 	currency_sql_MCreate                   = "INSERT INTO cmc.currency(id, symbol, slug, name, is_for_observing, circulating_supply, self_reported_circulating_supply, total_supply, max_supply, latest_price, cmc_rank, date_added, platform) VALUES "
-	currency_sql_Create_OnConflictDoUpdate = " ON CONFLICT (id) DO UPDATE SET symbol = EXCLUDED.symbol, slug = EXCLUDED.slug, name = EXCLUDED.name, is_for_observing = EXCLUDED.is_for_observing, circulating_supply = EXCLUDED.circulating_supply, self_reported_circulating_supply = EXCLUDED.self_reported_circulating_supply, total_supply = EXCLUDED.total_supply, max_supply = EXCLUDED.max_supply, latest_price = EXCLUDED.latest_price, cmc_rank = EXCLUDED.cmc_rank, date_added = EXCLUDED.date_added, platform = EXCLUDED.platform;"
+	currency_sql_Create_OnConflictDoUpdate = " ON CONFLICT (id) DO UPDATE SET symbol = EXCLUDED.symbol, slug = EXCLUDED.slug, name = EXCLUDED.name, circulating_supply = EXCLUDED.circulating_supply, self_reported_circulating_supply = EXCLUDED.self_reported_circulating_supply, total_supply = EXCLUDED.total_supply, max_supply = EXCLUDED.max_supply, latest_price = EXCLUDED.latest_price, cmc_rank = EXCLUDED.cmc_rank, date_added = EXCLUDED.date_added, platform = EXCLUDED.platform;"
 	currency_sql_Update                    = "UPDATE cmc.currency SET symbol = $2, slug = $3, name = $4, is_for_observing = $5 WHERE id = $1;"
 	currency_sql_Delete                    = "DELETE FROM cmc.currency WHERE id = $1;"
+	currency_sql_MSetIsForObserving        = "UPDATE cmc.currency SET is_for_observing = $2 WHERE slug = any($1) RETURNING slug;"
 
 	import_max_time_sql_MCreate                    = "INSERT INTO cmc.import_max_time(currency_id, price_and_cap, concentration) VALUES "
 	import_max_time_sql_MCreate_OnConflictDoUpdate = " ON CONFLICT (currency_id) DO UPDATE SET price_and_cap = EXCLUDED.price_and_cap, concentration = EXCLUDED.concentration;"
@@ -134,6 +139,46 @@ func (r *CurrencyRepository) GetImportMaxTimeForUpdateTx(ctx context.Context, tx
 	return res, nil
 }
 
+func (r *CurrencyRepository) MGetImportMaxTime(ctx context.Context, currencyIDs *[]uint) (map[uint]currency.ImportMaxTime, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "CurrencyRepository.MGetImportMaxTime"
+
+	var entity currency.ImportMaxTime
+	res := make(map[uint]currency.ImportMaxTime, len(*currencyIDs))
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, currency_sql_MGetImportMaxTime, *currencyIDs)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, currency_sql_MGetImportMaxTime, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.CurrencyID, &entity.PriceAndCap, &entity.Concentration); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, currency_sql_MGetImportMaxTime, err)
+		}
+		res[entity.CurrencyID] = entity
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return res, nil
+}
+
 func (r *CurrencyRepository) MGet(ctx context.Context, IDs *[]uint) (*currency.CurrencyList, error) {
 	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	//defer cancel()
@@ -254,6 +299,47 @@ func (r *CurrencyRepository) GetAll(ctx context.Context) (*currency.CurrencyList
 	return &res, nil
 }
 
+// GetAllWithNotObserved returns all the currencies including the ones which are not on the watchlist.
+func (r *CurrencyRepository) GetAllWithNotObserved(ctx context.Context) (*currency.CurrencyList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "CurrencyRepository.GetAllWithNotObserved"
+
+	var entity currency.Currency
+	res := make(currency.CurrencyList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, currency_sql_GetAllWithNotObserved)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, currency_sql_GetAllWithNotObserved, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.ID, &entity.Symbol, &entity.Slug, &entity.Name, &entity.IsForObserving, &entity.CirculatingSupply, &entity.SelfReportedCirculatingSupply, &entity.TotalSupply, &entity.MaxSupply, &entity.LatestPrice, &entity.CmcRank, &entity.AddedAt, &entity.Platform); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, currency_sql_GetAllWithNotObserved, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
 func (r *CurrencyRepository) Create(ctx context.Context, entity *currency.Currency) (ID uint, err error) {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -319,7 +405,62 @@ func (r *CurrencyRepository) Delete(ctx context.Context, ID uint) error {
 	return nil
 }
 
+// MSetIsForObserving sets is_for_observing of the currencies and returns the slugs of the updated ones.
+func (r *CurrencyRepository) MSetIsForObserving(ctx context.Context, slugs *[]string, isForObserving bool) (*[]string, error) {
+	return r.mSetIsForObserving(ctx, r.db, slugs, isForObserving)
+}
+
+func (r *CurrencyRepository) MSetIsForObservingTx(ctx context.Context, tx domain.Tx, slugs *[]string, isForObserving bool) (*[]string, error) {
+	return r.mSetIsForObserving(ctx, tx, slugs, isForObserving)
+}
+
+// mSetIsForObserving sets is_for_observing by the db, which is the pool or the transaction.
+func (r *CurrencyRepository) mSetIsForObserving(ctx context.Context, db execer, slugs *[]string, isForObserving bool) (*[]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "CurrencyRepository.MSetIsForObserving"
+
+	var slug string
+	res := make([]string, 0, len(*slugs))
+
+	start := time.Now().UTC()
+	rows, err := db.Query(ctx, currency_sql_MSetIsForObserving, *slugs, isForObserving)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, currency_sql_MSetIsForObserving, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&slug); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, currency_sql_MSetIsForObserving, err)
+		}
+		res = append(res, slug)
+	}
+	if err = rows.Err(); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, currency_sql_MSetIsForObserving, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	return &res, nil
+}
+
 func (r CurrencyRepository) MCreateImportMaxTime(ctx context.Context, entities *[]currency.ImportMaxTime) error {
+	return r.mCreateImportMaxTime(ctx, r.db, entities)
+}
+
+func (r CurrencyRepository) MCreateImportMaxTimeTx(ctx context.Context, tx domain.Tx, entities *[]currency.ImportMaxTime) error {
+	return r.mCreateImportMaxTime(ctx, tx, entities)
+}
+
+// mCreateImportMaxTime creates the import times by the db, which is the pool or the transaction.
+func (r CurrencyRepository) mCreateImportMaxTime(ctx context.Context, db execer, entities *[]currency.ImportMaxTime) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 	const metricName = "PriceAndCapRepository.MCreateImportMaxTime"
@@ -340,7 +481,7 @@ func (r CurrencyRepository) MCreateImportMaxTime(ctx context.Context, entities *
 	b.WriteString(sql_OnConflictDoNothing)
 	start := time.Now().UTC()
 
-	_, err := r.db.Exec(ctx, b.String(), params...)
+	_, err := db.Exec(ctx, b.String(), params...)
 	if err != nil {
 		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
 		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
@@ -393,6 +534,15 @@ func (r CurrencyRepository) MUpsertImportMaxTimeTx(ctx context.Context, tx domai
 }
 
 func (r CurrencyRepository) MUpsert(ctx context.Context, entities *currency.CurrencyList) error {
+	return r.mUpsert(ctx, r.db, entities)
+}
+
+func (r CurrencyRepository) MUpsertTx(ctx context.Context, tx domain.Tx, entities *currency.CurrencyList) error {
+	return r.mUpsert(ctx, tx, entities)
+}
+
+// mUpsert upserts the currencies by the db, which is the pool or the transaction.
+func (r CurrencyRepository) mUpsert(ctx context.Context, db execer, entities *currency.CurrencyList) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 	const metricName = "WarehouseRepository.MUpsert"
@@ -413,7 +563,7 @@ func (r CurrencyRepository) MUpsert(ctx context.Context, entities *currency.Curr
 	b.WriteString(currency_sql_Create_OnConflictDoUpdate)
 	start := time.Now().UTC()
 
-	_, err := r.db.Exec(ctx, b.String(), params...)
+	_, err := db.Exec(ctx, b.String(), params...)
 	if err != nil {
 		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
 		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
@@ -464,6 +614,56 @@ func (r *CurrencyRepository) MGetTokenAddress(ctx context.Context, IDs *[]uint)
 	return &res, nil
 }
 
+func (r *CurrencyRepository) MDeleteTokenAddressTx(ctx context.Context, tx domain.Tx, IDs *[]uint) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "CurrencyRepository.MDeleteTokenAddressTx"
+	if IDs == nil || len(*IDs) == 0 {
+		return nil
+	}
+	start := time.Now().UTC()
+
+	if _, err := tx.Exec(ctx, currency_sql_MDeleteTokenAddress, *IDs); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, currency_sql_MDeleteTokenAddress, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *CurrencyRepository) MCreateTokenAddressTx(ctx context.Context, tx domain.Tx, entities *currency.TokenAddressList) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "CurrencyRepository.MCreateTokenAddressTx"
+	const fields_nb = 3
+	if entities == nil || len(*entities) == 0 {
+		return nil
+	}
+	b := strings.Builder{}
+	params := make([]interface{}, 0, len(*entities)*fields_nb)
+	b.WriteString(currency_sql_MCreateTokenAddress)
+	for i, entity := range *entities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("($" + strconv.Itoa(i*fields_nb+1) + ", $" + strconv.Itoa(i*fields_nb+2) + ", $" + strconv.Itoa(i*fields_nb+3) + ")")
+		params = append(params, entity.CurrencyID, entity.Blockchain, entity.Address)
+	}
+	b.WriteString(sql_OnConflictDoNothing)
+	start := time.Now().UTC()
+
+	if _, err := tx.Exec(ctx, b.String(), params...); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, b.String(), err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
 /*
 ЗАПРОСЫ
 
@@ -499,49 +699,6 @@ inner join whales on d.currency_id = whales.currency_id and d.last_d = whales.d
 inner join min_max on c.id = min_max.currency_id;
 
 
-Изменения концентрации у китов [1неделя, 2недели, 1месяц, 2месяца]
-
-with m2 as (
-select currency_id, max(d) as d
-from cmc.concentration
-where d <= (now() - interval '2 month')::date
-group by currency_id),
-m1 as (
-select currency_id, max(d) as d
-from cmc.concentration
-where d <= (now() - interval '1 month')::date
-group by currency_id),
-w2 as (
-select currency_id, max(d) as d
-from cmc.concentration
-where d <= (now() - interval '2 week')::date
-group by currency_id),
-w1 as (
-select currency_id, max(d) as d
-from cmc.concentration
-where d <= (now() - interval '1 week')::date
-group by currency_id),
-n as (
-select currency_id, max(d) as d
-from cmc.concentration
-where d <= (now() - interval '2 day')::date		-- из-за глюков в данных
-group by currency_id)
-select c.id, c.symbol,
-round(cast(((cn.whales - cw1.whales) * 100)/cw1.whales AS numeric), 4) as week1, round(cast(((cn.whales - cw2.whales) * 100)/cw2.whales AS numeric), 4) as week2,
-round(cast(((cn.whales - cm1.whales) * 100)/cm1.whales AS numeric), 4) as month1, round(cast(((cn.whales - cm2.whales) * 100)/cm2.whales AS numeric), 4) as month2
-from cmc.currency c
-inner join m2 on c.id = m2.currency_id
-inner join m1 on c.id = m1.currency_id
-inner join w2 on c.id = w2.currency_id
-inner join w1 on c.id = w1.currency_id
-inner join n on c.id = n.currency_id
-inner join cmc.concentration cm2 on m2.currency_id = cm2.currency_id and m2.d = cm2.d
-inner join cmc.concentration cm1 on m1.currency_id = cm1.currency_id and m1.d = cm1.d
-inner join cmc.concentration cw2 on w2.currency_id = cw2.currency_id and w2.d = cw2.d
-inner join cmc.concentration cw1 on w1.currency_id = cw1.currency_id and w1.d = cw1.d
-inner join cmc.concentration cn on n.currency_id = cn.currency_id and n.d = cn.d
-order by month2, month1, week2, week1;
-
 Запросы для графиков
 
 Изменения доли китов
@@ -613,20 +770,18 @@ ORDER BY time;
 
 
 Сводная таблица
-SELECT c.symbol, w.whales_prc, ((GREATEST(c.circulating_supply, c.self_reported_circulating_supply) * c.latest_price)/1000000)::integer AS cap, ((coalesce(c.max_supply, c.total_supply) * c.latest_price)/1000000)::integer AS fdv, w.to_ath, w.from_atl, d.bonus, round(cast(coalesce(pit.crypto_holdings, 0) AS numeric), 2) AS crypto_holdings, round(cast(coalesce(pit.pl_percent_value, 0) AS numeric), 2) AS pl_percent_value
+SELECT c.symbol, w.whales_prc, ((GREATEST(c.circulating_supply, c.self_reported_circulating_supply) * c.latest_price)/1000000)::integer AS cap, ((coalesce(c.max_supply, c.total_supply) * c.latest_price)/1000000)::integer AS fdv, w.to_ath, w.from_atl, round(cast(coalesce(pit.crypto_holdings, 0) AS numeric), 2) AS crypto_holdings, round(cast(coalesce(pit.pl_percent_value, 0) AS numeric), 2) AS pl_percent_value
 FROM
 	currency AS c
 	LEFT JOIN whales_prc_and_min_max_price AS w ON c.id = w.id
-	LEFT JOIN cm1 AS d ON c.id = d.id
 	LEFT JOIN portfolio_item AS pit ON pit.portfolio_source_id = '6651f947db928013879d191c' AND c.id = pit.currency_id
 WHERE c.is_for_observing = true
 ORDER BY c.cmc_rank;
 
 Сводная таблица V2
-select c.symbol, w.whales_prc, ((GREATEST(c.circulating_supply, c.self_reported_circulating_supply) * c.latest_price)/1000000)::integer as cap, ((coalesce(c.max_supply, c.total_supply) * c.latest_price)/1000000)::integer as fdv, w.to_ath, w.from_atl, oa.whales_concentration, d.bonus, round(cast(coalesce(pit.crypto_holdings, 0) AS numeric), 2) as crypto_holdings, round(cast(coalesce(pit.pl_percent_value, 0) AS numeric), 2) as pl_percent_value
+select c.symbol, w.whales_prc, ((GREATEST(c.circulating_supply, c.self_reported_circulating_supply) * c.latest_price)/1000000)::integer as cap, ((coalesce(c.max_supply, c.total_supply) * c.latest_price)/1000000)::integer as fdv, w.to_ath, w.from_atl, oa.whales_concentration, round(cast(coalesce(pit.crypto_holdings, 0) AS numeric), 2) as crypto_holdings, round(cast(coalesce(pit.pl_percent_value, 0) AS numeric), 2) as pl_percent_value
 from currency as c
 left join whales_prc_and_min_max_price as w on c.id = w.id
-left join cw2 as d on c.id = d.id
 left join portfolio_item as pit on pit.portfolio_source_id = '6651f947db928013879d191c' and c.id = pit.currency_id
 left join (
 	select oa.currency_id, oa.whales_concentration
@@ -644,7 +799,6 @@ order by c.cmc_rank;
 select c.symbol, w.whales_prc, ((GREATEST(c.circulating_supply, c.self_reported_circulating_supply) * c.latest_price)/1000000)::integer as cap, ((coalesce(c.max_supply, c.total_supply) * c.latest_price)/1000000)::integer as fdv, w.to_ath, w.from_atl, oa.whales_concentration, round(cast(coalesce(pit.buy_avg_price, 0) AS numeric), 2) as buy_avg_price, round(cast(coalesce(pit.current_price, 0) AS numeric), 2) as current_price, round(cast(coalesce(pit.pl_percent_value, 0) AS numeric), 2)*100 as pl_percent_value, round(cast(coalesce(pit.holdings_percent, 0) AS numeric), 2)*100 as holdings_percent, round(cast(coalesce(pit.total_buy_spent, 0) AS numeric), 2) as total_buy_spent, round(cast(coalesce(pit.crypto_holdings, 0) AS numeric), 2) as crypto_holdings
 from currency as c
 left join whales_prc_and_min_max_price as w on c.id = w.id
-left join cw2 as d on c.id = d.id
 left join portfolio_item as pit on pit.portfolio_source_id = '6651f947db928013879d191c' and c.id = pit.currency_id
 left join (
 	select oa.currency_id, oa.whales_concentration
@@ -662,7 +816,6 @@ order by c.cmc_rank;
 select c.symbol, w.whales_prc, ((GREATEST(c.circulating_supply, c.self_reported_circulating_supply) * c.latest_price)/1000000)::integer as cap, ((coalesce(c.max_supply, c.total_supply) * c.latest_price)/1000000)::integer as fdv, w.to_ath, w.from_atl, oa.whales_concentration, round(cast(coalesce(pit.buy_avg_price, 0) AS numeric), 2) as buy_avg_price, round(cast(coalesce(pit.current_price, 0) AS numeric), 2) as current_price, round(cast(coalesce(pit.pl_percent_value, 0) * 100 AS numeric), 2) as pl_percent_value, round(cast(coalesce(pit.holdings_percent, 0) * 100 AS numeric), 2) as holdings_percent, round(cast(coalesce(pit.total_buy_spent, 0) AS numeric), 2) as total_buy_spent, round(cast(coalesce(pit.crypto_holdings, 0) AS numeric), 2) as crypto_holdings
 from currency as c
 left join whales_prc_and_min_max_price as w on c.id = w.id
-left join cw2 as d on c.id = d.id
 left join portfolio_item as pit on pit.portfolio_source_id = '6651f947db928013879d191c' and c.id = pit.currency_id
 left join (
 	select oa.currency_id, oa.whales_concentration
@@ -0,0 +1,459 @@
+package tsdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	"info/internal/pkg/apperror"
+
+	"info/internal/domain"
+	"info/internal/domain/data_audit"
+)
+
+type DataAuditRepository struct {
+	*Repository
+}
+
+var _ data_audit.WriteRepository = (*DataAuditRepository)(nil)
+var _ data_audit.ReadRepository = (*DataAuditRepository)(nil)
+
+func NewDataAuditRepository(repository *Repository) *DataAuditRepository {
+	return &DataAuditRepository{
+		Repository: repository,
+	}
+}
+
+const (
+	MCreateDataAuditIssue_Limit = 5000  // 11 пар-ов * 5т = 55т < 65т
+	MDataQuarantine_Limit       = 10000 // 5 пар-ов * 10т = 50т < 65т
+
+	data_audit_run_sql_fields      = "id, series, time_from, issues_nb, quarantined_nb, started_at, finished_at"
+	data_audit_run_sql_Create      = "INSERT INTO cmc.data_audit_run(series, time_from, issues_nb, quarantined_nb, started_at, finished_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;"
+	data_audit_run_sql_GetRuns     = "SELECT " + data_audit_run_sql_fields + " FROM cmc.data_audit_run ORDER BY id DESC LIMIT $1;"
+	data_audit_run_sql_GetLastRun  = "SELECT " + data_audit_run_sql_fields + " FROM cmc.data_audit_run ORDER BY id DESC LIMIT 1;"
+	data_audit_issue_sql_MCreate   = "INSERT INTO cmc.data_audit_issue(run_id, series, currency_id, symbol, kind, field, ts, ts_to, value, message) VALUES "
+	data_audit_issue_sql_fields    = "i.id, i.run_id, i.series, i.currency_id, i.symbol, i.kind, i.field, i.ts, i.ts_to, i.value, i.message, EXISTS (SELECT 1 FROM cmc.data_quarantine q WHERE q.series = i.series AND q.currency_id = i.currency_id AND q.ts = i.ts AND i.kind = any($1))"
+	data_audit_issue_sql_Get       = "SELECT " + data_audit_issue_sql_fields + " FROM cmc.data_audit_issue i WHERE i.id = $2;"
+	data_audit_issue_sql_GetIssues = "SELECT " + data_audit_issue_sql_fields + ` FROM cmc.data_audit_issue i
+		WHERE i.run_id = $2 AND ($3 = '' OR i.series = $3) AND ($4 = '' OR i.kind = $4) AND ($5 = 0 OR i.currency_id = $5) ORDER BY i.series, i.currency_id, i.ts, i.id LIMIT $6;`
+	data_quarantine_sql_MCreate           = "INSERT INTO cmc.data_quarantine(series, currency_id, ts, reason, created_at) VALUES "
+	data_quarantine_sql_MCreate_OnConfict = " ON CONFLICT (series, currency_id, ts) DO NOTHING;"
+	data_quarantine_sql_Delete            = "DELETE FROM cmc.data_quarantine WHERE series = $1 AND currency_id = $2 AND ts = $3;"
+	data_quarantine_sql_GetAll            = "SELECT series, currency_id, ts, reason, created_at FROM cmc.data_quarantine ORDER BY created_at DESC, series, currency_id, ts;"
+
+	// the conditions of the reads of the series aliased as t excluding the quarantined points
+	data_quarantine_sql_NotQuarantined_PriceAndCap             = "NOT EXISTS (SELECT 1 FROM cmc.data_quarantine q WHERE q.series = 'cmc.price_and_cap' AND q.currency_id = t.currency_id AND q.ts = t.ts)"
+	data_quarantine_sql_NotQuarantined_Concentration           = "NOT EXISTS (SELECT 1 FROM cmc.data_quarantine q WHERE q.series = 'cmc.concentration' AND q.currency_id = t.currency_id AND q.ts = t.d)"
+	data_quarantine_sql_NotQuarantined_OraculAnalytics         = "NOT EXISTS (SELECT 1 FROM cmc.data_quarantine q WHERE q.series = 'oracul.analytics' AND q.currency_id = t.currency_id AND q.ts = t.ts)"
+	data_quarantine_sql_NotQuarantined_OraculSpeedometers      = "NOT EXISTS (SELECT 1 FROM cmc.data_quarantine q WHERE q.series = 'oracul.speedometers' AND q.currency_id = t.currency_id AND q.ts = t.ts)"
+	data_quarantine_sql_NotQuarantined_OraculHolderStats       = "NOT EXISTS (SELECT 1 FROM cmc.data_quarantine q WHERE q.series = 'oracul.holder_stats' AND q.currency_id = t.currency_id AND q.ts = t.ts)"
+	data_quarantine_sql_NotQuarantined_OraculDailyBalanceStats = "NOT EXISTS (SELECT 1 FROM cmc.data_quarantine q WHERE q.series = 'oracul.daily_balance_stats' AND q.currency_id = t.currency_id AND q.ts = t.d)"
+)
+
+// data_audit_sql_GetSeries is the reads of the series for the audit; the columns are in the order of the fields of the series.
+var data_audit_sql_GetSeries = map[string]string{
+	data_audit.Series_PriceAndCap: "SELECT t.currency_id, t.price, t.daily_volume, t.cap, t.ts FROM cmc.price_and_cap t WHERE t.currency_id = any($1) AND t.ts >= $2 AND " +
+		data_quarantine_sql_NotQuarantined_PriceAndCap + " ORDER BY t.currency_id, t.ts;",
+	data_audit.Series_Concentration: "SELECT t.currency_id, t.whales, t.investors, t.retail, t.d::timestamp FROM cmc.concentration t WHERE t.currency_id = any($1) AND t.d >= $2::date AND " +
+		data_quarantine_sql_NotQuarantined_Concentration + " ORDER BY t.currency_id, t.d;",
+	data_audit.Series_OraculAnalytics: "SELECT t.currency_id, t.whales_concentration, t.worm_index, t.growth_fuel, t.ts FROM oracul.analytics t WHERE t.currency_id = any($1) AND t.ts >= $2 AND " +
+		data_quarantine_sql_NotQuarantined_OraculAnalytics + " ORDER BY t.currency_id, t.ts;",
+	data_audit.Series_OraculSpeedometers: "SELECT t.currency_id, t.whales_buy_rate, t.whales_sell_rate, t.whales_volume, t.investors_buy_rate, t.investors_sell_rate, t.investors_volume, t.retailers_buy_rate, t.retailers_sell_rate, t.retailers_volume, t.ts FROM oracul.speedometers t WHERE t.currency_id = any($1) AND t.ts >= $2 AND " +
+		data_quarantine_sql_NotQuarantined_OraculSpeedometers + " ORDER BY t.currency_id, t.ts;",
+	data_audit.Series_OraculHolderStats: "SELECT t.currency_id, t.whales_volume, t.whales_total_holders::double precision, t.investors_volume, t.investors_total_holders::double precision, t.retailers_volume, t.retailers_total_holders::double precision, t.ts FROM oracul.holder_stats t WHERE t.currency_id = any($1) AND t.ts >= $2 AND " +
+		data_quarantine_sql_NotQuarantined_OraculHolderStats + " ORDER BY t.currency_id, t.ts;",
+	data_audit.Series_OraculDailyBalanceStats: "SELECT t.currency_id, t.whales_balance, t.whales_total_holders::double precision, t.investors_balance, t.investors_total_holders::double precision, t.retailers_balance, t.retailers_total_holders::double precision, t.d::timestamp FROM oracul.daily_balance_stats t WHERE t.currency_id = any($1) AND t.d >= $2::date AND " +
+		data_quarantine_sql_NotQuarantined_OraculDailyBalanceStats + " ORDER BY t.currency_id, t.d;",
+}
+
+// quarantinableKinds is the kinds of the issues whose points may be quarantined
+var quarantinableKinds = []string{data_audit.Kind_Duplicate, data_audit.Kind_NonPositive, data_audit.Kind_Jump}
+
+func (r *DataAuditRepository) CreateRunTx(ctx context.Context, tx domain.Tx, entity *data_audit.Run) (ID uint, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "DataAuditRepository.CreateRunTx"
+	start := time.Now().UTC()
+
+	if err = tx.QueryRow(ctx, data_audit_run_sql_Create, entity.Series, entity.From, entity.IssuesNb, entity.QuarantinedNb, entity.StartedAt, entity.FinishedAt).Scan(&ID); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return 0, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_audit_run_sql_Create, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return ID, nil
+}
+
+func (r *DataAuditRepository) GetRuns(ctx context.Context, limit uint) (*data_audit.RunList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "DataAuditRepository.GetRuns"
+
+	if limit == 0 {
+		limit = defaultCapacityForResult
+	}
+	var entity data_audit.Run
+	res := make(data_audit.RunList, 0, limit)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, data_audit_run_sql_GetRuns, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_audit_run_sql_GetRuns, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity = data_audit.Run{}
+		if err = rows.Scan(&entity.ID, &entity.Series, &entity.From, &entity.IssuesNb, &entity.QuarantinedNb, &entity.StartedAt, &entity.FinishedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_audit_run_sql_GetRuns, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
+func (r *DataAuditRepository) GetLastRun(ctx context.Context) (*data_audit.Run, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "DataAuditRepository.GetLastRun"
+
+	entity := &data_audit.Run{}
+	start := time.Now().UTC()
+
+	err := r.db.QueryRow(ctx, data_audit_run_sql_GetLastRun).Scan(&entity.ID, &entity.Series, &entity.From, &entity.IssuesNb, &entity.QuarantinedNb, &entity.StartedAt, &entity.FinishedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_audit_run_sql_GetLastRun, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return entity, nil
+}
+
+func (r *DataAuditRepository) MCreateIssueTx(ctx context.Context, tx domain.Tx, entities *data_audit.IssueList) error {
+	if len(*entities) <= MCreateDataAuditIssue_Limit {
+		return r.mCreateIssueTx(ctx, tx, entities)
+	}
+
+	lbound := 0
+	hbound := MCreateDataAuditIssue_Limit
+	for lbound < hbound {
+		entitiesItem := (*entities)[lbound:hbound]
+		if err := r.mCreateIssueTx(ctx, tx, &entitiesItem); err != nil {
+			return err
+		}
+		lbound = hbound
+		hbound += MCreateDataAuditIssue_Limit
+		if hbound > len(*entities) {
+			hbound = len(*entities)
+		}
+	}
+	return nil
+}
+
+func (r *DataAuditRepository) mCreateIssueTx(ctx context.Context, tx domain.Tx, entities *data_audit.IssueList) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "DataAuditRepository.mCreateIssueTx"
+	const fields_nb = 10
+	if len(*entities) == 0 {
+		return nil
+	}
+	b := strings.Builder{}
+	params := make([]interface{}, 0, len(*entities)*fields_nb)
+	b.WriteString(data_audit_issue_sql_MCreate)
+	for i, entity := range *entities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("($" + strconv.Itoa(i*fields_nb+1) + ", $" + strconv.Itoa(i*fields_nb+2) + ", $" + strconv.Itoa(i*fields_nb+3) + ", $" + strconv.Itoa(i*fields_nb+4) + ", $" + strconv.Itoa(i*fields_nb+5) + ", $" + strconv.Itoa(i*fields_nb+6) + ", $" + strconv.Itoa(i*fields_nb+7) + ", $" + strconv.Itoa(i*fields_nb+8) + ", $" + strconv.Itoa(i*fields_nb+9) + ", $" + strconv.Itoa(i*fields_nb+10) + ")")
+		params = append(params, entity.RunID, entity.Series, entity.CurrencyID, entity.Symbol, entity.Kind, entity.Field, entity.Ts, entity.TsTo, entity.Value, entity.Message)
+	}
+	start := time.Now().UTC()
+
+	if _, err := tx.Exec(ctx, b.String(), params...); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_audit_issue_sql_MCreate, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *DataAuditRepository) GetIssue(ctx context.Context, ID uint) (*data_audit.Issue, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "DataAuditRepository.GetIssue"
+
+	entity := &data_audit.Issue{}
+	start := time.Now().UTC()
+
+	err := r.db.QueryRow(ctx, data_audit_issue_sql_Get, quarantinableKinds, ID).Scan(&entity.ID, &entity.RunID, &entity.Series, &entity.CurrencyID, &entity.Symbol, &entity.Kind, &entity.Field, &entity.Ts, &entity.TsTo, &entity.Value, &entity.Message, &entity.IsQuarantined)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_audit_issue_sql_Get, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return entity, nil
+}
+
+func (r *DataAuditRepository) GetIssues(ctx context.Context, filter *data_audit.IssueFilter) (*data_audit.IssueList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "DataAuditRepository.GetIssues"
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultCapacityForResult
+	}
+	var entity data_audit.Issue
+	res := make(data_audit.IssueList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, data_audit_issue_sql_GetIssues, quarantinableKinds, filter.RunID, filter.Series, filter.Kind, filter.CurrencyID, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_audit_issue_sql_GetIssues, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity = data_audit.Issue{}
+		if err = rows.Scan(&entity.ID, &entity.RunID, &entity.Series, &entity.CurrencyID, &entity.Symbol, &entity.Kind, &entity.Field, &entity.Ts, &entity.TsTo, &entity.Value, &entity.Message, &entity.IsQuarantined); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_audit_issue_sql_GetIssues, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
+func (r *DataAuditRepository) MQuarantine(ctx context.Context, entities *data_audit.QuarantineList) error {
+	return r.mQuarantine(ctx, r.db, entities)
+}
+
+func (r *DataAuditRepository) MQuarantineTx(ctx context.Context, tx domain.Tx, entities *data_audit.QuarantineList) error {
+	return r.mQuarantine(ctx, tx, entities)
+}
+
+// mQuarantine quarantines the points by the db, which is the pool or the transaction.
+func (r *DataAuditRepository) mQuarantine(ctx context.Context, db execer, entities *data_audit.QuarantineList) error {
+	if len(*entities) <= MDataQuarantine_Limit {
+		return r.mQuarantinePart(ctx, db, entities)
+	}
+
+	lbound := 0
+	hbound := MDataQuarantine_Limit
+	for lbound < hbound {
+		entitiesItem := (*entities)[lbound:hbound]
+		if err := r.mQuarantinePart(ctx, db, &entitiesItem); err != nil {
+			return err
+		}
+		lbound = hbound
+		hbound += MDataQuarantine_Limit
+		if hbound > len(*entities) {
+			hbound = len(*entities)
+		}
+	}
+	return nil
+}
+
+func (r *DataAuditRepository) mQuarantinePart(ctx context.Context, db execer, entities *data_audit.QuarantineList) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "DataAuditRepository.mQuarantine"
+	const fields_nb = 5
+	if len(*entities) == 0 {
+		return nil
+	}
+	b := strings.Builder{}
+	params := make([]interface{}, 0, len(*entities)*fields_nb)
+	b.WriteString(data_quarantine_sql_MCreate)
+	for i, entity := range *entities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("($" + strconv.Itoa(i*fields_nb+1) + ", $" + strconv.Itoa(i*fields_nb+2) + ", $" + strconv.Itoa(i*fields_nb+3) + ", $" + strconv.Itoa(i*fields_nb+4) + ", $" + strconv.Itoa(i*fields_nb+5) + ")")
+		params = append(params, entity.Series, entity.CurrencyID, entity.Ts, entity.Reason, entity.CreatedAt)
+	}
+	b.WriteString(data_quarantine_sql_MCreate_OnConfict)
+	start := time.Now().UTC()
+
+	if _, err := db.Exec(ctx, b.String(), params...); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_quarantine_sql_MCreate, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *DataAuditRepository) Release(ctx context.Context, series string, currencyID uint, ts time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "DataAuditRepository.Release"
+	start := time.Now().UTC()
+
+	tag, err := r.db.Exec(ctx, data_quarantine_sql_Delete, series, currencyID, ts)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_quarantine_sql_Delete, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *DataAuditRepository) GetQuarantine(ctx context.Context) (*data_audit.QuarantineList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "DataAuditRepository.GetQuarantine"
+
+	var entity data_audit.Quarantine
+	res := make(data_audit.QuarantineList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, data_quarantine_sql_GetAll)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_quarantine_sql_GetAll, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.Series, &entity.CurrencyID, &entity.Ts, &entity.Reason, &entity.CreatedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, data_quarantine_sql_GetAll, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
+func (r *DataAuditRepository) GetSeries(ctx context.Context, series string, currencyIDs *[]uint, from time.Time) (data_audit.PointMap, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "DataAuditRepository.GetSeries"
+
+	query, ok := data_audit_sql_GetSeries[series]
+	if !ok {
+		return nil, fmt.Errorf("[%w] %s unknown series: %q", apperror.ErrInternal, metricName, series)
+	}
+	res := make(data_audit.PointMap, len(*currencyIDs))
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, query, *currencyIDs, from)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
+	}
+	defer rows.Close()
+
+	fieldsNb := len(rows.FieldDescriptions()) - 2
+	for rows.Next() {
+		entity := data_audit.Point{
+			Values: make([]float64, fieldsNb),
+		}
+		dest := make([]interface{}, 0, fieldsNb+2)
+		dest = append(dest, &entity.CurrencyID)
+		for i := range entity.Values {
+			dest = append(dest, &entity.Values[i])
+		}
+		dest = append(dest, &entity.Ts)
+		if err = rows.Scan(dest...); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
+		}
+		if _, ok := res[entity.CurrencyID]; !ok {
+			res[entity.CurrencyID] = make(data_audit.PointList, 0, defaultCapacityForResult)
+		}
+		res[entity.CurrencyID] = append(res[entity.CurrencyID], entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return res, nil
+}
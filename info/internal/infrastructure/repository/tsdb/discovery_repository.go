@@ -0,0 +1,205 @@
+package tsdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	"info/internal/pkg/apperror"
+
+	"info/internal/domain"
+	"info/internal/domain/discovery"
+)
+
+type DiscoveryRepository struct {
+	*Repository
+}
+
+var _ discovery.WriteRepository = (*DiscoveryRepository)(nil)
+var _ discovery.ReadRepository = (*DiscoveryRepository)(nil)
+
+func NewDiscoveryRepository(repository *Repository) *DiscoveryRepository {
+	return &DiscoveryRepository{
+		Repository: repository,
+	}
+}
+
+const (
+	discovery_state_sql_GetAll                     = "SELECT currency_id, slug, promoted_at, last_matched_at FROM cmc.discovery_state;"
+	discovery_state_sql_MCreate                    = "INSERT INTO cmc.discovery_state(currency_id, slug, promoted_at, last_matched_at) VALUES "
+	discovery_state_sql_MCreate_OnConflictDoUpdate = " ON CONFLICT (currency_id) DO UPDATE SET slug = EXCLUDED.slug, last_matched_at = EXCLUDED.last_matched_at;"
+	discovery_state_sql_MDelete                    = "DELETE FROM cmc.discovery_state WHERE currency_id = any($1);"
+	discovery_event_sql_MCreate                    = "INSERT INTO cmc.discovery_event(currency_id, slug, action, reason, cmc_rank, market_cap, volume_24h, created_at) VALUES "
+	discovery_event_sql_Get                        = `SELECT id, currency_id, slug, action, reason, cmc_rank, market_cap, volume_24h, created_at FROM cmc.discovery_event
+		WHERE ($1 = '' OR action = $1) AND ($2 = '' OR slug = $2) AND ($3::timestamp IS NULL OR created_at >= $3::timestamp) ORDER BY created_at DESC, id DESC LIMIT $4;`
+)
+
+func (r *DiscoveryRepository) GetStateMap(ctx context.Context) (discovery.StateMap, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "DiscoveryRepository.GetStateMap"
+
+	var entity discovery.State
+	res := make(discovery.StateMap, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, discovery_state_sql_GetAll)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, discovery_state_sql_GetAll, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.CurrencyID, &entity.Slug, &entity.PromotedAt, &entity.LastMatchedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, discovery_state_sql_GetAll, err)
+		}
+		res[entity.CurrencyID] = entity
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return res, nil
+}
+
+func (r *DiscoveryRepository) MUpsertStateTx(ctx context.Context, tx domain.Tx, entities *discovery.StateList) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "DiscoveryRepository.MUpsertStateTx"
+	const fields_nb = 4
+	if entities == nil || len(*entities) == 0 {
+		return nil
+	}
+	b := strings.Builder{}
+	params := make([]interface{}, 0, len(*entities)*fields_nb)
+	b.WriteString(discovery_state_sql_MCreate)
+	for i, entity := range *entities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("($" + strconv.Itoa(i*fields_nb+1) + ", $" + strconv.Itoa(i*fields_nb+2) + ", $" + strconv.Itoa(i*fields_nb+3) + ", $" + strconv.Itoa(i*fields_nb+4) + ")")
+		params = append(params, entity.CurrencyID, entity.Slug, entity.PromotedAt, entity.LastMatchedAt)
+	}
+	b.WriteString(discovery_state_sql_MCreate_OnConflictDoUpdate)
+	start := time.Now().UTC()
+
+	if _, err := tx.Exec(ctx, b.String(), params...); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, b.String(), err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *DiscoveryRepository) MDeleteStateTx(ctx context.Context, tx domain.Tx, currencyIDs *[]uint) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "DiscoveryRepository.MDeleteStateTx"
+	if currencyIDs == nil || len(*currencyIDs) == 0 {
+		return nil
+	}
+	start := time.Now().UTC()
+
+	if _, err := tx.Exec(ctx, discovery_state_sql_MDelete, *currencyIDs); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, discovery_state_sql_MDelete, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *DiscoveryRepository) MCreateEventTx(ctx context.Context, tx domain.Tx, entities *discovery.EventList) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "DiscoveryRepository.MCreateEventTx"
+	const fields_nb = 8
+	if entities == nil || len(*entities) == 0 {
+		return nil
+	}
+	b := strings.Builder{}
+	params := make([]interface{}, 0, len(*entities)*fields_nb)
+	b.WriteString(discovery_event_sql_MCreate)
+	for i, entity := range *entities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("($" + strconv.Itoa(i*fields_nb+1) + ", $" + strconv.Itoa(i*fields_nb+2) + ", $" + strconv.Itoa(i*fields_nb+3) + ", $" + strconv.Itoa(i*fields_nb+4) + ", $" + strconv.Itoa(i*fields_nb+5) + ", $" + strconv.Itoa(i*fields_nb+6) + ", $" + strconv.Itoa(i*fields_nb+7) + ", $" + strconv.Itoa(i*fields_nb+8) + ")")
+		params = append(params, entity.CurrencyID, entity.Slug, entity.Action, entity.Reason, entity.CmcRank, entity.MarketCap, entity.Volume24h, entity.CreatedAt)
+	}
+	start := time.Now().UTC()
+
+	if _, err := tx.Exec(ctx, b.String(), params...); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, b.String(), err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *DiscoveryRepository) GetEvents(ctx context.Context, filter *discovery.EventFilter) (*discovery.EventList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "DiscoveryRepository.GetEvents"
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultCapacityForResult
+	}
+	var entity discovery.Event
+	res := make(discovery.EventList, 0, limit)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, discovery_event_sql_Get, filter.Action, filter.Slug, filter.Since, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, discovery_event_sql_Get, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.ID, &entity.CurrencyID, &entity.Slug, &entity.Action, &entity.Reason, &entity.CmcRank, &entity.MarketCap, &entity.Volume24h, &entity.CreatedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, discovery_event_sql_Get, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
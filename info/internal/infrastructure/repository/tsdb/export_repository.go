@@ -0,0 +1,166 @@
+package tsdb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/parquet"
+
+	"info/internal/domain/export"
+)
+
+type ExportRepository struct {
+	*Repository
+}
+
+var _ export.ReadRepository = (*ExportRepository)(nil)
+
+func NewExportRepository(repository *Repository) *ExportRepository {
+	return &ExportRepository{
+		Repository: repository,
+	}
+}
+
+const (
+	Export_FetchSize = 10000
+
+	export_sql_Cursor  = "export_cursor"
+	export_sql_Declare = "DECLARE " + export_sql_Cursor + " NO SCROLL CURSOR FOR "
+	export_sql_Close   = "CLOSE " + export_sql_Cursor + ";"
+
+	// the conditions of the exports of the series aliased as t: $1 is the IDs of the currencies (all if empty), $2 and $3 are the window
+	export_sql_WhereTs = " WHERE (cardinality($1::bigint[]) = 0 OR t.currency_id = any($1::bigint[])) AND t.ts >= $2::timestamp AND t.ts <= $3::timestamp"
+	export_sql_WhereD  = " WHERE (cardinality($1::bigint[]) = 0 OR t.currency_id = any($1::bigint[])) AND t.d >= $2::date AND t.d <= $3::date"
+)
+
+var export_sql_Fetch = "FETCH FORWARD " + strconv.Itoa(Export_FetchSize) + " FROM " + export_sql_Cursor + ";"
+
+// export_sql_Select is the queries of the datasets; the columns are in the order of export.Columns.
+// The quarantined points are excluded as in the other reads of the series.
+var export_sql_Select = map[string]string{
+	export.Dataset_PriceAndCap: "SELECT t.currency_id, t.price, t.daily_volume, t.cap, t.ts FROM cmc.price_and_cap t" + export_sql_WhereTs +
+		sql_And + data_quarantine_sql_NotQuarantined_PriceAndCap + " ORDER BY t.currency_id, t.ts",
+	export.Dataset_Concentration: "SELECT t.currency_id, t.whales, t.investors, t.retail, t.d FROM cmc.concentration t" + export_sql_WhereD +
+		sql_And + data_quarantine_sql_NotQuarantined_Concentration + " ORDER BY t.currency_id, t.d",
+	export.Dataset_OraculAnalytics: "SELECT t.currency_id, t.whales_concentration, t.worm_index, t.growth_fuel, t.ts FROM oracul.analytics t" + export_sql_WhereTs +
+		sql_And + data_quarantine_sql_NotQuarantined_OraculAnalytics + " ORDER BY t.currency_id, t.ts",
+	export.Dataset_OraculSpeedometers: "SELECT t.currency_id, t.whales_buy_rate, t.whales_sell_rate, t.whales_volume, t.investors_buy_rate, t.investors_sell_rate, t.investors_volume, t.retailers_buy_rate, t.retailers_sell_rate, t.retailers_volume, t.ts FROM oracul.speedometers t" + export_sql_WhereTs +
+		sql_And + data_quarantine_sql_NotQuarantined_OraculSpeedometers + " ORDER BY t.currency_id, t.ts",
+	export.Dataset_OraculHolderStats: "SELECT t.currency_id, t.whales_volume, t.whales_total_holders, t.investors_volume, t.investors_total_holders, t.retailers_volume, t.retailers_total_holders, t.ts FROM oracul.holder_stats t" + export_sql_WhereTs +
+		sql_And + data_quarantine_sql_NotQuarantined_OraculHolderStats + " ORDER BY t.currency_id, t.ts",
+	export.Dataset_OraculDailyBalanceStats: "SELECT t.currency_id, t.whales_balance, t.whales_total_holders, t.investors_balance, t.investors_total_holders, t.retailers_balance, t.retailers_total_holders, t.d FROM oracul.daily_balance_stats t" + export_sql_WhereD +
+		sql_And + data_quarantine_sql_NotQuarantined_OraculDailyBalanceStats + " ORDER BY t.currency_id, t.d",
+	export.Dataset_PortfolioHistory: "SELECT t.portfolio_source_id, t.currency_id, t.amount, t.current_price, t.crypto_holdings, t.holdings_percent, t.buy_avg_price, t.pl_percent_value, t.pl_value, t.total_buy_spent, t.updated_at, t.ts FROM cmc.portfolio_item_history t" + export_sql_WhereTs +
+		" ORDER BY t.portfolio_source_id, t.currency_id, t.ts",
+}
+
+// Stream reads the rows with the server side cursor in the read-only transaction by Export_FetchSize rows,
+// so neither the client nor the connection hold the whole export.
+func (r *ExportRepository) Stream(ctx context.Context, filter *export.Filter, fn func(row []any) error) (err error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "ExportRepository.Stream"
+
+	query, ok := export_sql_Select[filter.Dataset]
+	if !ok {
+		return fmt.Errorf("[%w] %s unknown dataset: %q", apperror.ErrInternal, metricName, filter.Dataset)
+	}
+	columns := export.Columns[filter.Dataset]
+	dest, row := export_Dest(columns)
+
+	start := time.Now().UTC()
+	defer func() {
+		success := metricsSuccess
+		if err != nil {
+			success = metricsFail
+		}
+		r.metrics.SqlMetrics.Inc(metricName, success)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, success)
+	}()
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("[%w] %s begin transaction error: %w", apperror.ErrInternal, metricName, err)
+	}
+	// транзакция только читает: откат закрывает курсор
+	defer tx.Rollback(ctx)
+
+	currencyIDs := filter.CurrencyIDs
+	if currencyIDs == nil {
+		currencyIDs = []uint{}
+	}
+	if _, err = tx.Exec(ctx, export_sql_Declare+query, currencyIDs, filter.From, filter.To); err != nil {
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, export_sql_Declare+query, err)
+	}
+
+	var rows pgx.Rows
+	for {
+		rows, err = tx.Query(ctx, export_sql_Fetch)
+		if err != nil {
+			return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, export_sql_Fetch, err)
+		}
+		rowsNb := 0
+		for rows.Next() {
+			if err = rows.Scan(dest...); err != nil {
+				rows.Close()
+				return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, export_sql_Fetch, err)
+			}
+			export_Row(columns, dest, row)
+			if err = fn(row); err != nil {
+				rows.Close()
+				return err
+			}
+			rowsNb++
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, export_sql_Fetch, err)
+		}
+		if rowsNb < Export_FetchSize {
+			break
+		}
+	}
+
+	if _, err = tx.Exec(ctx, export_sql_Close); err != nil {
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, export_sql_Close, err)
+	}
+	return nil
+}
+
+// export_Dest returns the destinations of the scan by the types of the columns and the row of the values.
+func export_Dest(columns []parquet.Column) (dest []any, row []any) {
+	dest = make([]any, len(columns))
+	for i, c := range columns {
+		switch c.Type {
+		case parquet.Type_Int64:
+			dest[i] = new(int64)
+		case parquet.Type_Double:
+			dest[i] = new(float64)
+		case parquet.Type_String:
+			dest[i] = new(string)
+		default:
+			dest[i] = new(time.Time)
+		}
+	}
+	return dest, make([]any, len(columns))
+}
+
+func export_Row(columns []parquet.Column, dest []any, row []any) {
+	for i, c := range columns {
+		switch c.Type {
+		case parquet.Type_Int64:
+			row[i] = *dest[i].(*int64)
+		case parquet.Type_Double:
+			row[i] = *dest[i].(*float64)
+		case parquet.Type_String:
+			row[i] = *dest[i].(*string)
+		default:
+			row[i] = *dest[i].(*time.Time)
+		}
+	}
+}
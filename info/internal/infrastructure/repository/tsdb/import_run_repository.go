@@ -0,0 +1,166 @@
+package tsdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	"info/internal/pkg/apperror"
+
+	"info/internal/domain/import_run"
+)
+
+type ImportRunRepository struct {
+	*Repository
+}
+
+var _ import_run.WriteRepository = (*ImportRunRepository)(nil)
+var _ import_run.ReadRepository = (*ImportRunRepository)(nil)
+
+func NewImportRunRepository(repository *Repository) *ImportRunRepository {
+	return &ImportRunRepository{
+		Repository: repository,
+	}
+}
+
+const (
+	import_run_sql_Create          = "INSERT INTO cmc.import_run(kind, status, items_succeeded, items_failed, error, started_at, finished_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;"
+	import_run_sql_Update          = "UPDATE cmc.import_run SET status = $2, items_succeeded = $3, items_failed = $4, error = $5, finished_at = $6 WHERE id = $1;"
+	import_run_sql_GetLastRuns     = "SELECT id, kind, status, items_succeeded, items_failed, error, started_at, finished_at FROM cmc.import_run WHERE ($1 = '' OR kind = $1) ORDER BY started_at DESC LIMIT $2;"
+	import_run_item_sql_Create     = "INSERT INTO cmc.import_run_item(import_run_id, kind, key, currency_id, rows_upserted, time_from, time_to, error, started_at, finished_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);"
+	import_run_item_sql_StatusList = `SELECT DISTINCT ON (i.kind, i.key) i.kind, i.key, i.currency_id, i.started_at, i.finished_at, i.error,
+		(SELECT max(s.finished_at) FROM cmc.import_run_item s WHERE s.kind = i.kind AND s.key = i.key AND s.error IS NULL) AS last_success_at
+		FROM cmc.import_run_item i WHERE ($1 = '' OR i.kind = $1) ORDER BY i.kind, i.key, i.started_at DESC;`
+)
+
+func (r *ImportRunRepository) Create(ctx context.Context, entity *import_run.ImportRun) (ID uint, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "ImportRunRepository.Create"
+	start := time.Now().UTC()
+
+	if err := r.db.QueryRow(ctx, import_run_sql_Create, entity.Kind, entity.Status, entity.ItemsSucceeded, entity.ItemsFailed, entity.Error, entity.StartedAt, entity.FinishedAt).Scan(&ID); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return 0, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, import_run_sql_Create, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return ID, nil
+}
+
+func (r *ImportRunRepository) Update(ctx context.Context, entity *import_run.ImportRun) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "ImportRunRepository.Update"
+	start := time.Now().UTC()
+
+	if _, err := r.db.Exec(ctx, import_run_sql_Update, entity.ID, entity.Status, entity.ItemsSucceeded, entity.ItemsFailed, entity.Error, entity.FinishedAt); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, import_run_sql_Update, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *ImportRunRepository) CreateItem(ctx context.Context, entity *import_run.ImportRunItem) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "ImportRunRepository.CreateItem"
+	start := time.Now().UTC()
+
+	if _, err := r.db.Exec(ctx, import_run_item_sql_Create, entity.ImportRunID, entity.Kind, entity.Key, entity.CurrencyID, entity.Rows, entity.TimeFrom, entity.TimeTo, entity.Error, entity.StartedAt, entity.FinishedAt); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, import_run_item_sql_Create, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+func (r *ImportRunRepository) GetLastRuns(ctx context.Context, kind string, limit uint) (*import_run.ImportRunList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "ImportRunRepository.GetLastRuns"
+
+	var entity import_run.ImportRun
+	res := make(import_run.ImportRunList, 0, limit)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, import_run_sql_GetLastRuns, kind, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, import_run_sql_GetLastRuns, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.ID, &entity.Kind, &entity.Status, &entity.ItemsSucceeded, &entity.ItemsFailed, &entity.Error, &entity.StartedAt, &entity.FinishedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, import_run_sql_GetLastRuns, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
+func (r *ImportRunRepository) GetItemStatusList(ctx context.Context, kind string) (*import_run.ItemStatusList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "ImportRunRepository.GetItemStatusList"
+
+	var entity import_run.ItemStatus
+	res := make(import_run.ItemStatusList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, import_run_item_sql_StatusList, kind)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, import_run_item_sql_StatusList, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.Kind, &entity.Key, &entity.CurrencyID, &entity.LastStartedAt, &entity.LastFinishedAt, &entity.LastError, &entity.LastSuccessAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, import_run_item_sql_StatusList, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
@@ -3,6 +3,7 @@ package tsdb
 import (
 	"context"
 	"fmt"
+	pgx "github.com/jackc/pgx/v5"
 	"info/internal/domain/oracul_analytics"
 	"info/internal/pkg/apperror"
 	"time"
@@ -22,7 +23,10 @@ func NewOraculAnalyticsRepository(repository *Repository) *OraculAnalyticsReposi
 }
 
 const (
-	oracul_analytics_sql_Upsert = "INSERT INTO oracul.analytics(currency_id, whales_concentration, worm_index, growth_fuel, ts) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (currency_id, ts) DO UPDATE SET whales_concentration = EXCLUDED.whales_concentration, worm_index = EXCLUDED.worm_index, growth_fuel = EXCLUDED.growth_fuel;"
+	oracul_analytics_sql_Upsert     = "INSERT INTO oracul.analytics(currency_id, whales_concentration, worm_index, growth_fuel, ts) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (currency_id, ts) DO UPDATE SET whales_concentration = EXCLUDED.whales_concentration, worm_index = EXCLUDED.worm_index, growth_fuel = EXCLUDED.growth_fuel;"
+	oracul_analytics_sql_GetLast    = "SELECT currency_id, whales_concentration, worm_index, growth_fuel, ts FROM oracul.analytics t WHERE currency_id = $1 AND " + data_quarantine_sql_NotQuarantined_OraculAnalytics + " ORDER BY ts DESC LIMIT 1;"
+	oracul_analytics_sql_GetBetween = "SELECT currency_id, whales_concentration, worm_index, growth_fuel, ts FROM oracul.analytics t WHERE currency_id = $1 AND ts >= $2 AND ts <= $3 AND " + data_quarantine_sql_NotQuarantined_OraculAnalytics + " ORDER BY ts;"
+	oracul_analytics_sql_MGetLast   = "SELECT DISTINCT ON (currency_id) currency_id, whales_concentration, worm_index, growth_fuel, ts FROM oracul.analytics t WHERE currency_id = any($1) AND " + data_quarantine_sql_NotQuarantined_OraculAnalytics + " ORDER BY currency_id, ts DESC;"
 )
 
 func (r *OraculAnalyticsRepository) Upsert(ctx context.Context, entity *oracul_analytics.OraculAnalytics) error {
@@ -40,3 +44,35 @@ func (r *OraculAnalyticsRepository) Upsert(ctx context.Context, entity *oracul_a
 	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
 	return nil
 }
+
+func scanOraculAnalytics(row pgx.Row, e *oracul_analytics.OraculAnalytics) error {
+	return row.Scan(&e.CurrencyID, &e.WhalesConcentration, &e.WormIndex, &e.GrowthFuel, &e.Ts)
+}
+
+// GetLast returns the latest analytics of the currency.
+func (r *OraculAnalyticsRepository) GetLast(ctx context.Context, currencyID uint) (*oracul_analytics.OraculAnalytics, error) {
+	return queryRow(ctx, r.Repository, "OraculAnalyticsRepository.GetLast", oracul_analytics_sql_GetLast, scanOraculAnalytics, currencyID)
+}
+
+// GetBetween returns the analytics of the currency in the window [from, to], from the oldest to the newest.
+func (r *OraculAnalyticsRepository) GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*oracul_analytics.OraculAnalyticsList, error) {
+	l, err := queryList(ctx, r.Repository, "OraculAnalyticsRepository.GetBetween", oracul_analytics_sql_GetBetween, scanOraculAnalytics, currencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	res := oracul_analytics.OraculAnalyticsList(l)
+	return &res, nil
+}
+
+// MGetLast returns the latest analytics of every currency.
+func (r *OraculAnalyticsRepository) MGetLast(ctx context.Context, currencyIDs *[]uint) (*oracul_analytics.OraculAnalyticsList, error) {
+	l, err := queryList(ctx, r.Repository, "OraculAnalyticsRepository.MGetLast", oracul_analytics_sql_MGetLast, scanOraculAnalytics, *currencyIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+	res := oracul_analytics.OraculAnalyticsList(l)
+	return &res, nil
+}
@@ -3,6 +3,7 @@ package tsdb
 import (
 	"context"
 	"fmt"
+	pgx "github.com/jackc/pgx/v5"
 	"info/internal/domain/oracul_daily_balance_stats"
 	"info/internal/pkg/apperror"
 	"strconv"
@@ -28,6 +29,9 @@ const (
 
 	oracul_daily_balance_stats_sql_MUpsert                    = "INSERT INTO oracul.daily_balance_stats(currency_id, whales_balance, whales_total_holders, investors_balance, investors_total_holders, retailers_balance, retailers_total_holders, d) VALUES "
 	oracul_daily_balance_stats_sql_MUpsert_OnConflictDoUpdate = " ON CONFLICT (currency_id, d) DO UPDATE SET whales_balance = EXCLUDED.whales_balance, whales_total_holders = EXCLUDED.whales_total_holders, investors_balance = EXCLUDED.investors_balance, investors_total_holders = EXCLUDED.investors_total_holders, retailers_balance = EXCLUDED.retailers_balance, retailers_total_holders = EXCLUDED.retailers_total_holders;"
+	oracul_daily_balance_stats_sql_GetLast                    = "SELECT currency_id, whales_balance, whales_total_holders, investors_balance, investors_total_holders, retailers_balance, retailers_total_holders, d FROM oracul.daily_balance_stats t WHERE currency_id = $1 AND " + data_quarantine_sql_NotQuarantined_OraculDailyBalanceStats + " ORDER BY d DESC LIMIT 1;"
+	oracul_daily_balance_stats_sql_GetBetween                 = "SELECT currency_id, whales_balance, whales_total_holders, investors_balance, investors_total_holders, retailers_balance, retailers_total_holders, d FROM oracul.daily_balance_stats t WHERE currency_id = $1 AND d >= $2::date AND d <= $3::date AND " + data_quarantine_sql_NotQuarantined_OraculDailyBalanceStats + " ORDER BY d;"
+	oracul_daily_balance_stats_sql_MGetLast                   = "SELECT DISTINCT ON (currency_id) currency_id, whales_balance, whales_total_holders, investors_balance, investors_total_holders, retailers_balance, retailers_total_holders, d FROM oracul.daily_balance_stats t WHERE currency_id = any($1) AND " + data_quarantine_sql_NotQuarantined_OraculDailyBalanceStats + " ORDER BY currency_id, d DESC;"
 )
 
 func (r *OraculDailyBalanceStatsRepository) MUpsert(ctx context.Context, entities *oracul_daily_balance_stats.OraculDailyBalanceStatsList) error {
@@ -82,3 +86,35 @@ func (r *OraculDailyBalanceStatsRepository) mUpsert(ctx context.Context, entitie
 	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
 	return nil
 }
+
+func scanOraculDailyBalanceStats(row pgx.Row, e *oracul_daily_balance_stats.OraculDailyBalanceStats) error {
+	return row.Scan(&e.CurrencyID, &e.WhalesBalance, &e.WhalesTotalHolders, &e.InvestorsBalance, &e.InvestorsTotalHolders, &e.RetailersBalance, &e.RetailersTotalHolders, &e.D)
+}
+
+// GetLast returns the latest daily balance stats of the currency.
+func (r *OraculDailyBalanceStatsRepository) GetLast(ctx context.Context, currencyID uint) (*oracul_daily_balance_stats.OraculDailyBalanceStats, error) {
+	return queryRow(ctx, r.Repository, "OraculDailyBalanceStatsRepository.GetLast", oracul_daily_balance_stats_sql_GetLast, scanOraculDailyBalanceStats, currencyID)
+}
+
+// GetBetween returns the daily balance stats of the currency in the window [from, to], from the oldest to the newest.
+func (r *OraculDailyBalanceStatsRepository) GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*oracul_daily_balance_stats.OraculDailyBalanceStatsList, error) {
+	l, err := queryList(ctx, r.Repository, "OraculDailyBalanceStatsRepository.GetBetween", oracul_daily_balance_stats_sql_GetBetween, scanOraculDailyBalanceStats, currencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	res := oracul_daily_balance_stats.OraculDailyBalanceStatsList(l)
+	return &res, nil
+}
+
+// MGetLast returns the latest daily balance stats of every currency.
+func (r *OraculDailyBalanceStatsRepository) MGetLast(ctx context.Context, currencyIDs *[]uint) (*oracul_daily_balance_stats.OraculDailyBalanceStatsList, error) {
+	l, err := queryList(ctx, r.Repository, "OraculDailyBalanceStatsRepository.MGetLast", oracul_daily_balance_stats_sql_MGetLast, scanOraculDailyBalanceStats, *currencyIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+	res := oracul_daily_balance_stats.OraculDailyBalanceStatsList(l)
+	return &res, nil
+}
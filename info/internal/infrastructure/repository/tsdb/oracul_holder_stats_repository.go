@@ -3,6 +3,7 @@ package tsdb
 import (
 	"context"
 	"fmt"
+	pgx "github.com/jackc/pgx/v5"
 	"info/internal/domain/oracul_holder_stats"
 	"info/internal/pkg/apperror"
 	"time"
@@ -22,7 +23,10 @@ func NewOraculHolderStatsRepository(repository *Repository) *OraculHolderStatsRe
 }
 
 const (
-	oracul_holder_stats_sql_Upsert = "INSERT INTO oracul.holder_stats(currency_id, whales_volume, whales_total_holders, investors_volume, investors_total_holders, retailers_volume, retailers_total_holders, ts) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (currency_id, ts) DO UPDATE SET whales_volume = EXCLUDED.whales_volume, whales_total_holders = EXCLUDED.whales_total_holders, investors_volume = EXCLUDED.investors_volume, investors_total_holders = EXCLUDED.investors_total_holders, retailers_volume = EXCLUDED.retailers_volume, retailers_total_holders = EXCLUDED.retailers_total_holders;"
+	oracul_holder_stats_sql_Upsert     = "INSERT INTO oracul.holder_stats(currency_id, whales_volume, whales_total_holders, investors_volume, investors_total_holders, retailers_volume, retailers_total_holders, ts) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (currency_id, ts) DO UPDATE SET whales_volume = EXCLUDED.whales_volume, whales_total_holders = EXCLUDED.whales_total_holders, investors_volume = EXCLUDED.investors_volume, investors_total_holders = EXCLUDED.investors_total_holders, retailers_volume = EXCLUDED.retailers_volume, retailers_total_holders = EXCLUDED.retailers_total_holders;"
+	oracul_holder_stats_sql_GetLast    = "SELECT currency_id, whales_volume, whales_total_holders, investors_volume, investors_total_holders, retailers_volume, retailers_total_holders, ts FROM oracul.holder_stats t WHERE currency_id = $1 AND " + data_quarantine_sql_NotQuarantined_OraculHolderStats + " ORDER BY ts DESC LIMIT 1;"
+	oracul_holder_stats_sql_GetBetween = "SELECT currency_id, whales_volume, whales_total_holders, investors_volume, investors_total_holders, retailers_volume, retailers_total_holders, ts FROM oracul.holder_stats t WHERE currency_id = $1 AND ts >= $2 AND ts <= $3 AND " + data_quarantine_sql_NotQuarantined_OraculHolderStats + " ORDER BY ts;"
+	oracul_holder_stats_sql_MGetLast   = "SELECT DISTINCT ON (currency_id) currency_id, whales_volume, whales_total_holders, investors_volume, investors_total_holders, retailers_volume, retailers_total_holders, ts FROM oracul.holder_stats t WHERE currency_id = any($1) AND " + data_quarantine_sql_NotQuarantined_OraculHolderStats + " ORDER BY currency_id, ts DESC;"
 )
 
 func (r *OraculHolderStatsRepository) Upsert(ctx context.Context, entity *oracul_holder_stats.OraculHolderStats) error {
@@ -40,3 +44,35 @@ func (r *OraculHolderStatsRepository) Upsert(ctx context.Context, entity *oracul
 	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
 	return nil
 }
+
+func scanOraculHolderStats(row pgx.Row, e *oracul_holder_stats.OraculHolderStats) error {
+	return row.Scan(&e.CurrencyID, &e.WhalesVolume, &e.WhalesTotalHolders, &e.InvestorsVolume, &e.InvestorsTotalHolders, &e.RetailersVolume, &e.RetailersTotalHolders, &e.Ts)
+}
+
+// GetLast returns the latest holder stats of the currency.
+func (r *OraculHolderStatsRepository) GetLast(ctx context.Context, currencyID uint) (*oracul_holder_stats.OraculHolderStats, error) {
+	return queryRow(ctx, r.Repository, "OraculHolderStatsRepository.GetLast", oracul_holder_stats_sql_GetLast, scanOraculHolderStats, currencyID)
+}
+
+// GetBetween returns the holder stats of the currency in the window [from, to], from the oldest to the newest.
+func (r *OraculHolderStatsRepository) GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*oracul_holder_stats.OraculHolderStatsList, error) {
+	l, err := queryList(ctx, r.Repository, "OraculHolderStatsRepository.GetBetween", oracul_holder_stats_sql_GetBetween, scanOraculHolderStats, currencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	res := oracul_holder_stats.OraculHolderStatsList(l)
+	return &res, nil
+}
+
+// MGetLast returns the latest holder stats of every currency.
+func (r *OraculHolderStatsRepository) MGetLast(ctx context.Context, currencyIDs *[]uint) (*oracul_holder_stats.OraculHolderStatsList, error) {
+	l, err := queryList(ctx, r.Repository, "OraculHolderStatsRepository.MGetLast", oracul_holder_stats_sql_MGetLast, scanOraculHolderStats, *currencyIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+	res := oracul_holder_stats.OraculHolderStatsList(l)
+	return &res, nil
+}
@@ -3,6 +3,7 @@ package tsdb
 import (
 	"context"
 	"fmt"
+	pgx "github.com/jackc/pgx/v5"
 	"time"
 
 	"info/internal/pkg/apperror"
@@ -24,7 +25,10 @@ func NewOraculSpeedometersRepository(repository *Repository) *OraculSpeedometers
 }
 
 const (
-	oracul_speedometers_sql_Upsert = "INSERT INTO oracul.speedometers(currency_id, whales_buy_rate, whales_sell_rate, whales_volume, investors_buy_rate, investors_sell_rate, investors_volume, retailers_buy_rate, retailers_sell_rate, retailers_volume, ts) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (currency_id, ts) DO UPDATE SET whales_buy_rate = EXCLUDED.whales_buy_rate, whales_sell_rate = EXCLUDED.whales_sell_rate, whales_volume = EXCLUDED.whales_volume, investors_buy_rate = EXCLUDED.investors_buy_rate, investors_sell_rate = EXCLUDED.investors_sell_rate, investors_volume = EXCLUDED.investors_volume, retailers_buy_rate = EXCLUDED.retailers_buy_rate, retailers_sell_rate = EXCLUDED.retailers_sell_rate, retailers_volume = EXCLUDED.retailers_volume;"
+	oracul_speedometers_sql_Upsert     = "INSERT INTO oracul.speedometers(currency_id, whales_buy_rate, whales_sell_rate, whales_volume, investors_buy_rate, investors_sell_rate, investors_volume, retailers_buy_rate, retailers_sell_rate, retailers_volume, ts) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (currency_id, ts) DO UPDATE SET whales_buy_rate = EXCLUDED.whales_buy_rate, whales_sell_rate = EXCLUDED.whales_sell_rate, whales_volume = EXCLUDED.whales_volume, investors_buy_rate = EXCLUDED.investors_buy_rate, investors_sell_rate = EXCLUDED.investors_sell_rate, investors_volume = EXCLUDED.investors_volume, retailers_buy_rate = EXCLUDED.retailers_buy_rate, retailers_sell_rate = EXCLUDED.retailers_sell_rate, retailers_volume = EXCLUDED.retailers_volume;"
+	oracul_speedometers_sql_GetLast    = "SELECT currency_id, whales_buy_rate, whales_sell_rate, whales_volume, investors_buy_rate, investors_sell_rate, investors_volume, retailers_buy_rate, retailers_sell_rate, retailers_volume, ts FROM oracul.speedometers t WHERE currency_id = $1 AND " + data_quarantine_sql_NotQuarantined_OraculSpeedometers + " ORDER BY ts DESC LIMIT 1;"
+	oracul_speedometers_sql_GetBetween = "SELECT currency_id, whales_buy_rate, whales_sell_rate, whales_volume, investors_buy_rate, investors_sell_rate, investors_volume, retailers_buy_rate, retailers_sell_rate, retailers_volume, ts FROM oracul.speedometers t WHERE currency_id = $1 AND ts >= $2 AND ts <= $3 AND " + data_quarantine_sql_NotQuarantined_OraculSpeedometers + " ORDER BY ts;"
+	oracul_speedometers_sql_MGetLast   = "SELECT DISTINCT ON (currency_id) currency_id, whales_buy_rate, whales_sell_rate, whales_volume, investors_buy_rate, investors_sell_rate, investors_volume, retailers_buy_rate, retailers_sell_rate, retailers_volume, ts FROM oracul.speedometers t WHERE currency_id = any($1) AND " + data_quarantine_sql_NotQuarantined_OraculSpeedometers + " ORDER BY currency_id, ts DESC;"
 )
 
 func (r *OraculSpeedometersRepository) Upsert(ctx context.Context, entity *oracul_speedometers.OraculSpeedometers) error {
@@ -42,3 +46,35 @@ func (r *OraculSpeedometersRepository) Upsert(ctx context.Context, entity *oracu
 	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
 	return nil
 }
+
+func scanOraculSpeedometers(row pgx.Row, e *oracul_speedometers.OraculSpeedometers) error {
+	return row.Scan(&e.CurrencyID, &e.WhalesBuyRate, &e.WhalesSellRate, &e.WhalesVolume, &e.InvestorsBuyRate, &e.InvestorsSellRate, &e.InvestorsVolume, &e.RetailersBuyRate, &e.RetailersSellRate, &e.RetailersVolume, &e.Ts)
+}
+
+// GetLast returns the latest speedometers of the currency.
+func (r *OraculSpeedometersRepository) GetLast(ctx context.Context, currencyID uint) (*oracul_speedometers.OraculSpeedometers, error) {
+	return queryRow(ctx, r.Repository, "OraculSpeedometersRepository.GetLast", oracul_speedometers_sql_GetLast, scanOraculSpeedometers, currencyID)
+}
+
+// GetBetween returns the speedometers of the currency in the window [from, to], from the oldest to the newest.
+func (r *OraculSpeedometersRepository) GetBetween(ctx context.Context, currencyID uint, from time.Time, to time.Time) (*oracul_speedometers.OraculSpeedometersList, error) {
+	l, err := queryList(ctx, r.Repository, "OraculSpeedometersRepository.GetBetween", oracul_speedometers_sql_GetBetween, scanOraculSpeedometers, currencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	res := oracul_speedometers.OraculSpeedometersList(l)
+	return &res, nil
+}
+
+// MGetLast returns the latest speedometers of every currency.
+func (r *OraculSpeedometersRepository) MGetLast(ctx context.Context, currencyIDs *[]uint) (*oracul_speedometers.OraculSpeedometersList, error) {
+	l, err := queryList(ctx, r.Repository, "OraculSpeedometersRepository.MGetLast", oracul_speedometers_sql_MGetLast, scanOraculSpeedometers, *currencyIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(l) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+	res := oracul_speedometers.OraculSpeedometersList(l)
+	return &res, nil
+}
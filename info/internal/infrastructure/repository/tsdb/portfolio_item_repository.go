@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	pgx "github.com/jackc/pgx/v5"
+	"info/internal/domain"
 	"info/internal/domain/portfolio_item"
 	"info/internal/pkg/apperror"
 	"strconv"
@@ -30,11 +31,15 @@ func NewPortfolioItemRepository(repository *Repository) *PortfolioItemRepository
 
 const (
 	portfolio_item_sql_MGet                      = "SELECT portfolio_source_id, currency_id, amount, current_price, crypto_holdings, holdings_percent, buy_avg_price, pl_percent_value, pl_value, total_buy_spent, updated_at FROM cmc.portfolio_item WHERE portfolio_source_id = $1;"
+	portfolio_item_sql_GetAll                    = "SELECT portfolio_source_id, currency_id, amount, current_price, crypto_holdings, holdings_percent, buy_avg_price, pl_percent_value, pl_value, total_buy_spent, updated_at FROM cmc.portfolio_item ORDER BY portfolio_source_id;"
 	portfolio_item_sql_MCreate                   = "INSERT INTO cmc.portfolio_item(portfolio_source_id, currency_id, amount, current_price, crypto_holdings, holdings_percent, buy_avg_price, pl_percent_value, pl_value, total_buy_spent, updated_at) VALUES "
 	portfolio_item_sql_Create_OnConflictDoUpdate = " ON CONFLICT (portfolio_source_id, currency_id) DO UPDATE SET amount = EXCLUDED.amount, current_price = EXCLUDED.current_price, crypto_holdings = EXCLUDED.crypto_holdings, holdings_percent = EXCLUDED.holdings_percent, buy_avg_price = EXCLUDED.buy_avg_price, pl_percent_value = EXCLUDED.pl_percent_value, pl_value = EXCLUDED.pl_value, total_buy_spent = EXCLUDED.total_buy_spent, updated_at = EXCLUDED.updated_at;"
+	portfolio_item_sql_GetHistory                = "SELECT portfolio_source_id, currency_id, amount, current_price, crypto_holdings, holdings_percent, buy_avg_price, pl_percent_value, pl_value, total_buy_spent, updated_at, ts FROM cmc.portfolio_item_history WHERE portfolio_source_id = $1 AND ts BETWEEN $2 AND $3 ORDER BY ts, currency_id;"
+	portfolio_item_sql_MCreateHistory            = "INSERT INTO cmc.portfolio_item_history(portfolio_source_id, currency_id, amount, current_price, crypto_holdings, holdings_percent, buy_avg_price, pl_percent_value, pl_value, total_buy_spent, updated_at, ts) VALUES "
+	portfolio_item_sql_MCreateHistory_OnConflict = " ON CONFLICT (portfolio_source_id, currency_id, ts) DO NOTHING;"
 )
 
-func (r *PortfolioItemRepository) MGetByPortfolioSourceId(ctx context.Context, portfolioSourceId uint) (*portfolio_item.PortfolioItemMap, error) {
+func (r *PortfolioItemRepository) MGetByPortfolioSourceId(ctx context.Context, portfolioSourceId string) (*portfolio_item.PortfolioItemMap, error) {
 	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	//defer cancel()
 	const metricName = "PortfolioItemRepository.MGetByPortfolioSourceId"
@@ -74,6 +79,88 @@ func (r *PortfolioItemRepository) MGetByPortfolioSourceId(ctx context.Context, p
 	return &res, nil
 }
 
+// GetAll returns the items of all the portfolios sorted by the portfolio.
+func (r *PortfolioItemRepository) GetAll(ctx context.Context) (*portfolio_item.PortfolioItemList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "PortfolioItemRepository.GetAll"
+
+	var entity portfolio_item.PortfolioItem
+	res := make(portfolio_item.PortfolioItemList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, portfolio_item_sql_GetAll)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, portfolio_item_sql_GetAll, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.PortfolioSourceID, &entity.CurrencyID, &entity.Amount, &entity.CurrentPrice, &entity.CryptoHoldings, &entity.HoldingsPercent, &entity.BuyAvgPrice, &entity.PlPercentValue, &entity.PlValue, &entity.TotalBuySpent, &entity.UpdatedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, portfolio_item_sql_GetAll, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
+// GetHistory returns the snapshots of the portfolio from the window [from, to] sorted by the time of the snapshot.
+func (r *PortfolioItemRepository) GetHistory(ctx context.Context, portfolioSourceId string, from time.Time, to time.Time) (*portfolio_item.PortfolioItemHistoryList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "PortfolioItemRepository.GetHistory"
+
+	var entity portfolio_item.PortfolioItemHistory
+	res := make(portfolio_item.PortfolioItemHistoryList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, portfolio_item_sql_GetHistory, portfolioSourceId, from, to)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, portfolio_item_sql_GetHistory, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.PortfolioSourceID, &entity.CurrencyID, &entity.Amount, &entity.CurrentPrice, &entity.CryptoHoldings, &entity.HoldingsPercent, &entity.BuyAvgPrice, &entity.PlPercentValue, &entity.PlValue, &entity.TotalBuySpent, &entity.UpdatedAt, &entity.Ts); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, portfolio_item_sql_GetHistory, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
 func (r PortfolioItemRepository) MUpsert(ctx context.Context, entities *portfolio_item.PortfolioItemList) error {
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
@@ -105,3 +192,68 @@ func (r PortfolioItemRepository) MUpsert(ctx context.Context, entities *portfoli
 	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
 	return nil
 }
+
+func (r PortfolioItemRepository) MUpsertTx(ctx context.Context, tx domain.Tx, entities *portfolio_item.PortfolioItemList) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "PortfolioItemRepository.MUpsertTx"
+	const fields_nb = 11
+	if len(*entities) == 0 {
+		return nil
+	}
+	b := strings.Builder{}
+	params := make([]interface{}, 0, len(*entities)*fields_nb)
+	b.WriteString(portfolio_item_sql_MCreate)
+	for i, entity := range *entities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("($" + strconv.Itoa(i*fields_nb+1) + ", $" + strconv.Itoa(i*fields_nb+2) + ", $" + strconv.Itoa(i*fields_nb+3) + ", $" + strconv.Itoa(i*fields_nb+4) + ", $" + strconv.Itoa(i*fields_nb+5) + ", $" + strconv.Itoa(i*fields_nb+6) + ", $" + strconv.Itoa(i*fields_nb+7) + ", $" + strconv.Itoa(i*fields_nb+8) + ", $" + strconv.Itoa(i*fields_nb+9) + ", $" + strconv.Itoa(i*fields_nb+10) + ", $" + strconv.Itoa(i*fields_nb+11) + ")")
+		params = append(params, entity.PortfolioSourceID, entity.CurrencyID, entity.Amount, entity.CurrentPrice, entity.CryptoHoldings, entity.HoldingsPercent, entity.BuyAvgPrice, entity.PlPercentValue, entity.PlValue, entity.TotalBuySpent, entity.UpdatedAt)
+	}
+	b.WriteString(portfolio_item_sql_Create_OnConflictDoUpdate)
+	start := time.Now().UTC()
+
+	_, err := tx.Exec(ctx, b.String(), params...)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, b.String(), err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
+
+// MCreateHistoryTx stores the items as the snapshot of the time ts; the snapshot which is already stored is not changed.
+func (r PortfolioItemRepository) MCreateHistoryTx(ctx context.Context, tx domain.Tx, ts time.Time, entities *portfolio_item.PortfolioItemList) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "PortfolioItemRepository.MCreateHistoryTx"
+	const fields_nb = 12
+	if len(*entities) == 0 {
+		return nil
+	}
+	b := strings.Builder{}
+	params := make([]interface{}, 0, len(*entities)*fields_nb)
+	b.WriteString(portfolio_item_sql_MCreateHistory)
+	for i, entity := range *entities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("($" + strconv.Itoa(i*fields_nb+1) + ", $" + strconv.Itoa(i*fields_nb+2) + ", $" + strconv.Itoa(i*fields_nb+3) + ", $" + strconv.Itoa(i*fields_nb+4) + ", $" + strconv.Itoa(i*fields_nb+5) + ", $" + strconv.Itoa(i*fields_nb+6) + ", $" + strconv.Itoa(i*fields_nb+7) + ", $" + strconv.Itoa(i*fields_nb+8) + ", $" + strconv.Itoa(i*fields_nb+9) + ", $" + strconv.Itoa(i*fields_nb+10) + ", $" + strconv.Itoa(i*fields_nb+11) + ", $" + strconv.Itoa(i*fields_nb+12) + ")")
+		params = append(params, entity.PortfolioSourceID, entity.CurrencyID, entity.Amount, entity.CurrentPrice, entity.CryptoHoldings, entity.HoldingsPercent, entity.BuyAvgPrice, entity.PlPercentValue, entity.PlValue, entity.TotalBuySpent, entity.UpdatedAt, ts)
+	}
+	b.WriteString(portfolio_item_sql_MCreateHistory_OnConflict)
+	start := time.Now().UTC()
+
+	_, err := tx.Exec(ctx, b.String(), params...)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, b.String(), err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return nil
+}
@@ -32,22 +32,50 @@ func NewPriceAndCapRepository(repository *Repository) *PriceAndCapRepository {
 const (
 	MUpsertPriceAndCap_Limit = 13000 // 5 пар-ра * 13т = 65т ~= max
 
-	price_and_cap_sql_MGet                       = "SELECT currency_id, price, daily_volume, cap, ts FROM cmc.price_and_cap WHERE currency_id = any($1) ORDER BY ts DESC;"
+	price_and_cap_sql_MGet                       = "SELECT currency_id, price, daily_volume, cap, ts FROM cmc.price_and_cap t WHERE currency_id = any($1) AND " + data_quarantine_sql_NotQuarantined_PriceAndCap + " ORDER BY ts DESC;"
 	price_and_cap_sql_Upsert                     = "INSERT INTO cmc.price_and_cap(currency_id, price, daily_volume, cap, ts) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (currency_id, ts) DO UPDATE SET price = EXCLUDED.price, daily_volume = EXCLUDED.daily_volume, cap = EXCLUDED.cap;"
 	price_and_cap_sql_MUpsert                    = "INSERT INTO cmc.price_and_cap(currency_id, price, daily_volume, cap, ts) VALUES "
 	price_and_cap_sql_MUpsert_OnConflictDoUpdate = " ON CONFLICT (currency_id, ts) DO UPDATE SET price = EXCLUDED.price, daily_volume = EXCLUDED.daily_volume, cap = EXCLUDED.cap;"
+	price_and_cap_sql_GetCandles                 = `SELECT time_bucket($2::interval, ts) AS bucket, first(price, ts), max(price), min(price), last(price, ts), avg(cap), sum(daily_volume), count(*)
+		FROM cmc.price_and_cap t WHERE currency_id = $1 AND ts >= $3 AND ts <= $4 AND ` + data_quarantine_sql_NotQuarantined_PriceAndCap + ` GROUP BY bucket ORDER BY bucket LIMIT $5 OFFSET $6;`
+	price_and_cap_sql_CountCandles = "SELECT count(DISTINCT time_bucket($2::interval, ts)) FROM cmc.price_and_cap t WHERE currency_id = $1 AND ts >= $3 AND ts <= $4 AND " + data_quarantine_sql_NotQuarantined_PriceAndCap + ";"
+	price_and_cap_sql_GetLast      = "SELECT currency_id, price, daily_volume, cap, ts FROM cmc.price_and_cap t WHERE currency_id = $1 AND " + data_quarantine_sql_NotQuarantined_PriceAndCap + " ORDER BY ts DESC LIMIT 1;"
+	// точки окна и последняя точка до окна - для переноса цены на первые дни окна
+	price_and_cap_sql_MGetBetween = `SELECT currency_id, price, daily_volume, cap, ts FROM cmc.price_and_cap t WHERE currency_id = any($1) AND ts >= $2 AND ts <= $3 AND ` + data_quarantine_sql_NotQuarantined_PriceAndCap + `
+		UNION ALL
+		SELECT p.currency_id, p.price, p.daily_volume, p.cap, p.ts FROM unnest($1::bigint[]) c(id) CROSS JOIN LATERAL (
+			SELECT currency_id, price, daily_volume, cap, ts FROM cmc.price_and_cap t WHERE currency_id = c.id AND ts < $2 AND ` + data_quarantine_sql_NotQuarantined_PriceAndCap + ` ORDER BY ts DESC LIMIT 1
+		) p
+		ORDER BY ts DESC;`
+	price_and_cap_sql_MGetLast = `SELECT p.currency_id, p.price, p.daily_volume, p.cap, p.ts FROM unnest($1::bigint[]) c(id) CROSS JOIN LATERAL (
+			SELECT currency_id, price, daily_volume, cap, ts FROM cmc.price_and_cap t WHERE currency_id = c.id AND ` + data_quarantine_sql_NotQuarantined_PriceAndCap + ` ORDER BY ts DESC LIMIT $2
+		) p
+		ORDER BY ts DESC;`
 )
 
 func (r *PriceAndCapRepository) MGet(ctx context.Context, currencyIDs *[]uint) (price_and_cap.PriceAndCapMap, error) {
+	return r.mGet(ctx, "PriceAndCapRepository.MGet", price_and_cap_sql_MGet, *currencyIDs)
+}
+
+// MGetBetween returns the points of the currencies in [from, to] and the last point of every currency before from.
+func (r *PriceAndCapRepository) MGetBetween(ctx context.Context, currencyIDs *[]uint, from time.Time, to time.Time) (price_and_cap.PriceAndCapMap, error) {
+	return r.mGet(ctx, "PriceAndCapRepository.MGetBetween", price_and_cap_sql_MGetBetween, *currencyIDs, from, to)
+}
+
+// MGetLast returns the last limit points of every currency.
+func (r *PriceAndCapRepository) MGetLast(ctx context.Context, currencyIDs *[]uint, limit uint) (price_and_cap.PriceAndCapMap, error) {
+	return r.mGet(ctx, "PriceAndCapRepository.MGetLast", price_and_cap_sql_MGetLast, *currencyIDs, limit)
+}
+
+func (r *PriceAndCapRepository) mGet(ctx context.Context, metricName string, query string, args ...any) (price_and_cap.PriceAndCapMap, error) {
 	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	//defer cancel()
-	const metricName = "PriceAndCapRepository.MGet"
 
 	var entity price_and_cap.PriceAndCap
-	res := make(price_and_cap.PriceAndCapMap, len(*currencyIDs))
+	res := make(price_and_cap.PriceAndCapMap)
 
 	start := time.Now().UTC()
-	rows, err := r.db.Query(ctx, price_and_cap_sql_MGet, *currencyIDs)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
 			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
@@ -56,7 +84,7 @@ func (r *PriceAndCapRepository) MGet(ctx context.Context, currencyIDs *[]uint) (
 		}
 		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
 		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
-		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, price_and_cap_sql_MGet, err)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
 	}
 	defer rows.Close()
 
@@ -64,7 +92,7 @@ func (r *PriceAndCapRepository) MGet(ctx context.Context, currencyIDs *[]uint) (
 		if err = rows.Scan(&entity.CurrencyID, &entity.Price, &entity.DailyVolume, &entity.Cap, &entity.Ts); err != nil {
 			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
 			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
-			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, price_and_cap_sql_MGet, err)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
 		}
 		if _, ok := res[entity.CurrencyID]; !ok {
 			res[entity.CurrencyID] = make(price_and_cap.PriceAndCapList, 0, defaultCapacityForResult)
@@ -154,3 +182,79 @@ func (r PriceAndCapRepository) mUpsertTx(ctx context.Context, tx domain.Tx, enti
 	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
 	return nil
 }
+
+func (r *PriceAndCapRepository) GetCandles(ctx context.Context, params *price_and_cap.CandleParams) (*price_and_cap.CandleList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "PriceAndCapRepository.GetCandles"
+
+	var entity price_and_cap.Candle
+	res := make(price_and_cap.CandleList, 0, params.Limit)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, price_and_cap_sql_GetCandles, params.CurrencyID, params.BucketInterval(), params.From, params.To, params.Limit, params.Offset)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, price_and_cap_sql_GetCandles, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&entity.Ts, &entity.Open, &entity.High, &entity.Low, &entity.Close, &entity.Cap, &entity.Volume, &entity.PointsNb); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, price_and_cap_sql_GetCandles, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	return &res, nil
+}
+
+func (r *PriceAndCapRepository) CountCandles(ctx context.Context, params *price_and_cap.CandleParams) (uint, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "PriceAndCapRepository.CountCandles"
+	start := time.Now().UTC()
+
+	var res uint
+	if err := r.db.QueryRow(ctx, price_and_cap_sql_CountCandles, params.CurrencyID, params.BucketInterval(), params.From, params.To).Scan(&res); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return 0, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, price_and_cap_sql_CountCandles, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return res, nil
+}
+
+// GetLast returns the latest price and cap of the currency.
+func (r *PriceAndCapRepository) GetLast(ctx context.Context, currencyID uint) (*price_and_cap.PriceAndCap, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "PriceAndCapRepository.GetLast"
+	start := time.Now().UTC()
+
+	entity := &price_and_cap.PriceAndCap{}
+	if err := r.db.QueryRow(ctx, price_and_cap_sql_GetLast, currencyID).Scan(&entity.CurrencyID, &entity.Price, &entity.DailyVolume, &entity.Cap, &entity.Ts); err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, price_and_cap_sql_GetLast, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return entity, nil
+}
@@ -3,6 +3,7 @@ package tsdb
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"info/internal/domain"
 	"time"
@@ -49,6 +50,12 @@ type Tx interface {
 
 type Txs map[byte]Tx
 
+// execer is the pool or the transaction
+type execer interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (commandTag pgconn.CommandTag, err error)
+}
+
 var _ Tx = (pgx.Tx)(nil)
 
 type Repository struct {
@@ -208,3 +215,59 @@ func (r *Repository) ExecTx(ctx context.Context, tx Tx, sql string, arguments ..
 	}
 	return nil
 }
+
+// queryRow runs the query of the single row and scans it by the scan; it returns ErrNotFound if there is no row.
+func queryRow[T any](ctx context.Context, r *Repository, metricName string, query string, scan func(row pgx.Row, entity *T) error, args ...any) (*T, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	start := time.Now().UTC()
+
+	entity := new(T)
+	if err := scan(r.db.QueryRow(ctx, query, args...), entity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return entity, nil
+}
+
+// queryList runs the query and scans every row by the scan; the list is empty if there are no rows.
+func queryList[T any](ctx context.Context, r *Repository, metricName string, query string, scan func(row pgx.Row, entity *T) error, args ...any) ([]T, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	start := time.Now().UTC()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
+	}
+	defer rows.Close()
+
+	var entity T
+	res := make([]T, 0, defaultCapacityForResult)
+	for rows.Next() {
+		if err = scan(rows, &entity); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
+		}
+		res = append(res, entity)
+	}
+	if err = rows.Err(); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return res, nil
+}
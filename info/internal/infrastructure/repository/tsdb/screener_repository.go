@@ -0,0 +1,167 @@
+package tsdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+
+	"info/internal/pkg/apperror"
+
+	"info/internal/domain/screener"
+)
+
+type ScreenerRepository struct {
+	*Repository
+}
+
+var _ screener.WriteRepository = (*ScreenerRepository)(nil)
+var _ screener.ReadRepository = (*ScreenerRepository)(nil)
+
+func NewScreenerRepository(repository *Repository) *ScreenerRepository {
+	return &ScreenerRepository{
+		Repository: repository,
+	}
+}
+
+const (
+	screener_preset_sql_fields    = "id, name, normalization, criteria, rank_limit, created_at, updated_at"
+	screener_preset_sql_Get       = "SELECT " + screener_preset_sql_fields + " FROM cmc.screener_preset WHERE id = $1;"
+	screener_preset_sql_GetByName = "SELECT " + screener_preset_sql_fields + " FROM cmc.screener_preset WHERE name = $1;"
+	screener_preset_sql_GetAll    = "SELECT " + screener_preset_sql_fields + " FROM cmc.screener_preset ORDER BY id;"
+	screener_preset_sql_Create    = "INSERT INTO cmc.screener_preset(name, normalization, criteria, rank_limit, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;"
+	screener_preset_sql_Update    = "UPDATE cmc.screener_preset SET name = $2, normalization = $3, criteria = $4, rank_limit = $5, updated_at = $6 WHERE id = $1;"
+	screener_preset_sql_Delete    = "DELETE FROM cmc.screener_preset WHERE id = $1;"
+)
+
+func (r *ScreenerRepository) GetPreset(ctx context.Context, ID uint) (*screener.Preset, error) {
+	return r.getPreset(ctx, "ScreenerRepository.GetPreset", screener_preset_sql_Get, ID)
+}
+
+func (r *ScreenerRepository) GetPresetByName(ctx context.Context, name string) (*screener.Preset, error) {
+	return r.getPreset(ctx, "ScreenerRepository.GetPresetByName", screener_preset_sql_GetByName, name)
+}
+
+func (r *ScreenerRepository) getPreset(ctx context.Context, metricName string, query string, arg interface{}) (*screener.Preset, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	entity := &screener.Preset{}
+	start := time.Now().UTC()
+
+	err := r.db.QueryRow(ctx, query, arg).Scan(&entity.ID, &entity.Name, &entity.Normalization, &entity.Criteria, &entity.Limit, &entity.CreatedAt, &entity.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, query, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return entity, nil
+}
+
+func (r *ScreenerRepository) GetPresets(ctx context.Context) (*screener.PresetList, error) {
+	//ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	//defer cancel()
+	const metricName = "ScreenerRepository.GetPresets"
+
+	var entity screener.Preset
+	res := make(screener.PresetList, 0, defaultCapacityForResult)
+
+	start := time.Now().UTC()
+	rows, err := r.db.Query(ctx, screener_preset_sql_GetAll)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+			return nil, apperror.ErrNotFound
+		}
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, screener_preset_sql_GetAll, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity = screener.Preset{}
+		if err = rows.Scan(&entity.ID, &entity.Name, &entity.Normalization, &entity.Criteria, &entity.Limit, &entity.CreatedAt, &entity.UpdatedAt); err != nil {
+			r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+			r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+			return nil, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, screener_preset_sql_GetAll, err)
+		}
+		res = append(res, entity)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if len(res) == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &res, nil
+}
+
+func (r *ScreenerRepository) CreatePreset(ctx context.Context, entity *screener.Preset) (ID uint, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "ScreenerRepository.CreatePreset"
+	start := time.Now().UTC()
+
+	if err = r.db.QueryRow(ctx, screener_preset_sql_Create, entity.Name, entity.Normalization, entity.Criteria, entity.Limit, entity.CreatedAt, entity.UpdatedAt).Scan(&ID); err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return 0, fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, screener_preset_sql_Create, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+	return ID, nil
+}
+
+func (r *ScreenerRepository) UpdatePreset(ctx context.Context, entity *screener.Preset) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "ScreenerRepository.UpdatePreset"
+	start := time.Now().UTC()
+
+	tag, err := r.db.Exec(ctx, screener_preset_sql_Update, entity.ID, entity.Name, entity.Normalization, entity.Criteria, entity.Limit, entity.UpdatedAt)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, screener_preset_sql_Update, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ScreenerRepository) DeletePreset(ctx context.Context, ID uint) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	const metricName = "ScreenerRepository.DeletePreset"
+	start := time.Now().UTC()
+
+	tag, err := r.db.Exec(ctx, screener_preset_sql_Delete, ID)
+	if err != nil {
+		r.metrics.SqlMetrics.Inc(metricName, metricsFail)
+		r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsFail)
+		return fmt.Errorf("[%w] %s query error; query: %s; error: %w", apperror.ErrInternal, metricName, screener_preset_sql_Delete, err)
+	}
+	r.metrics.SqlMetrics.Inc(metricName, metricsSuccess)
+	r.metrics.SqlMetrics.WriteTiming(start, metricName, metricsSuccess)
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
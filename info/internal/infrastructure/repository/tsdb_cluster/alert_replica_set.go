@@ -0,0 +1,26 @@
+package tsdb_cluster
+
+import (
+	"info/internal/domain/alert"
+	"info/internal/infrastructure/repository/tsdb"
+)
+
+type AlertReplicaSet struct {
+	*ReplicaSet
+}
+
+var _ alert.ReplicaSet = (*AlertReplicaSet)(nil)
+
+func NewAlertReplicaSet(replicaSet *ReplicaSet) *AlertReplicaSet {
+	return &AlertReplicaSet{
+		ReplicaSet: replicaSet,
+	}
+}
+
+func (c *AlertReplicaSet) WriteRepo() alert.WriteRepository {
+	return tsdb.NewAlertRepository(c.ReplicaSet.WriteRepo())
+}
+
+func (c *AlertReplicaSet) ReadRepo() alert.ReadRepository {
+	return tsdb.NewAlertRepository(c.ReplicaSet.ReadRepo())
+}
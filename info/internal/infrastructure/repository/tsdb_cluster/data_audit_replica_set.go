@@ -0,0 +1,26 @@
+package tsdb_cluster
+
+import (
+	"info/internal/domain/data_audit"
+	"info/internal/infrastructure/repository/tsdb"
+)
+
+type DataAuditReplicaSet struct {
+	*ReplicaSet
+}
+
+var _ data_audit.ReplicaSet = (*DataAuditReplicaSet)(nil)
+
+func NewDataAuditReplicaSet(replicaSet *ReplicaSet) *DataAuditReplicaSet {
+	return &DataAuditReplicaSet{
+		ReplicaSet: replicaSet,
+	}
+}
+
+func (c *DataAuditReplicaSet) WriteRepo() data_audit.WriteRepository {
+	return tsdb.NewDataAuditRepository(c.ReplicaSet.WriteRepo())
+}
+
+func (c *DataAuditReplicaSet) ReadRepo() data_audit.ReadRepository {
+	return tsdb.NewDataAuditRepository(c.ReplicaSet.ReadRepo())
+}
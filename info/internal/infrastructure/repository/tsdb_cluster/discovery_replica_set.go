@@ -0,0 +1,26 @@
+package tsdb_cluster
+
+import (
+	"info/internal/domain/discovery"
+	"info/internal/infrastructure/repository/tsdb"
+)
+
+type DiscoveryReplicaSet struct {
+	*ReplicaSet
+}
+
+var _ discovery.ReplicaSet = (*DiscoveryReplicaSet)(nil)
+
+func NewDiscoveryReplicaSet(replicaSet *ReplicaSet) *DiscoveryReplicaSet {
+	return &DiscoveryReplicaSet{
+		ReplicaSet: replicaSet,
+	}
+}
+
+func (c *DiscoveryReplicaSet) WriteRepo() discovery.WriteRepository {
+	return tsdb.NewDiscoveryRepository(c.ReplicaSet.WriteRepo())
+}
+
+func (c *DiscoveryReplicaSet) ReadRepo() discovery.ReadRepository {
+	return tsdb.NewDiscoveryRepository(c.ReplicaSet.ReadRepo())
+}
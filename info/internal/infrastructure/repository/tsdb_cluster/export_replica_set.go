@@ -0,0 +1,22 @@
+package tsdb_cluster
+
+import (
+	"info/internal/domain/export"
+	"info/internal/infrastructure/repository/tsdb"
+)
+
+type ExportReplicaSet struct {
+	*ReplicaSet
+}
+
+var _ export.ReplicaSet = (*ExportReplicaSet)(nil)
+
+func NewExportReplicaSet(replicaSet *ReplicaSet) *ExportReplicaSet {
+	return &ExportReplicaSet{
+		ReplicaSet: replicaSet,
+	}
+}
+
+func (c *ExportReplicaSet) ReadRepo() export.ReadRepository {
+	return tsdb.NewExportRepository(c.ReplicaSet.ReadRepo())
+}
@@ -0,0 +1,26 @@
+package tsdb_cluster
+
+import (
+	"info/internal/domain/import_run"
+	"info/internal/infrastructure/repository/tsdb"
+)
+
+type ImportRunReplicaSet struct {
+	*ReplicaSet
+}
+
+var _ import_run.ReplicaSet = (*ImportRunReplicaSet)(nil)
+
+func NewImportRunReplicaSet(replicaSet *ReplicaSet) *ImportRunReplicaSet {
+	return &ImportRunReplicaSet{
+		ReplicaSet: replicaSet,
+	}
+}
+
+func (c *ImportRunReplicaSet) WriteRepo() import_run.WriteRepository {
+	return tsdb.NewImportRunRepository(c.ReplicaSet.WriteRepo())
+}
+
+func (c *ImportRunReplicaSet) ReadRepo() import_run.ReadRepository {
+	return tsdb.NewImportRunRepository(c.ReplicaSet.ReadRepo())
+}
@@ -0,0 +1,26 @@
+package tsdb_cluster
+
+import (
+	"info/internal/domain/screener"
+	"info/internal/infrastructure/repository/tsdb"
+)
+
+type ScreenerReplicaSet struct {
+	*ReplicaSet
+}
+
+var _ screener.ReplicaSet = (*ScreenerReplicaSet)(nil)
+
+func NewScreenerReplicaSet(replicaSet *ReplicaSet) *ScreenerReplicaSet {
+	return &ScreenerReplicaSet{
+		ReplicaSet: replicaSet,
+	}
+}
+
+func (c *ScreenerReplicaSet) WriteRepo() screener.WriteRepository {
+	return tsdb.NewScreenerRepository(c.ReplicaSet.WriteRepo())
+}
+
+func (c *ScreenerReplicaSet) ReadRepo() screener.ReadRepository {
+	return tsdb.NewScreenerRepository(c.ReplicaSet.ReadRepo())
+}
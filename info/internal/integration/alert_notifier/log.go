@@ -0,0 +1,30 @@
+package alert_notifier
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"info/internal/domain/alert"
+	"info/internal/pkg/log_key"
+)
+
+// LogNotifier writes the alerts to the log.
+type LogNotifier struct {
+	logger *zap.Logger
+}
+
+var _ alert.Notifier = (*LogNotifier)(nil)
+
+func NewLogNotifier(logger *zap.Logger) *LogNotifier {
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+func (n *LogNotifier) Name() string {
+	return alert.Notifier_Log
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, item *alert.Alert) error {
+	n.logger.Info("alert: "+item.Message, zap.Uint(log_key.AlertRule, item.RuleID), zap.String(log_key.AlertKind, item.Kind), zap.Uint(log_key.Currency, item.CurrencyID), zap.Float64(log_key.Value, item.Value))
+	return nil
+}
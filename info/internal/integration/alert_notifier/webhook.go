@@ -0,0 +1,111 @@
+package alert_notifier
+
+import (
+	"context"
+	"fmt"
+	"github.com/minipkg/httpclient"
+	prometheus_utils "github.com/minipkg/prometheus-utils"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap"
+	"info/internal/domain/alert"
+	"info/internal/integration/resilience"
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/log_key"
+	"strconv"
+	"time"
+)
+
+type httpClient interface {
+	Post(ctx context.Context, path string, reqObj interface{}, opts ...httpclient.RequestOption) ([]byte, int, error)
+}
+
+type AppConfig struct {
+	NameSpace string
+	Subsystem string
+	Service   string
+}
+
+// WebhookConfig is the endpoint which the alerts are posted to as JSON: Httpconfig.Host is the scheme with the host (e.g. http://localhost:8081), Path is the rest of the URL.
+type WebhookConfig struct {
+	Name       string // the name of the notifier in the rules, "webhook" by default
+	Httpconfig httpclient.Config
+	Path       string
+	Headers    map[string]string // e.g. the authorization
+	Resilience *resilience.Config
+}
+
+type WebhookNotifier struct {
+	config     *WebhookConfig
+	httpClient httpClient
+	logger     *zap.Logger
+}
+
+const (
+	WebhookName           = "WebhookNotifier"
+	ContentType           = "application/json; charset=utf-8"
+	HeaderParam_RequestId = "X-Request-Id"
+)
+
+var _ alert.Notifier = (*WebhookNotifier)(nil)
+
+// webhookRequest is the body of the webhook request
+type webhookRequest struct {
+	ID         uint      `json:"id"`
+	RuleID     uint      `json:"rule_id"`
+	RuleName   string    `json:"rule_name"`
+	Kind       string    `json:"kind"`
+	CurrencyID uint      `json:"currency_id"`
+	Symbol     string    `json:"symbol"`
+	Value      float64   `json:"value"`
+	Message    string    `json:"message"`
+	FiredAt    time.Time `json:"fired_at"`
+}
+
+func NewWebhookNotifier(appConfig *AppConfig, conf *WebhookConfig, resilienceMetrics *resilience.Metrics, logger *zap.Logger) *WebhookNotifier {
+	client := httpclient.New(conf.Httpconfig, prometheus_utils.NewHttpClientMetrics(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, conf.Httpconfig.Name))
+	return &WebhookNotifier{
+		config:     conf,
+		httpClient: resilience.NewClient(conf.Httpconfig.Name, client, conf.Resilience, resilienceMetrics, logger),
+		logger:     logger,
+	}
+}
+
+func (n *WebhookNotifier) Name() string {
+	if n.config.Name == "" {
+		return alert.Notifier_Webhook
+	}
+	return n.config.Name
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, item *alert.Alert) error {
+	const funcName = "Notify"
+	requestId := uuid.NewV4().String()
+	options := []httpclient.RequestOption{
+		httpclient.WithContentType(ContentType),
+		httpclient.WithHeader(HeaderParam_RequestId, requestId),
+	}
+	for key, value := range n.config.Headers {
+		options = append(options, httpclient.WithHeader(key, value))
+	}
+
+	data, code, err := n.httpClient.Post(ctx, n.config.Path, webhookRequest{
+		ID:         item.ID,
+		RuleID:     item.RuleID,
+		RuleName:   item.RuleName,
+		Kind:       item.Kind,
+		CurrencyID: item.CurrencyID,
+		Symbol:     item.Symbol,
+		Value:      item.Value,
+		Message:    item.Message,
+		FiredAt:    item.FiredAt,
+	}, options...)
+	if err != nil {
+		n.logger.Error("httpClient.Post error", zap.String(log_key.ApiClient, WebhookName), zap.String(log_key.Func, funcName), zap.Error(err))
+		return fmt.Errorf(WebhookName+"."+funcName+" [%w] http error: %s; requestId: %s; path: %s", apperror.ErrInternal, err.Error(), requestId, n.config.Path)
+	}
+	if code < 200 || code >= 300 {
+		n.logger.Error("httpClient.Post error", zap.String(log_key.ApiClient, WebhookName), zap.String(log_key.Func, funcName), zap.Int(log_key.Code, code))
+		return fmt.Errorf(funcName+" [%w] http response error code: "+strconv.Itoa(code)+"; requestId: %s; path: %s; response: %s", apperror.ErrInternal, requestId, n.config.Path, string(data))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package alert_notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minipkg/httpclient"
+	"go.uber.org/zap"
+
+	"info/internal/domain/alert"
+)
+
+type fakeHttpMetrics struct{}
+
+func (m *fakeHttpMetrics) Inc(method, code, path string)                          {}
+func (m *fakeHttpMetrics) WriteTiming(start time.Time, method, code, path string) {}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	firedAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	item := &alert.Alert{
+		ID:         42,
+		RuleID:     7,
+		RuleName:   "worm",
+		Kind:       alert.Kind_WormIndexAbove,
+		CurrencyID: 1,
+		Symbol:     "BTC",
+		Value:      0.75,
+		Message:    "BTC: the worm index is above 0.5",
+		FiredAt:    firedAt,
+	}
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "delivered", status: http.StatusOK},
+		{name: "accepted", status: http.StatusAccepted},
+		{name: "rejected", status: http.StatusBadRequest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got webhookRequest
+			var gotHeader http.Header
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotHeader = r.Header.Clone()
+				body, _ := io.ReadAll(r.Body)
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Errorf("body %s unmarshal error: %v", body, err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			n := &WebhookNotifier{
+				config: &WebhookConfig{
+					Path:    "/hooks/alert",
+					Headers: map[string]string{"Authorization": "Bearer secret"},
+				},
+				httpClient: httpclient.New(httpclient.Config{Host: server.URL, Timeout: time.Second}, &fakeHttpMetrics{}),
+				logger:     zap.NewNop(),
+			}
+
+			err := n.Notify(context.Background(), item)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, want error %v", err, tt.wantErr)
+			}
+			want := webhookRequest{
+				ID:         item.ID,
+				RuleID:     item.RuleID,
+				RuleName:   item.RuleName,
+				Kind:       item.Kind,
+				CurrencyID: item.CurrencyID,
+				Symbol:     item.Symbol,
+				Value:      item.Value,
+				Message:    item.Message,
+				FiredAt:    item.FiredAt,
+			}
+			if got != want {
+				t.Fatalf("payload = %+v, want %+v", got, want)
+			}
+			if gotPath != "/hooks/alert" {
+				t.Fatalf("path = %q, want %q", gotPath, "/hooks/alert")
+			}
+			if gotHeader.Get("Content-Type") != ContentType || gotHeader.Get(HeaderParam_RequestId) == "" || gotHeader.Get("Authorization") != "Bearer secret" {
+				t.Fatalf("headers = %v, want the content type, the request ID and the configured headers", gotHeader)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_Name(t *testing.T) {
+	if name := (&WebhookNotifier{config: &WebhookConfig{}}).Name(); name != alert.Notifier_Webhook {
+		t.Fatalf("Name() = %q, want %q", name, alert.Notifier_Webhook)
+	}
+	if name := (&WebhookNotifier{config: &WebhookConfig{Name: "slack"}}).Name(); name != "slack" {
+		t.Fatalf("Name() = %q, want %q", name, "slack")
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"info/internal/domain/currency"
 	"info/internal/domain/portfolio_item"
 	"info/internal/domain/price_and_cap"
+	"info/internal/integration/ratelimit"
+	"info/internal/integration/resilience"
 	"info/internal/pkg/apperror"
 	"info/internal/pkg/log_key"
 	"strconv"
@@ -30,8 +32,11 @@ type AppConfig struct {
 }
 
 type Config struct {
-	Httpconfig httpclient.Config
-	Cookie     string
+	Httpconfig  httpclient.Config
+	RateLimit   *ratelimit.Config
+	Resilience  *resilience.Config
+	Concurrency uint // количество одновременных запросов при импорте
+	Cookie      string
 }
 
 type CmcApiClient struct {
@@ -100,15 +105,23 @@ func ChartRange2AnalyticsRange(s string) (string, error) {
 	return res, nil
 }
 
-func New(appConfig *AppConfig, conf *Config, logger *zap.Logger) *CmcApiClient {
+func New(appConfig *AppConfig, conf *Config, limiter *ratelimit.Limiter, resilienceMetrics *resilience.Metrics, logger *zap.Logger) *CmcApiClient {
 	client := httpclient.New(conf.Httpconfig, prometheus_utils.NewHttpClientMetrics(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, conf.Httpconfig.Name).SetCuttingPathOpts(&prometheus_utils.CuttingPathOpts{IsNeedToRemoveQueryInPath: true}))
 	return &CmcApiClient{
 		config:     conf,
-		httpClient: client,
+		httpClient: resilience.NewClient(conf.Httpconfig.Name, ratelimit.NewClient(client, limiter), conf.Resilience, resilienceMetrics, logger),
 		logger:     logger,
 	}
 }
 
+// Concurrency returns the number of the import workers allowed for the API.
+func (c *CmcApiClient) Concurrency() uint {
+	if c == nil || c.config.Concurrency == 0 {
+		return 1
+	}
+	return c.config.Concurrency
+}
+
 func (c *CmcApiClient) getDefaultRequestOptions() (requestId string, options []httpclient.RequestOption) {
 	requestId = uuid.NewV4().String()
 	return requestId, []httpclient.RequestOption{
@@ -9,6 +9,9 @@ import (
 	uuid "github.com/satori/go.uuid"
 	"go.uber.org/zap"
 	"info/internal/domain/currency"
+	"info/internal/domain/discovery"
+	"info/internal/integration/ratelimit"
+	"info/internal/integration/resilience"
 	"info/internal/pkg/apperror"
 	"info/internal/pkg/fasthttp_tools"
 	"info/internal/pkg/log_key"
@@ -28,8 +31,11 @@ type AppConfig struct {
 }
 
 type Config struct {
-	Httpconfig httpclient.Config
-	Token      string
+	Httpconfig  httpclient.Config
+	RateLimit   *ratelimit.Config
+	Resilience  *resilience.Config
+	Concurrency uint // количество одновременных запросов при импорте
+	Token       string
 }
 
 type CmcApiClient struct {
@@ -47,17 +53,27 @@ const (
 	ErrorMessage_Success = "SUCCESS"
 
 	URI_GetCurrencies string = "/v2/cryptocurrency/quotes/latest"
+	URI_GetListings   string = "/v1/cryptocurrency/listings/latest"
+	URI_GetInfo       string = "/v2/cryptocurrency/info"
 )
 
-func New(appConfig *AppConfig, conf *Config, logger *zap.Logger) *CmcApiClient {
+func New(appConfig *AppConfig, conf *Config, limiter *ratelimit.Limiter, resilienceMetrics *resilience.Metrics, logger *zap.Logger) *CmcApiClient {
 	client := httpclient.New(conf.Httpconfig, prometheus_utils.NewHttpClientMetrics(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, conf.Httpconfig.Name).SetCuttingPathOpts(&prometheus_utils.CuttingPathOpts{IsNeedToRemoveQueryInPath: true}))
 	return &CmcApiClient{
 		config:     conf,
-		httpClient: client,
+		httpClient: resilience.NewClient(conf.Httpconfig.Name, ratelimit.NewClient(client, limiter), conf.Resilience, resilienceMetrics, logger),
 		logger:     logger,
 	}
 }
 
+// Concurrency returns the number of the import workers allowed for the API.
+func (c *CmcApiClient) Concurrency() uint {
+	if c == nil || c.config.Concurrency == 0 {
+		return 1
+	}
+	return c.config.Concurrency
+}
+
 func (c *CmcApiClient) getDefaultRequestOptions() (requestId string, options []httpclient.RequestOption) {
 	requestId = uuid.NewV4().String()
 	return requestId, []httpclient.RequestOption{
@@ -121,3 +137,69 @@ func (c *CmcApiClient) getCurrencies(ctx context.Context, params string) (curren
 
 	return currencyMap, nil
 }
+
+// GetListings returns the first limit coins of the listings sorted by the market cap.
+func (c *CmcApiClient) GetListings(ctx context.Context, limit uint) (*discovery.ListingList, error) {
+	const funcName = "GetListings"
+	resp := &ListingsResponse{}
+	requestId, options := c.getRequestOptions()
+
+	uri := URI_GetListings + "?start=1&sort=market_cap&convert=USD&limit=" + strconv.FormatUint(uint64(limit), 10)
+
+	data, code, err := c.httpClient.Get(ctx, uri, options...)
+	if err != nil {
+		c.logger.Error("httpClient.Get error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Error(err))
+		return nil, fmt.Errorf(Name+"."+funcName+" [%w] http error: %s; requestId: %s; uri: %s", apperror.ErrInternal, err.Error(), requestId, uri)
+	}
+	if code != 200 {
+		c.logger.Error("httpClient.Get error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Error(err), zap.Int(log_key.Code, code))
+		return nil, fmt.Errorf(funcName+" [%w] http response error code: "+strconv.Itoa(code)+"; requestId: %s; uri: %s; response: %s", apperror.ErrInternal, requestId, uri, string(data))
+	}
+
+	if err = json.Unmarshal(data, resp); err != nil {
+		c.logger.Error("json.Unmarshal error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Error(err))
+		return nil, fmt.Errorf(funcName+" [%w] json.Unmarshal error: %s; requestId: %s; uri: %s; response: %s", apperror.ErrInternal, err.Error(), requestId, uri, string(data))
+	}
+
+	if resp.Status.ErrorCode != 0 || (resp.Status.ErrorMessage != ErrorMessage_Success && resp.Status.ErrorMessage != "") {
+		c.logger.Error("response with error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Int(log_key.ErrorCode, resp.Status.ErrorCode), zap.String(log_key.ErrorMessage, resp.Status.ErrorMessage))
+		return nil, fmt.Errorf(funcName+" [%w] response with error; code: %d; error message: "+resp.Status.ErrorMessage+"; requestId: %s; uri: %s; response: %s", apperror.ErrInternal, resp.Status.ErrorCode, requestId, uri, string(data))
+	}
+
+	return resp.ListingList(), nil
+}
+
+// GetTokenAddresses returns the addresses of the tokens of the currencies on all the chains they live on.
+func (c *CmcApiClient) GetTokenAddresses(ctx context.Context, currencyIDs *[]uint) (*currency.TokenAddressList, error) {
+	if currencyIDs == nil || len(*currencyIDs) == 0 {
+		return &currency.TokenAddressList{}, nil
+	}
+
+	const funcName = "GetTokenAddresses"
+	resp := &CurrencyInfoResponse{}
+	requestId, options := c.getRequestOptions()
+
+	uri := URI_GetInfo + "?aux=platform&id=" + fasthttp_tools.Uints2Str(currencyIDs, nil)
+
+	data, code, err := c.httpClient.Get(ctx, uri, options...)
+	if err != nil {
+		c.logger.Error("httpClient.Get error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Error(err))
+		return nil, fmt.Errorf(Name+"."+funcName+" [%w] http error: %s; requestId: %s; uri: %s", apperror.ErrInternal, err.Error(), requestId, uri)
+	}
+	if code != 200 {
+		c.logger.Error("httpClient.Get error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Error(err), zap.Int(log_key.Code, code))
+		return nil, fmt.Errorf(funcName+" [%w] http response error code: "+strconv.Itoa(code)+"; requestId: %s; uri: %s; response: %s", apperror.ErrInternal, requestId, uri, string(data))
+	}
+
+	if err = json.Unmarshal(data, resp); err != nil {
+		c.logger.Error("json.Unmarshal error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Error(err))
+		return nil, fmt.Errorf(funcName+" [%w] json.Unmarshal error: %s; requestId: %s; uri: %s; response: %s", apperror.ErrInternal, err.Error(), requestId, uri, string(data))
+	}
+
+	if resp.Status.ErrorCode != 0 || (resp.Status.ErrorMessage != ErrorMessage_Success && resp.Status.ErrorMessage != "") {
+		c.logger.Error("response with error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Int(log_key.ErrorCode, resp.Status.ErrorCode), zap.String(log_key.ErrorMessage, resp.Status.ErrorMessage))
+		return nil, fmt.Errorf(funcName+" [%w] response with error; code: %d; error message: "+resp.Status.ErrorMessage+"; requestId: %s; uri: %s; response: %s", apperror.ErrInternal, resp.Status.ErrorCode, requestId, uri, string(data))
+	}
+
+	return resp.TokenAddressList(), nil
+}
@@ -3,6 +3,7 @@ package cmc_pro_api
 import (
 	"info/internal/domain/concentration"
 	"info/internal/domain/currency"
+	"info/internal/domain/discovery"
 	"info/internal/domain/price_and_cap"
 	"info/internal/pkg/apperror"
 	"strconv"
@@ -223,7 +224,33 @@ type Quote struct {
 	USD QuoteUSD
 }
 type QuoteUSD struct {
-	Price float64
+	Price     float64
+	Volume24h float64 `json:"volume_24h"`
+	MarketCap float64 `json:"market_cap"`
+}
+
+type ListingsResponse struct {
+	Data   []ListingItem `json:"data"`
+	Status Status        `json:"status"`
+}
+
+type ListingItem struct {
+	CurrencyQuote
+	Tags []string `json:"tags"`
+}
+
+func (e *ListingsResponse) ListingList() *discovery.ListingList {
+	res := make(discovery.ListingList, 0, len(e.Data))
+	var item ListingItem
+	for _, item = range e.Data {
+		res = append(res, discovery.Listing{
+			Currency:  *item.Currency(),
+			MarketCap: item.Quote.USD.MarketCap,
+			Volume24h: item.Quote.USD.Volume24h,
+			Tags:      item.Tags,
+		})
+	}
+	return &res
 }
 
 func (e *CurrencyQuote) Currency() *currency.Currency {
@@ -243,3 +270,49 @@ func (e *CurrencyQuote) Currency() *currency.Currency {
 		Platform:                      e.Platform.CurrencyPlatform(),
 	}
 }
+
+type CurrencyInfoResponse struct {
+	Data   map[string]CurrencyInfo `json:"data"`
+	Status Status                  `json:"status"`
+}
+
+type CurrencyInfo struct {
+	ID              uint              `json:"id"`
+	ContractAddress []ContractAddress `json:"contract_address"`
+}
+
+type ContractAddress struct {
+	ContractAddress string           `json:"contract_address"`
+	Platform        ContractPlatform `json:"platform"`
+}
+
+type ContractPlatform struct {
+	Name string               `json:"name"`
+	Coin ContractPlatformCoin `json:"coin"`
+}
+
+type ContractPlatformCoin struct {
+	Symbol string `json:"symbol"`
+	Slug   string `json:"slug"`
+	Name   string `json:"name"`
+}
+
+// TokenAddressList returns the addresses of the tokens on all the chains; the chain is the symbol of the coin of the platform (ETH, BNB, ...).
+func (e *CurrencyInfoResponse) TokenAddressList() *currency.TokenAddressList {
+	res := make(currency.TokenAddressList, 0, len(e.Data))
+	var info CurrencyInfo
+	var item ContractAddress
+	for _, info = range e.Data {
+		for _, item = range info.ContractAddress {
+			if item.ContractAddress == "" || item.Platform.Coin.Symbol == "" {
+				continue
+			}
+			res = append(res, currency.TokenAddress{
+				CurrencyID: info.ID,
+				Blockchain: item.Platform.Coin.Symbol,
+				Address:    item.ContractAddress,
+			})
+		}
+	}
+	return &res
+}
@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"info/internal/integration/alert_notifier"
 	"info/internal/integration/cmc_api"
 	"info/internal/integration/cmc_pro_api"
 	"info/internal/integration/oracul_analytics_api"
@@ -10,6 +11,7 @@ type Config struct {
 	CmcAPI             *cmc_api.Config
 	CmcProAPI          *cmc_pro_api.Config
 	OraculAnalyticsAPI *oracul_analytics_api.Config
+	AlertWebhook       *alert_notifier.WebhookConfig
 }
 
 type UsageConfig struct {
@@ -3,9 +3,13 @@ package integration
 import (
 	"errors"
 	"go.uber.org/zap"
+	"info/internal/domain/alert"
+	"info/internal/integration/alert_notifier"
 	"info/internal/integration/cmc_api"
 	"info/internal/integration/cmc_pro_api"
 	"info/internal/integration/oracul_analytics_api"
+	"info/internal/integration/ratelimit"
+	"info/internal/integration/resilience"
 )
 
 type AppConfig struct {
@@ -19,17 +23,28 @@ type Integration struct {
 	CmcAPI             *cmc_api.CmcApiClient
 	CmcProAPI          *cmc_pro_api.CmcApiClient
 	OraculAnalyticsAPI *oracul_analytics_api.OraculAnalyticsAPIClient
+	AlertNotifiers     []alert.Notifier
 }
 
 func New(appConfig *AppConfig, cfg *Config, logger *zap.Logger) (*Integration, error) {
 	integration := &Integration{}
+	limiters := ratelimit.NewRegistry(&ratelimit.AppConfig{
+		NameSpace: appConfig.NameSpace,
+		Subsystem: appConfig.Subsystem,
+		Service:   appConfig.Service,
+	})
+	resilienceMetrics := resilience.NewMetrics(&resilience.AppConfig{
+		NameSpace: appConfig.NameSpace,
+		Subsystem: appConfig.Subsystem,
+		Service:   appConfig.Service,
+	})
 
 	if cfg.CmcAPI != nil {
 		integration.CmcAPI = cmc_api.New(&cmc_api.AppConfig{
 			NameSpace: appConfig.NameSpace,
 			Subsystem: appConfig.Subsystem,
 			Service:   appConfig.Service,
-		}, cfg.CmcAPI, logger)
+		}, cfg.CmcAPI, limiters.Limiter(cfg.CmcAPI.Httpconfig.Host, cfg.CmcAPI.RateLimit), resilienceMetrics, logger)
 	}
 
 	if cfg.CmcProAPI != nil {
@@ -37,7 +52,7 @@ func New(appConfig *AppConfig, cfg *Config, logger *zap.Logger) (*Integration, e
 			NameSpace: appConfig.NameSpace,
 			Subsystem: appConfig.Subsystem,
 			Service:   appConfig.Service,
-		}, cfg.CmcProAPI, logger)
+		}, cfg.CmcProAPI, limiters.Limiter(cfg.CmcProAPI.Httpconfig.Host, cfg.CmcProAPI.RateLimit), resilienceMetrics, logger)
 	}
 
 	if cfg.OraculAnalyticsAPI != nil {
@@ -45,7 +60,16 @@ func New(appConfig *AppConfig, cfg *Config, logger *zap.Logger) (*Integration, e
 			NameSpace: appConfig.NameSpace,
 			Subsystem: appConfig.Subsystem,
 			Service:   appConfig.Service,
-		}, cfg.OraculAnalyticsAPI, logger)
+		}, cfg.OraculAnalyticsAPI, limiters.Limiter(cfg.OraculAnalyticsAPI.Httpconfig.Host, cfg.OraculAnalyticsAPI.RateLimit), resilienceMetrics, logger)
+	}
+
+	integration.AlertNotifiers = []alert.Notifier{alert_notifier.NewLogNotifier(logger)}
+	if cfg.AlertWebhook != nil {
+		integration.AlertNotifiers = append(integration.AlertNotifiers, alert_notifier.NewWebhookNotifier(&alert_notifier.AppConfig{
+			NameSpace: appConfig.NameSpace,
+			Subsystem: appConfig.Subsystem,
+			Service:   appConfig.Service,
+		}, cfg.AlertWebhook, resilienceMetrics, logger))
 	}
 
 	return integration, nil
@@ -9,8 +9,11 @@ import (
 	uuid "github.com/satori/go.uuid"
 	"go.uber.org/zap"
 	"info/internal/domain/oracul_analytics"
+	"info/internal/integration/ratelimit"
+	"info/internal/integration/resilience"
 	"info/internal/pkg/apperror"
 	"info/internal/pkg/log_key"
+	"net/http"
 	"strconv"
 	"time"
 )
@@ -27,7 +30,10 @@ type AppConfig struct {
 }
 
 type Config struct {
-	Httpconfig httpclient.Config
+	Httpconfig  httpclient.Config
+	RateLimit   *ratelimit.Config
+	Resilience  *resilience.Config
+	Concurrency uint // количество одновременных запросов при импорте
 }
 
 type OraculAnalyticsAPIClient struct {
@@ -47,15 +53,23 @@ const (
 
 var _ oracul_analytics.OraculAnalyticsAPIClient = (*OraculAnalyticsAPIClient)(nil)
 
-func New(appConfig *AppConfig, conf *Config, logger *zap.Logger) *OraculAnalyticsAPIClient {
+func New(appConfig *AppConfig, conf *Config, limiter *ratelimit.Limiter, resilienceMetrics *resilience.Metrics, logger *zap.Logger) *OraculAnalyticsAPIClient {
 	client := httpclient.New(conf.Httpconfig, prometheus_utils.NewHttpClientMetrics(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, conf.Httpconfig.Name).SetCuttingPathOpts(&prometheus_utils.CuttingPathOpts{IsNeedToRemoveQueryInPath: true}))
 	return &OraculAnalyticsAPIClient{
 		config:     conf,
-		httpClient: client,
+		httpClient: resilience.NewClient(conf.Httpconfig.Name, ratelimit.NewClient(client, limiter), conf.Resilience, resilienceMetrics, logger),
 		logger:     logger,
 	}
 }
 
+// Concurrency returns the number of the import workers allowed for the API.
+func (c *OraculAnalyticsAPIClient) Concurrency() uint {
+	if c == nil || c.config.Concurrency == 0 {
+		return 1
+	}
+	return c.config.Concurrency
+}
+
 func (c *OraculAnalyticsAPIClient) getDefaultRequestOptions() (requestId string, options []httpclient.RequestOption) {
 	requestId = uuid.NewV4().String()
 	return requestId, []httpclient.RequestOption{
@@ -77,6 +91,9 @@ func (c *OraculAnalyticsAPIClient) GetHoldersStats(ctx context.Context, currency
 	uri := URI_GetHoldersStats + "?coin_address=" + coinAddress + "&blockchain=" + blockchain + "&start_at=" + ts.Add(-1*time.Hour*24*365).Format(time.DateOnly) + "&end_at=" + ts.Format(time.DateOnly) + "&total_candles=27"
 
 	data, code, err := c.httpClient.Get(ctx, uri, options...)
+	if code == http.StatusNotFound {
+		return nil, fmt.Errorf(Name+"."+funcName+" [%w] no data for the token; requestId: %s; uri: %s", apperror.ErrNotFound, requestId, uri)
+	}
 	if err != nil {
 		c.logger.Error("httpClient.Get error", zap.String(log_key.ApiClient, Name), zap.String(log_key.Func, funcName), zap.Error(err))
 		return nil, fmt.Errorf(Name+"."+funcName+" [%w] http error: %s; requestId: %s; uri: %s", apperror.ErrInternal, err.Error(), requestId, uri)
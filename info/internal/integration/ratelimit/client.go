@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/minipkg/httpclient"
+)
+
+type httpClient interface {
+	Get(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, int, error)
+	Post(ctx context.Context, path string, reqObj interface{}, opts ...httpclient.RequestOption) ([]byte, int, error)
+}
+
+// httpclient не отдаёт заголовки ответа, но при ошибке кладёт в неё весь ответ, вместе с заголовками
+var retryAfterRegexp = regexp.MustCompile(`(?i)Retry-After:\s*(\d+)`)
+
+// Client is a httpClient which waits for the limiter before every request.
+type Client struct {
+	client  httpClient
+	limiter *Limiter
+}
+
+func NewClient(client httpClient, limiter *Limiter) *Client {
+	return &Client{
+		client:  client,
+		limiter: limiter,
+	}
+}
+
+func (c *Client) Get(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, int, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	data, code, err := c.client.Get(ctx, path, opts...)
+	c.checkThrottling(code, err)
+	return data, code, err
+}
+
+func (c *Client) Post(ctx context.Context, path string, reqObj interface{}, opts ...httpclient.RequestOption) ([]byte, int, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, 0, err
+	}
+	data, code, err := c.client.Post(ctx, path, reqObj, opts...)
+	c.checkThrottling(code, err)
+	return data, code, err
+}
+
+func (c *Client) checkThrottling(code int, err error) {
+	if code != http.StatusTooManyRequests {
+		return
+	}
+	c.limiter.Throttle(RetryAfter(err))
+}
+
+// RetryAfter returns the value of the Retry-After header from the httpclient error or zero if there is no one.
+func RetryAfter(err error) time.Duration {
+	if err == nil {
+		return 0
+	}
+	m := retryAfterRegexp.FindStringSubmatch(err.Error())
+	if len(m) < 2 {
+		return 0
+	}
+	sec, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(sec) * time.Second
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPeriod        = time.Second
+	defaultThrottlePause = time.Minute
+)
+
+// Config describes a token bucket: Limit requests per Period with bursts up to Burst requests.
+// A Config with zero Limit means no limit.
+type Config struct {
+	Limit         uint
+	Period        time.Duration
+	Burst         uint
+	ThrottlePause time.Duration // пауза после ответа 429 без заголовка Retry-After
+}
+
+type metrics interface {
+	SetTokens(host string, tokens float64)
+	SetPause(host string, pause time.Duration)
+	IncEvent(host string, event string)
+}
+
+// Limiter is a token bucket limiter for a single upstream host.
+type Limiter struct {
+	host          string
+	metrics       metrics
+	mu            sync.Mutex
+	rate          float64 // токенов в секунду
+	burst         float64
+	tokens        float64
+	last          time.Time
+	pausedUntil   time.Time
+	throttlePause time.Duration
+}
+
+func newLimiter(host string, cfg *Config, metrics metrics) *Limiter {
+	l := &Limiter{
+		host:          host,
+		metrics:       metrics,
+		throttlePause: defaultThrottlePause,
+		last:          time.Now(),
+	}
+	if cfg == nil || cfg.Limit == 0 {
+		return l
+	}
+
+	period := cfg.Period
+	if period <= 0 {
+		period = defaultPeriod
+	}
+	burst := cfg.Burst
+	if burst == 0 {
+		burst = 1
+	}
+	if cfg.ThrottlePause > 0 {
+		l.throttlePause = cfg.ThrottlePause
+	}
+	l.rate = float64(cfg.Limit) / period.Seconds()
+	l.burst = float64(burst)
+	l.tokens = l.burst
+	l.metrics.SetTokens(l.host, l.tokens)
+	return l
+}
+
+func (l *Limiter) Host() string {
+	return l.host
+}
+
+// Wait blocks until a request is allowed or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	var wait time.Duration
+	isWaited := false
+
+	for {
+		if wait = l.reserve(); wait == 0 {
+			return nil
+		}
+		if !isWaited {
+			isWaited = true
+			l.metrics.IncEvent(l.host, event_Wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.metrics.IncEvent(l.host, event_Canceled)
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token and returns zero or returns the time to wait for the next try.
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate == 0 {
+		return 0
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.metrics.SetTokens(l.host, l.tokens)
+		return 0
+	}
+	l.metrics.SetTokens(l.host, l.tokens)
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Throttle stops all the requests to the host for retryAfter (or for the configured ThrottlePause if retryAfter is zero).
+func (l *Limiter) Throttle(retryAfter time.Duration) {
+	if l == nil {
+		return
+	}
+	if retryAfter <= 0 {
+		retryAfter = l.throttlePause
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if until := now.Add(retryAfter); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.tokens = 0
+	l.last = now
+	l.metrics.IncEvent(l.host, event_Throttled)
+	l.metrics.SetTokens(l.host, l.tokens)
+	l.metrics.SetPause(l.host, l.pausedUntil.Sub(now))
+}
@@ -0,0 +1,208 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/minipkg/httpclient"
+)
+
+type fakeMetrics struct {
+	events map[string]int
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{events: make(map[string]int)}
+}
+
+func (m *fakeMetrics) SetTokens(host string, tokens float64)     {}
+func (m *fakeMetrics) SetPause(host string, pause time.Duration) {}
+func (m *fakeMetrics) IncEvent(host string, event string)        { m.events[event]++ }
+
+func TestLimiter_reserve(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *Config
+		before    int           // the calls before the time passes
+		elapsed   time.Duration // the time passed after the before calls
+		after     int           // the calls after the time passes; all of them must not wait
+		wantZero  bool          // the next call must not wait
+		wantAbout time.Duration // the wait of the next call if it is not zero
+	}{
+		{name: "no config is unlimited", cfg: nil, after: 100, wantZero: true},
+		{name: "zero limit is unlimited", cfg: &Config{Limit: 0, Burst: 5}, after: 100, wantZero: true},
+		{name: "burst is available at once", cfg: &Config{Limit: 1, Period: time.Second, Burst: 3}, after: 2, wantZero: true},
+		{name: "over burst waits for the next token", cfg: &Config{Limit: 1, Period: time.Second, Burst: 3}, after: 3, wantAbout: time.Second},
+		{name: "zero burst is one", cfg: &Config{Limit: 2, Period: time.Second}, after: 1, wantAbout: 500 * time.Millisecond},
+		{name: "zero period is a second", cfg: &Config{Limit: 4, Burst: 1}, after: 1, wantAbout: 250 * time.Millisecond},
+		{name: "tokens are refilled with the time", cfg: &Config{Limit: 1, Period: time.Second, Burst: 1}, before: 1, elapsed: 2 * time.Second, wantZero: true},
+		{name: "refill is capped by burst", cfg: &Config{Limit: 10, Period: time.Second, Burst: 2}, before: 2, elapsed: time.Hour, after: 2, wantAbout: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter("host", tt.cfg, newFakeMetrics())
+			for i := 0; i < tt.before; i++ {
+				l.reserve()
+			}
+			l.last = l.last.Add(-tt.elapsed)
+			for i := 0; i < tt.after; i++ {
+				if wait := l.reserve(); wait != 0 {
+					t.Fatalf("call %d: wait = %v, want 0", i, wait)
+				}
+			}
+
+			wait := l.reserve()
+			if tt.wantZero {
+				if wait != 0 {
+					t.Fatalf("wait = %v, want 0", wait)
+				}
+				return
+			}
+			if wait > tt.wantAbout || wait < tt.wantAbout-50*time.Millisecond {
+				t.Fatalf("wait = %v, want about %v", wait, tt.wantAbout)
+			}
+		})
+	}
+}
+
+func TestLimiter_Throttle(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        *Config
+		retryAfter []time.Duration // the consecutive throttles
+		wantPause  time.Duration
+	}{
+		{name: "retry after is used", cfg: &Config{Limit: 10}, retryAfter: []time.Duration{5 * time.Second}, wantPause: 5 * time.Second},
+		{name: "zero retry after is the default pause", cfg: &Config{Limit: 10}, retryAfter: []time.Duration{0}, wantPause: defaultThrottlePause},
+		{name: "zero retry after is the configured pause", cfg: &Config{Limit: 10, ThrottlePause: 7 * time.Second}, retryAfter: []time.Duration{0}, wantPause: 7 * time.Second},
+		{name: "the shorter throttle does not shorten the pause", cfg: &Config{Limit: 10}, retryAfter: []time.Duration{30 * time.Second, time.Second}, wantPause: 30 * time.Second},
+		{name: "the unlimited host is paused too", cfg: nil, retryAfter: []time.Duration{3 * time.Second}, wantPause: 3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newFakeMetrics()
+			l := newLimiter("host", tt.cfg, m)
+			for _, d := range tt.retryAfter {
+				l.Throttle(d)
+			}
+			wait := l.reserve()
+			if wait > tt.wantPause || wait < tt.wantPause-time.Second {
+				t.Fatalf("wait = %v, want about %v", wait, tt.wantPause)
+			}
+			if m.events[event_Throttled] != len(tt.retryAfter) {
+				t.Fatalf("throttled events = %d, want %d", m.events[event_Throttled], len(tt.retryAfter))
+			}
+		})
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	m := newFakeMetrics()
+	l := newLimiter("host", &Config{Limit: 1, Period: time.Hour, Burst: 1}, m)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if m.events[event_Wait] != 1 || m.events[event_Canceled] != 1 {
+		t.Fatalf("events = %v, want one wait and one canceled", m.events)
+	}
+
+	var nilLimiter *Limiter
+	if err := nilLimiter.Wait(ctx); err != nil {
+		t.Fatalf("nil limiter Wait error: %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+	}{
+		{name: "no error", err: nil, want: 0},
+		{name: "no header", err: errors.New("status 429"), want: 0},
+		{name: "header", err: errors.New("HTTP/1.1 429 Too Many Requests\r\nRetry-After: 12\r\n"), want: 12 * time.Second},
+		{name: "header in any case", err: errors.New("retry-after:3"), want: 3 * time.Second},
+		{name: "http date is not supported", err: errors.New("Retry-After: Wed, 21 Oct 2015 07:28:00 GMT"), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RetryAfter(tt.err); got != tt.want {
+				t.Fatalf("RetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeHttpClient struct {
+	code int
+	err  error
+}
+
+func (c *fakeHttpClient) Get(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, int, error) {
+	return nil, c.code, c.err
+}
+
+func (c *fakeHttpClient) Post(ctx context.Context, path string, reqObj interface{}, opts ...httpclient.RequestOption) ([]byte, int, error) {
+	return nil, c.code, c.err
+}
+
+func TestClient_throttling(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       int
+		err        error
+		wantPaused bool
+	}{
+		{name: "ok", code: http.StatusOK, wantPaused: false},
+		{name: "server error", code: http.StatusInternalServerError, err: errors.New("Retry-After: 10"), wantPaused: false},
+		{name: "too many requests", code: http.StatusTooManyRequests, err: errors.New("Retry-After: 10"), wantPaused: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter("host", nil, newFakeMetrics())
+			c := NewClient(&fakeHttpClient{code: tt.code, err: tt.err}, l)
+			c.Get(context.Background(), "/")
+			if paused := l.reserve() > 0; paused != tt.wantPaused {
+				t.Fatalf("paused = %v, want %v", paused, tt.wantPaused)
+			}
+		})
+	}
+}
+
+type fakeGauge struct{}
+
+func (g *fakeGauge) Set(valueName string, value float64) {}
+
+type fakeCounter struct{}
+
+func (c *fakeCounter) Inc(labelValues ...string) {}
+
+func TestRegistry_Limiter(t *testing.T) {
+	r := &Registry{
+		limiters: make(map[string]*Limiter),
+		metrics:  &limiterMetrics{tokens: &fakeGauge{}, pause: &fakeGauge{}, events: &fakeCounter{}},
+	}
+	a := r.Limiter("a", &Config{Limit: 1, Burst: 1})
+	if again := r.Limiter("a", &Config{Limit: 100, Burst: 100}); again != a {
+		t.Fatal("the limiter of the same host is not shared")
+	}
+	if a.burst != 1 {
+		t.Fatalf("burst = %v, want the config of the first call", a.burst)
+	}
+	if b := r.Limiter("b", nil); b == a || b.Host() != "b" {
+		t.Fatal("the limiter of the other host is shared")
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	prometheus_utils "github.com/minipkg/prometheus-utils"
+)
+
+const (
+	event_Wait      = "wait"
+	event_Canceled  = "canceled"
+	event_Throttled = "throttled"
+)
+
+type AppConfig struct {
+	NameSpace string
+	Subsystem string
+	Service   string
+}
+
+// Registry holds one Limiter per upstream host, so the clients working with the same host share it.
+type Registry struct {
+	mu       sync.Mutex
+	limiters map[string]*Limiter
+	metrics  *limiterMetrics
+}
+
+func NewRegistry(appConfig *AppConfig) *Registry {
+	return &Registry{
+		limiters: make(map[string]*Limiter),
+		metrics: &limiterMetrics{
+			tokens: prometheus_utils.NewGauge(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, "ratelimiter_tokens", "integration", "host"),
+			pause:  prometheus_utils.NewGauge(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, "ratelimiter_pause_seconds", "integration", "host"),
+			events: prometheus_utils.NewCounter(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, "ratelimiter_events", "integration", "host", "event"),
+		},
+	}
+}
+
+// Limiter returns the limiter of the host. The config is used only when the limiter for the host is created.
+func (r *Registry) Limiter(host string, cfg *Config) *Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.limiters[host]; ok {
+		return l
+	}
+	l := newLimiter(host, cfg, r.metrics)
+	r.limiters[host] = l
+	return l
+}
+
+type gauge interface {
+	Set(valueName string, value float64)
+}
+
+type counter interface {
+	Inc(labelValues ...string)
+}
+
+type limiterMetrics struct {
+	tokens gauge
+	pause  gauge
+	events counter
+}
+
+func (m *limiterMetrics) SetTokens(host string, tokens float64) {
+	m.tokens.Set(host, tokens)
+}
+
+func (m *limiterMetrics) SetPause(host string, pause time.Duration) {
+	m.pause.Set(host, pause.Seconds())
+}
+
+func (m *limiterMetrics) IncEvent(host string, event string) {
+	m.events.Inc(host, event)
+}
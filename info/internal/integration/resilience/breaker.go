@@ -0,0 +1,100 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState uint8
+
+const (
+	breakerState_Closed breakerState = iota
+	breakerState_HalfOpen
+	breakerState_Open
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerState_Closed:
+		return "closed"
+	case breakerState_HalfOpen:
+		return "half-open"
+	default:
+		return "open"
+	}
+}
+
+// breaker is a circuit breaker of a single endpoint.
+// It opens after threshold consecutive retryable failures (a success or a permanent error breaks the series), lets one probe request through after cooldown
+// and closes after the successful probe.
+type breaker struct {
+	mu        sync.Mutex
+	threshold uint
+	cooldown  time.Duration
+	state     breakerState
+	failures  uint
+	openedAt  time.Time
+	isProbing bool
+}
+
+func newBreaker(threshold uint, cooldown time.Duration) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow reports whether a request is allowed and returns the state after the check.
+func (b *breaker) allow() (bool, breakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerState_Open:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false, b.state
+		}
+		b.state = breakerState_HalfOpen
+		b.isProbing = true
+		return true, b.state
+	case breakerState_HalfOpen:
+		if b.isProbing {
+			return false, b.state
+		}
+		b.isProbing = true
+		return true, b.state
+	default:
+		return true, b.state
+	}
+}
+
+// done registers the result of a request and returns the state after it.
+func (b *breaker) done(class Class) breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.isProbing = false
+	switch class {
+	case ClassSuccess:
+		b.state = breakerState_Closed
+		b.failures = 0
+	case ClassRetryable:
+		b.failures++
+		if b.state == breakerState_HalfOpen || b.failures >= b.threshold {
+			b.state = breakerState_Open
+			b.openedAt = time.Now()
+		}
+	default:
+		// постоянная ошибка говорит о проблеме запроса, а не сервиса: сервис ответил, поэтому серия временных ошибок прервана
+		b.state = breakerState_Closed
+		b.failures = 0
+	}
+	return b.state
+}
+
+// release returns the probe without the result (e.g. the request has been canceled).
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.isProbing = false
+}
@@ -0,0 +1,115 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker_transitions(t *testing.T) {
+	type step struct {
+		class     Class
+		elapsed   time.Duration // the time passed since the opening before the allow call
+		wantAllow bool
+		wantState breakerState // the state after done or after the rejected allow
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "closed stays closed below the threshold",
+			steps: []step{
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+			},
+		},
+		{
+			name: "closed to open to half-open to closed",
+			steps: []step{
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Open},
+				{wantAllow: false, wantState: breakerState_Open},
+				{class: ClassSuccess, elapsed: time.Minute, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+			},
+		},
+		{
+			name: "failed probe opens again",
+			steps: []step{
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Open},
+				{class: ClassRetryable, elapsed: time.Minute, wantAllow: true, wantState: breakerState_Open},
+				{wantAllow: false, wantState: breakerState_Open},
+			},
+		},
+		{
+			name: "permanent error of the probe closes",
+			steps: []step{
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Open},
+				{class: ClassPermanent, elapsed: time.Minute, wantAllow: true, wantState: breakerState_Closed},
+			},
+		},
+		{
+			name: "success resets the failures",
+			steps: []step{
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassSuccess, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+			},
+		},
+		{
+			name: "permanent error resets the failures",
+			steps: []step{
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassPermanent, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+				{class: ClassRetryable, wantAllow: true, wantState: breakerState_Closed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(3, time.Minute)
+			for i, s := range tt.steps {
+				b.openedAt = b.openedAt.Add(-s.elapsed)
+				isAllowed, _ := b.allow()
+				if isAllowed != s.wantAllow {
+					t.Fatalf("step %d: allow = %v, want %v", i, isAllowed, s.wantAllow)
+				}
+				state := b.state
+				if isAllowed {
+					state = b.done(s.class)
+				}
+				if state != s.wantState {
+					t.Fatalf("step %d: state = %v, want %v", i, state, s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestBreaker_singleProbe(t *testing.T) {
+	b := newBreaker(1, time.Minute)
+	b.allow()
+	b.done(ClassRetryable)
+	b.openedAt = b.openedAt.Add(-time.Minute)
+
+	if isAllowed, state := b.allow(); !isAllowed || state != breakerState_HalfOpen {
+		t.Fatalf("probe: allow = %v, %v; want true, %v", isAllowed, state, breakerState_HalfOpen)
+	}
+	if isAllowed, _ := b.allow(); isAllowed {
+		t.Fatal("the second request is allowed during the probe")
+	}
+	b.release()
+	if isAllowed, _ := b.allow(); !isAllowed {
+		t.Fatal("the probe is not allowed after the release")
+	}
+}
@@ -0,0 +1,53 @@
+package resilience
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type Class uint8
+
+const (
+	ClassSuccess Class = iota
+	// ClassRetryable - временная ошибка: таймаут, сетевая ошибка, 429, 5xx
+	ClassRetryable
+	// ClassPermanent - повтор запроса не поможет: 4xx, ошибка формирования запроса, отмена контекста
+	ClassPermanent
+)
+
+// httpclient возвращает код 0 и при ошибке выполнения запроса (сеть, таймаут), и при ошибке сериализации тела запроса
+const errPrefix_Marshal = "error marshal request object"
+
+func (c Class) String() string {
+	switch c {
+	case ClassSuccess:
+		return "success"
+	case ClassRetryable:
+		return "retryable"
+	default:
+		return "permanent"
+	}
+}
+
+// Classify classifies the result of a httpClient call.
+// Errors of the response schema appear above this layer (in the API clients) and they are always permanent.
+func Classify(ctx context.Context, code int, err error) Class {
+	if ctx.Err() != nil {
+		return ClassPermanent
+	}
+	switch {
+	case code == 0 && err == nil:
+		return ClassSuccess
+	case code == 0 && strings.HasPrefix(err.Error(), errPrefix_Marshal):
+		return ClassPermanent
+	case code == 0:
+		return ClassRetryable
+	case code == http.StatusTooManyRequests || code >= http.StatusInternalServerError:
+		return ClassRetryable
+	case code >= http.StatusBadRequest || err != nil:
+		return ClassPermanent
+	default:
+		return ClassSuccess
+	}
+}
@@ -0,0 +1,39 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code int
+		err  error
+		want Class
+	}{
+		{name: "success", ctx: context.Background(), code: 0, want: ClassSuccess},
+		{name: "ok code", ctx: context.Background(), code: http.StatusOK, want: ClassSuccess},
+		{name: "network error", ctx: context.Background(), code: 0, err: errors.New("dial tcp: connection refused"), want: ClassRetryable},
+		{name: "marshal error", ctx: context.Background(), code: 0, err: errors.New(errPrefix_Marshal + ": unsupported type"), want: ClassPermanent},
+		{name: "too many requests", ctx: context.Background(), code: http.StatusTooManyRequests, err: errors.New("429"), want: ClassRetryable},
+		{name: "server error", ctx: context.Background(), code: http.StatusBadGateway, err: errors.New("502"), want: ClassRetryable},
+		{name: "not found", ctx: context.Background(), code: http.StatusNotFound, err: errors.New("404"), want: ClassPermanent},
+		{name: "error with ok code", ctx: context.Background(), code: http.StatusOK, err: errors.New("read body"), want: ClassPermanent},
+		{name: "canceled context", ctx: canceled, code: 0, err: context.Canceled, want: ClassPermanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.ctx, tt.code, tt.err); got != tt.want {
+				t.Fatalf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minipkg/httpclient"
+	"go.uber.org/zap"
+
+	"info/internal/pkg/apperror"
+	"info/internal/pkg/log_key"
+)
+
+const (
+	defaultMaxRetries       = 3
+	defaultBaseDelay        = time.Second
+	defaultMaxDelay         = 30 * time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = time.Minute
+)
+
+var ErrCircuitOpen = apperror.NewError("circuit breaker is open")
+
+// Config of the retries and of the circuit breakers. Zero fields are set to the defaults.
+type Config struct {
+	MaxRetries       uint
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	BreakerThreshold uint // количество подряд идущих временных ошибок для размыкания
+	BreakerCooldown  time.Duration
+}
+
+func (c *Config) withDefaults() Config {
+	res := Config{}
+	if c != nil {
+		res = *c
+	}
+	if res.MaxRetries == 0 {
+		res.MaxRetries = defaultMaxRetries
+	}
+	if res.BaseDelay == 0 {
+		res.BaseDelay = defaultBaseDelay
+	}
+	if res.MaxDelay == 0 {
+		res.MaxDelay = defaultMaxDelay
+	}
+	if res.BreakerThreshold == 0 {
+		res.BreakerThreshold = defaultBreakerThreshold
+	}
+	if res.BreakerCooldown == 0 {
+		res.BreakerCooldown = defaultBreakerCooldown
+	}
+	return res
+}
+
+type httpClient interface {
+	Get(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, int, error)
+	Post(ctx context.Context, path string, reqObj interface{}, opts ...httpclient.RequestOption) ([]byte, int, error)
+}
+
+// Client is a httpClient which retries the retryable errors with jittered exponential backoff
+// and stops calling an endpoint by the circuit breaker of the endpoint.
+type Client struct {
+	name     string
+	client   httpClient
+	config   Config
+	metrics  *Metrics
+	logger   *zap.Logger
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func NewClient(name string, client httpClient, conf *Config, metrics *Metrics, logger *zap.Logger) *Client {
+	return &Client{
+		name:     name,
+		client:   client,
+		config:   conf.withDefaults(),
+		metrics:  metrics,
+		logger:   logger,
+		breakers: make(map[string]*breaker),
+	}
+}
+
+func (c *Client) Get(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, int, error) {
+	return c.do(ctx, path, func() ([]byte, int, error) {
+		return c.client.Get(ctx, path, opts...)
+	})
+}
+
+func (c *Client) Post(ctx context.Context, path string, reqObj interface{}, opts ...httpclient.RequestOption) ([]byte, int, error) {
+	return c.do(ctx, path, func() ([]byte, int, error) {
+		return c.client.Post(ctx, path, reqObj, opts...)
+	})
+}
+
+func (c *Client) do(ctx context.Context, path string, exec func() ([]byte, int, error)) (data []byte, code int, err error) {
+	const funcName = "resilience.Client.do"
+	endpoint := endpointOf(path)
+	b := c.breaker(endpoint)
+	var class Class
+
+	for attempt := uint(0); ; attempt++ {
+		isAllowed, state := b.allow()
+		if !isAllowed {
+			c.metrics.incEvent(c.name, endpoint, event_BreakerRejected)
+			return nil, 0, fmt.Errorf("[%w] %s: %w; endpoint: %s", apperror.ErrInternal, c.name, ErrCircuitOpen, endpoint)
+		}
+		if state == breakerState_HalfOpen {
+			c.metrics.setBreakerState(c.name, endpoint, state)
+		}
+
+		data, code, err = exec()
+		class = Classify(ctx, code, err)
+		if ctx.Err() != nil {
+			b.release()
+			return data, code, err
+		}
+		c.setBreakerState(endpoint, state, b.done(class))
+
+		switch class {
+		case ClassSuccess:
+			return data, code, err
+		case ClassPermanent:
+			c.metrics.incEvent(c.name, endpoint, event_PermanentError)
+			return data, code, err
+		}
+
+		if attempt >= c.config.MaxRetries {
+			c.metrics.incEvent(c.name, endpoint, event_RetryExhausted)
+			return data, code, err
+		}
+		c.metrics.incEvent(c.name, endpoint, event_Retry)
+		c.logger.Warn("retry after error", zap.String(log_key.ApiClient, c.name), zap.String(log_key.Func, funcName), zap.String(log_key.Endpoint, endpoint), zap.Uint(log_key.Attempt, attempt+1), zap.Int(log_key.Code, code), zap.Error(err))
+
+		timer := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return data, code, err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns a random delay from [d/2, d], where d = BaseDelay * 2^attempt limited by MaxDelay.
+func (c *Client) backoff(attempt uint) time.Duration {
+	d := c.config.MaxDelay
+	if attempt < 32 {
+		if exp := c.config.BaseDelay << attempt; exp > 0 && exp < d {
+			d = exp
+		}
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func (c *Client) breaker(endpoint string) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.breakers[endpoint]
+	if !ok {
+		b = newBreaker(c.config.BreakerThreshold, c.config.BreakerCooldown)
+		c.breakers[endpoint] = b
+		c.metrics.setBreakerState(c.name, endpoint, breakerState_Closed)
+	}
+	return b
+}
+
+func (c *Client) setBreakerState(endpoint string, before, after breakerState) {
+	if before == after {
+		return
+	}
+	c.metrics.setBreakerState(c.name, endpoint, after)
+	switch after {
+	case breakerState_Open:
+		c.metrics.incEvent(c.name, endpoint, event_BreakerOpened)
+		c.logger.Error("circuit breaker is opened", zap.String(log_key.ApiClient, c.name), zap.String(log_key.Endpoint, endpoint), zap.Duration(log_key.Cooldown, c.config.BreakerCooldown))
+	case breakerState_Closed:
+		c.metrics.incEvent(c.name, endpoint, event_BreakerClosed)
+		c.logger.Info("circuit breaker is closed", zap.String(log_key.ApiClient, c.name), zap.String(log_key.Endpoint, endpoint))
+	}
+}
+
+// endpointOf returns the path without the query: the query has the IDs, so it is not an endpoint label.
+func endpointOf(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
@@ -0,0 +1,156 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/minipkg/httpclient"
+	"go.uber.org/zap"
+)
+
+type fakeGauge struct{}
+
+func (g *fakeGauge) SetWithLabelValues(labelValues *[]string, value float64) {}
+
+type fakeCounter struct {
+	events map[string]int
+}
+
+func (c *fakeCounter) Inc(labelValues ...string) { c.events[labelValues[len(labelValues)-1]]++ }
+
+type response struct {
+	code int
+	err  error
+}
+
+// fakeHttpClient returns the responses in turn and the last one after them.
+type fakeHttpClient struct {
+	responses []response
+	calls     int
+}
+
+func (c *fakeHttpClient) Get(ctx context.Context, path string, opts ...httpclient.RequestOption) ([]byte, int, error) {
+	i := c.calls
+	if i >= len(c.responses) {
+		i = len(c.responses) - 1
+	}
+	c.calls++
+	return nil, c.responses[i].code, c.responses[i].err
+}
+
+func (c *fakeHttpClient) Post(ctx context.Context, path string, reqObj interface{}, opts ...httpclient.RequestOption) ([]byte, int, error) {
+	return c.Get(ctx, path)
+}
+
+func TestClient_retry(t *testing.T) {
+	errServer := errors.New("500")
+	errNotFound := errors.New("404")
+	tests := []struct {
+		name       string
+		responses  []response
+		config     Config
+		wantCalls  int
+		wantErr    error
+		wantEvents map[string]int
+	}{
+		{
+			name:       "success at once",
+			responses:  []response{{}},
+			wantCalls:  1,
+			wantEvents: map[string]int{},
+		},
+		{
+			name:       "retryable error then success",
+			responses:  []response{{http.StatusInternalServerError, errServer}, {}},
+			wantCalls:  2,
+			wantEvents: map[string]int{event_Retry: 1},
+		},
+		{
+			name:       "permanent error is not retried",
+			responses:  []response{{http.StatusNotFound, errNotFound}},
+			wantCalls:  1,
+			wantErr:    errNotFound,
+			wantEvents: map[string]int{event_PermanentError: 1},
+		},
+		{
+			name:       "retries are exhausted",
+			responses:  []response{{http.StatusInternalServerError, errServer}},
+			config:     Config{MaxRetries: 2},
+			wantCalls:  3,
+			wantErr:    errServer,
+			wantEvents: map[string]int{event_Retry: 2, event_RetryExhausted: 1},
+		},
+		{
+			name:       "open breaker rejects the retry",
+			responses:  []response{{http.StatusInternalServerError, errServer}},
+			config:     Config{MaxRetries: 5, BreakerThreshold: 2},
+			wantCalls:  2,
+			wantErr:    ErrCircuitOpen,
+			wantEvents: map[string]int{event_Retry: 2, event_BreakerOpened: 1, event_BreakerRejected: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.BaseDelay = time.Millisecond
+			tt.config.MaxDelay = time.Millisecond
+			counter := &fakeCounter{events: make(map[string]int)}
+			httpClient := &fakeHttpClient{responses: tt.responses}
+			c := NewClient("test", httpClient, &tt.config, &Metrics{breakerState: &fakeGauge{}, events: counter}, zap.NewNop())
+
+			_, _, err := c.Get(context.Background(), "/v1/items?id=1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if httpClient.calls != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", httpClient.calls, tt.wantCalls)
+			}
+			for event, want := range tt.wantEvents {
+				if counter.events[event] != want {
+					t.Fatalf("events = %v, want %v", counter.events, tt.wantEvents)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_backoff(t *testing.T) {
+	c := NewClient("test", nil, &Config{BaseDelay: time.Second, MaxDelay: 10 * time.Second}, nil, zap.NewNop())
+	tests := []struct {
+		attempt uint
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Second},
+		{attempt: 2, want: 4 * time.Second},
+		{attempt: 4, want: 10 * time.Second},
+		{attempt: 100, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := c.backoff(tt.attempt); d < tt.want/2 || d > tt.want {
+				t.Fatalf("backoff(%d) = %v, want from %v to %v", tt.attempt, d, tt.want/2, tt.want)
+			}
+		}
+	}
+}
+
+func TestEndpointOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/v1/items", want: "/v1/items"},
+		{path: "/v1/items?id=1,2", want: "/v1/items"},
+		{path: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := endpointOf(tt.path); got != tt.want {
+			t.Fatalf("endpointOf(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package resilience
+
+import (
+	prometheus_utils "github.com/minipkg/prometheus-utils"
+)
+
+const (
+	event_Retry           = "retry"
+	event_RetryExhausted  = "retry_exhausted"
+	event_PermanentError  = "permanent_error"
+	event_BreakerOpened   = "breaker_opened"
+	event_BreakerClosed   = "breaker_closed"
+	event_BreakerRejected = "breaker_rejected"
+)
+
+type AppConfig struct {
+	NameSpace string
+	Subsystem string
+	Service   string
+}
+
+type gauge interface {
+	SetWithLabelValues(labelValues *[]string, value float64)
+}
+
+type counter interface {
+	Inc(labelValues ...string)
+}
+
+// Metrics are shared by all the clients, because the prometheus metrics can be registered only once.
+type Metrics struct {
+	breakerState gauge
+	events       counter
+}
+
+func NewMetrics(appConfig *AppConfig) *Metrics {
+	return &Metrics{
+		breakerState: prometheus_utils.NewGauge(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, "circuit_breaker_state", "integration", "client", "endpoint"),
+		events:       prometheus_utils.NewCounter(appConfig.NameSpace, appConfig.Subsystem, appConfig.Service, "resilience_events", "integration", "client", "endpoint", "event"),
+	}
+}
+
+// setBreakerState sets 0 for closed, 1 for half-open and 2 for open breaker.
+func (m *Metrics) setBreakerState(client, endpoint string, state breakerState) {
+	m.breakerState.SetWithLabelValues(&[]string{client, endpoint}, float64(state))
+}
+
+func (m *Metrics) incEvent(client, endpoint, event string) {
+	m.events.Inc(client, endpoint, event)
+}
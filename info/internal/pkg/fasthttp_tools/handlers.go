@@ -7,6 +7,8 @@ import (
 	"github.com/valyala/fasthttp"
 	"info/internal/pkg/apperror"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -30,6 +32,89 @@ func ParseQueryArgUint(ctx *fasthttp.RequestCtx, name string) (uint, error) {
 	return uint(val), nil
 }
 
+// ParseQueryArgUints parses the comma separated list of uints.
+func ParseQueryArgUints(ctx *fasthttp.RequestCtx, name string) ([]uint, error) {
+	valStr, err := ParseQueryArgString(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(valStr, ",")
+	res := make([]uint, 0, len(parts))
+	var part string
+	for _, part = range parts {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		val, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("[%w] failed to parse uint list param %s; error: %w", apperror.ErrBadRequest, name, err)
+		}
+		res = append(res, uint(val))
+	}
+
+	return res, nil
+}
+
+func ParseQueryArgFloat(ctx *fasthttp.RequestCtx, name string) (float64, error) {
+	valStr, err := ParseQueryArgString(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := strconv.ParseFloat(valStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("[%w] failed to parse float param %s; error: %w", apperror.ErrBadRequest, name, err)
+	}
+
+	return val, nil
+}
+
+func ParseQueryArgBool(ctx *fasthttp.RequestCtx, name string) (bool, error) {
+	valStr, err := ParseQueryArgString(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	val, err := strconv.ParseBool(valStr)
+	if err != nil {
+		return false, fmt.Errorf("[%w] failed to parse bool param %s; error: %w", apperror.ErrBadRequest, name, err)
+	}
+
+	return val, nil
+}
+
+func ParseQueryArgDuration(ctx *fasthttp.RequestCtx, name string) (time.Duration, error) {
+	valStr, err := ParseQueryArgString(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := time.ParseDuration(valStr)
+	if err != nil {
+		return 0, fmt.Errorf("[%w] failed to parse duration param %s; error: %w", apperror.ErrBadRequest, name, err)
+	}
+
+	return val, nil
+}
+
+// ParseQueryArgTime parses the time in RFC3339 or the date in YYYY-MM-DD (UTC).
+func ParseQueryArgTime(ctx *fasthttp.RequestCtx, name string) (time.Time, error) {
+	valStr, err := ParseQueryArgString(ctx, name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	val, err := time.Parse(time.RFC3339, valStr)
+	if err != nil {
+		if val, err = time.Parse(time.DateOnly, valStr); err != nil {
+			return time.Time{}, fmt.Errorf("[%w] failed to parse time param %s; error: %w", apperror.ErrBadRequest, name, err)
+		}
+	}
+
+	return val.UTC(), nil
+}
+
 func ParseQueryArgString(ctx *fasthttp.RequestCtx, name string) (string, error) {
 	val := string(ctx.QueryArgs().Peek(name))
 	if val == "" {
@@ -61,15 +61,14 @@ func NewResponse_Success(data interface{}) *Response {
 	}
 }
 
-//
-//func NewResponse_SuccessWithPagination(data interface{}, limit uint, offset uint, count uint) *Response {
-//	return &Response{
-//		ErrorText: apperror.NewError(""),
-//		Data:      data,
-//		Pagination: &Pagination{
-//			Offset:  offset,
-//			Size:    limit,
-//			TotalNb: count,
-//		},
-//	}
-//}
+func NewResponse_SuccessWithPagination(data interface{}, limit uint, offset uint, count uint) *Response {
+	return &Response{
+		ErrorText: apperror.NewError(""),
+		Data:      data,
+		Pagination: &Pagination{
+			Offset:  offset,
+			Size:    limit,
+			TotalNb: count,
+		},
+	}
+}
@@ -7,4 +7,15 @@ const (
 	ErrorCode       = "errorCode"
 	ErrorMessage    = "errorMessage"
 	ErrorStacktrace = "errorStacktrace"
+	Endpoint        = "endpoint"
+	Attempt         = "attempt"
+	Cooldown        = "cooldown"
+	Token           = "token"
+	AlertRule       = "alertRule"
+	AlertKind       = "alertKind"
+	Currency        = "currency"
+	Value           = "value"
+	Dataset         = "dataset"
+	Format          = "format"
+	Rows            = "rows"
 )
@@ -0,0 +1,93 @@
+package parquet
+
+import (
+	"encoding/binary"
+)
+
+// the types of the fields of the thrift compact protocol
+const (
+	thrift_I32    = 5
+	thrift_I64    = 6
+	thrift_Binary = 8
+	thrift_List   = 9
+	thrift_Struct = 12
+)
+
+// thriftWriter encodes the structs of the parquet metadata with the thrift compact protocol
+type thriftWriter struct {
+	buf         []byte
+	lastFieldID int16
+	stack       []int16 // the last field IDs of the outer structs
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *thriftWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) fieldHeader(ID int16, typ byte) {
+	if delta := ID - w.lastFieldID; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.zigzag(int64(ID))
+	}
+	w.lastFieldID = ID
+}
+
+func (w *thriftWriter) fieldI32(ID int16, v int32) {
+	w.fieldHeader(ID, thrift_I32)
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) fieldI64(ID int16, v int64) {
+	w.fieldHeader(ID, thrift_I64)
+	w.zigzag(v)
+}
+
+func (w *thriftWriter) fieldString(ID int16, v string) {
+	w.fieldHeader(ID, thrift_Binary)
+	w.varint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// fieldList writes the header of the list field; the elements are written by the caller
+func (w *thriftWriter) fieldList(ID int16, elemType byte, size int) {
+	w.fieldHeader(ID, thrift_List)
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|elemType)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|elemType)
+	w.varint(uint64(size))
+}
+
+func (w *thriftWriter) i32(v int32) {
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) string(v string) {
+	w.varint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// fieldStruct writes the header of the struct field and begins the struct
+func (w *thriftWriter) fieldStruct(ID int16) {
+	w.fieldHeader(ID, thrift_Struct)
+	w.structBegin()
+}
+
+// structBegin begins the struct: the element of the list or the nested struct
+func (w *thriftWriter) structBegin() {
+	w.stack = append(w.stack, w.lastFieldID)
+	w.lastFieldID = 0
+}
+
+func (w *thriftWriter) structEnd() {
+	w.buf = append(w.buf, 0)
+	w.lastFieldID = w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+}
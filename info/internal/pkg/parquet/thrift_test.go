@@ -0,0 +1,173 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+// thriftReader decodes the thrift compact protocol: the struct is map[field ID]value, the integers are int64,
+// the binaries are string and the lists are []any.
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *thriftReader) byte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, fmt.Errorf("unexpected end at %d", r.pos)
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("bad varint at %d", r.pos)
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *thriftReader) zigzag() (int64, error) {
+	v, err := r.varint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *thriftReader) value(typ byte) (any, error) {
+	switch typ {
+	case thrift_I32, thrift_I64:
+		return r.zigzag()
+	case thrift_Binary:
+		size, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if r.pos+int(size) > len(r.buf) {
+			return nil, fmt.Errorf("binary out of range at %d", r.pos)
+		}
+		s := string(r.buf[r.pos : r.pos+int(size)])
+		r.pos += int(size)
+		return s, nil
+	case thrift_List:
+		h, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(h >> 4)
+		if size == 15 {
+			if size, err = r.varint(); err != nil {
+				return nil, err
+			}
+		}
+		res := make([]any, 0, size)
+		for i := uint64(0); i < size; i++ {
+			v, err := r.value(h & 0x0f)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, v)
+		}
+		return res, nil
+	case thrift_Struct:
+		return r.structValue()
+	}
+	return nil, fmt.Errorf("unknown type %d at %d", typ, r.pos)
+}
+
+func (r *thriftReader) structValue() (map[int16]any, error) {
+	res := make(map[int16]any)
+	var lastID int16
+	for {
+		h, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if h == 0 {
+			return res, nil
+		}
+		ID := lastID + int16(h>>4)
+		if h>>4 == 0 {
+			v, err := r.zigzag()
+			if err != nil {
+				return nil, err
+			}
+			ID = int16(v)
+		}
+		if res[ID], err = r.value(h & 0x0f); err != nil {
+			return nil, err
+		}
+		lastID = ID
+	}
+}
+
+func TestThriftWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *thriftWriter)
+		want  []byte
+	}{
+		{
+			name: "short field delta",
+			write: func(w *thriftWriter) {
+				w.fieldI32(1, 0)
+				w.fieldI32(3, -1)
+				w.fieldI64(4, 64)
+			},
+			want: []byte{0x15, 0x00, 0x25, 0x01, 0x16, 0x80, 0x01},
+		},
+		{
+			name: "long field delta",
+			write: func(w *thriftWriter) {
+				w.fieldI32(20, 1)
+			},
+			want: []byte{0x05, 0x28, 0x02},
+		},
+		{
+			name: "string",
+			write: func(w *thriftWriter) {
+				w.fieldString(2, "ab")
+			},
+			want: []byte{0x28, 0x02, 'a', 'b'},
+		},
+		{
+			name: "short list",
+			write: func(w *thriftWriter) {
+				w.fieldList(1, thrift_I32, 2)
+				w.i32(1)
+				w.i32(2)
+			},
+			want: []byte{0x19, 0x25, 0x02, 0x04},
+		},
+		{
+			name: "long list",
+			write: func(w *thriftWriter) {
+				w.fieldList(1, thrift_I32, 15)
+			},
+			want: []byte{0x19, 0xf5, 0x0f},
+		},
+		{
+			name: "nested struct restores the field ID",
+			write: func(w *thriftWriter) {
+				w.fieldI32(2, 0)
+				w.fieldStruct(3)
+				w.fieldI32(1, 0)
+				w.structEnd()
+				w.fieldI32(4, 0)
+			},
+			want: []byte{0x25, 0x00, 0x1c, 0x15, 0x00, 0x00, 0x15, 0x00},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &thriftWriter{}
+			tt.write(w)
+			if !bytes.Equal(w.buf, tt.want) {
+				t.Errorf("buf = % x, want % x", w.buf, tt.want)
+			}
+		})
+	}
+}
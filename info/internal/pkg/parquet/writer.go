@@ -0,0 +1,300 @@
+// Package parquet is the minimal writer of the parquet files: the flat schema of the required columns,
+// the PLAIN encoding without the compression, one data page per column in the row group.
+// The rows are buffered up to the row group, so the memory does not depend on the number of the rows.
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	Type_Int64     = iota // INT64
+	Type_Double           // DOUBLE
+	Type_String           // BYTE_ARRAY, UTF8
+	Type_Timestamp        // INT64, TIMESTAMP_MICROS in UTC
+	Type_Date             // INT32, DATE
+)
+
+const DefaultRowGroupSize = 65536
+
+const magic = "PAR1"
+
+// the enums of the parquet format
+const (
+	physical_Int32     = 1
+	physical_Int64     = 2
+	physical_Double    = 5
+	physical_ByteArray = 6
+
+	converted_UTF8            = 0
+	converted_Date            = 6
+	converted_TimestampMicros = 10
+
+	repetition_Required = 0
+	encoding_Plain      = 0
+	encoding_RLE        = 3
+	codec_Uncompressed  = 0
+	pageType_Data       = 0
+)
+
+const secondsInDay = 24 * 60 * 60
+
+// Column of the file; the values of Write are int64 for Type_Int64, float64 for Type_Double, string for Type_String
+// and time.Time for Type_Timestamp and Type_Date.
+type Column struct {
+	Name string
+	Type int
+}
+
+type columnChunk struct {
+	offset int64
+	size   int64
+}
+
+type rowGroup struct {
+	columns []columnChunk
+	rowsNb  int64
+	size    int64
+}
+
+// Writer writes the rows to the parquet file; Close must be called to write the footer.
+type Writer struct {
+	w            io.Writer
+	columns      []Column
+	rowGroupSize int
+	offset       int64
+	values       [][]byte // the PLAIN encoded values of the row group by the columns
+	rowsNb       int      // the rows of the row group
+	totalRowsNb  int64
+	rowGroups    []rowGroup
+}
+
+func NewWriter(w io.Writer, columns []Column, rowGroupSize int) *Writer {
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+	return &Writer{
+		w:            w,
+		columns:      columns,
+		rowGroupSize: rowGroupSize,
+		values:       make([][]byte, len(columns)),
+	}
+}
+
+// Write appends the row with the values in the order of the columns.
+func (w *Writer) Write(row []any) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: the row has %d values, the columns are %d", len(row), len(w.columns))
+	}
+	for i, c := range w.columns {
+		buf, err := appendValue(w.values[i], c, row[i])
+		if err != nil {
+			return err
+		}
+		w.values[i] = buf
+	}
+	w.rowsNb++
+	if w.rowsNb >= w.rowGroupSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+func appendValue(buf []byte, c Column, value any) ([]byte, error) {
+	switch c.Type {
+	case Type_Int64:
+		if v, ok := value.(int64); ok {
+			return binary.LittleEndian.AppendUint64(buf, uint64(v)), nil
+		}
+	case Type_Double:
+		if v, ok := value.(float64); ok {
+			return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v)), nil
+		}
+	case Type_String:
+		if v, ok := value.(string); ok {
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+			return append(buf, v...), nil
+		}
+	case Type_Timestamp:
+		if v, ok := value.(time.Time); ok {
+			return binary.LittleEndian.AppendUint64(buf, uint64(v.UnixMicro())), nil
+		}
+	case Type_Date:
+		if v, ok := value.(time.Time); ok {
+			days := v.Unix() / secondsInDay
+			if v.Unix()%secondsInDay < 0 {
+				days--
+			}
+			return binary.LittleEndian.AppendUint32(buf, uint32(int32(days))), nil
+		}
+	default:
+		return nil, fmt.Errorf("parquet: column %q: unknown type %d", c.Name, c.Type)
+	}
+	return nil, fmt.Errorf("parquet: column %q: unexpected value of type %T", c.Name, value)
+}
+
+// Flush writes the buffered rows as the row group.
+func (w *Writer) Flush() error {
+	if w.rowsNb == 0 {
+		return nil
+	}
+	if err := w.writeMagic(); err != nil {
+		return err
+	}
+
+	group := rowGroup{
+		columns: make([]columnChunk, len(w.columns)),
+		rowsNb:  int64(w.rowsNb),
+	}
+	for i := range w.columns {
+		header := w.pageHeader(len(w.values[i]))
+		chunk := columnChunk{
+			offset: w.offset,
+			size:   int64(len(header) + len(w.values[i])),
+		}
+		if err := w.write(header); err != nil {
+			return err
+		}
+		if err := w.write(w.values[i]); err != nil {
+			return err
+		}
+		group.columns[i] = chunk
+		group.size += chunk.size
+		w.values[i] = w.values[i][:0]
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.totalRowsNb += group.rowsNb
+	w.rowsNb = 0
+	return nil
+}
+
+// Close writes the buffered rows and the footer; it does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := w.writeMagic(); err != nil {
+		return err
+	}
+	meta := w.fileMetaData()
+	if err := w.write(meta); err != nil {
+		return err
+	}
+	if err := w.write(binary.LittleEndian.AppendUint32(nil, uint32(len(meta)))); err != nil {
+		return err
+	}
+	return w.write([]byte(magic))
+}
+
+func (w *Writer) writeMagic() error {
+	if w.offset > 0 {
+		return nil
+	}
+	return w.write([]byte(magic))
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+func (w *Writer) pageHeader(size int) []byte {
+	t := &thriftWriter{}
+	t.structBegin()
+	t.fieldI32(1, pageType_Data)
+	t.fieldI32(2, int32(size))
+	t.fieldI32(3, int32(size))
+	t.fieldStruct(5)
+	t.fieldI32(1, int32(w.rowsNb))
+	t.fieldI32(2, encoding_Plain)
+	t.fieldI32(3, encoding_RLE)
+	t.fieldI32(4, encoding_RLE)
+	t.structEnd()
+	t.structEnd()
+	return t.buf
+}
+
+func (w *Writer) fileMetaData() []byte {
+	t := &thriftWriter{}
+	t.structBegin()
+	t.fieldI32(1, 1)
+
+	t.fieldList(2, thrift_Struct, len(w.columns)+1)
+	t.structBegin()
+	t.fieldString(4, "schema")
+	t.fieldI32(5, int32(len(w.columns)))
+	t.structEnd()
+	for _, c := range w.columns {
+		t.structBegin()
+		t.fieldI32(1, physicalType(c.Type))
+		t.fieldI32(3, repetition_Required)
+		t.fieldString(4, c.Name)
+		if converted, ok := convertedType(c.Type); ok {
+			t.fieldI32(6, converted)
+		}
+		t.structEnd()
+	}
+
+	t.fieldI64(3, w.totalRowsNb)
+
+	t.fieldList(4, thrift_Struct, len(w.rowGroups))
+	for _, group := range w.rowGroups {
+		t.structBegin()
+		t.fieldList(1, thrift_Struct, len(group.columns))
+		for i, chunk := range group.columns {
+			t.structBegin()
+			t.fieldI64(2, chunk.offset)
+			t.fieldStruct(3)
+			t.fieldI32(1, physicalType(w.columns[i].Type))
+			t.fieldList(2, thrift_I32, 1)
+			t.i32(encoding_Plain)
+			t.fieldList(3, thrift_Binary, 1)
+			t.string(w.columns[i].Name)
+			t.fieldI32(4, codec_Uncompressed)
+			t.fieldI64(5, group.rowsNb)
+			t.fieldI64(6, chunk.size)
+			t.fieldI64(7, chunk.size)
+			t.fieldI64(9, chunk.offset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.fieldI64(2, group.size)
+		t.fieldI64(3, group.rowsNb)
+		t.structEnd()
+	}
+
+	t.fieldString(6, "info export")
+	t.structEnd()
+	return t.buf
+}
+
+func physicalType(typ int) int32 {
+	switch typ {
+	case Type_Double:
+		return physical_Double
+	case Type_String:
+		return physical_ByteArray
+	case Type_Date:
+		return physical_Int32
+	default:
+		return physical_Int64
+	}
+}
+
+func convertedType(typ int) (int32, bool) {
+	switch typ {
+	case Type_String:
+		return converted_UTF8, true
+	case Type_Timestamp:
+		return converted_TimestampMicros, true
+	case Type_Date:
+		return converted_Date, true
+	default:
+		return 0, false
+	}
+}
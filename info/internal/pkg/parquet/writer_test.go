@@ -0,0 +1,256 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testColumns = []Column{
+	{Name: "id", Type: Type_Int64},
+	{Name: "price", Type: Type_Double},
+	{Name: "symbol", Type: Type_String},
+	{Name: "ts", Type: Type_Timestamp},
+	{Name: "d", Type: Type_Date},
+}
+
+var testRows = [][]any{
+	{int64(1), 1.5, "BTC", time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC), time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+	{int64(-2), -0.25, "", time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+	{int64(3), math.MaxFloat64, "ЭФИР", time.Unix(0, 0).UTC(), time.Unix(0, 0).UTC()},
+}
+
+// readFooter checks the magic at the both ends and decodes the FileMetaData.
+func readFooter(t *testing.T, file []byte) map[int16]any {
+	t.Helper()
+	if len(file) < 12 || string(file[:4]) != magic || string(file[len(file)-4:]) != magic {
+		t.Fatalf("no magic at the both ends of % x", file)
+	}
+	size := int(binary.LittleEndian.Uint32(file[len(file)-8 : len(file)-4]))
+	start := len(file) - 8 - size
+	if start < 4 {
+		t.Fatalf("footer length %d is out of the file of %d bytes", size, len(file))
+	}
+	r := &thriftReader{buf: file[start : len(file)-8]}
+	meta, err := r.structValue()
+	if err != nil {
+		t.Fatalf("FileMetaData decode error: %v", err)
+	}
+	if r.pos != size {
+		t.Fatalf("FileMetaData has %d bytes, footer length is %d", r.pos, size)
+	}
+	return meta
+}
+
+// readPage decodes the page header at the offset and returns it with the values of the page.
+func readPage(t *testing.T, file []byte, offset int64) (map[int16]any, []byte) {
+	t.Helper()
+	r := &thriftReader{buf: file, pos: int(offset)}
+	header, err := r.structValue()
+	if err != nil {
+		t.Fatalf("PageHeader decode error at %d: %v", offset, err)
+	}
+	size := int(header[3].(int64))
+	if r.pos+size > len(file) {
+		t.Fatalf("page at %d of %d bytes is out of the file", offset, size)
+	}
+	return header, file[r.pos : r.pos+size]
+}
+
+func decodeValues(t *testing.T, c Column, data []byte, n int) []any {
+	t.Helper()
+	res := make([]any, 0, n)
+	for i := 0; i < n; i++ {
+		switch c.Type {
+		case Type_Int64:
+			res = append(res, int64(binary.LittleEndian.Uint64(data)))
+			data = data[8:]
+		case Type_Double:
+			res = append(res, math.Float64frombits(binary.LittleEndian.Uint64(data)))
+			data = data[8:]
+		case Type_String:
+			size := binary.LittleEndian.Uint32(data)
+			res = append(res, string(data[4:4+size]))
+			data = data[4+size:]
+		case Type_Timestamp:
+			res = append(res, time.UnixMicro(int64(binary.LittleEndian.Uint64(data))).UTC())
+			data = data[8:]
+		case Type_Date:
+			days := int64(int32(binary.LittleEndian.Uint32(data)))
+			res = append(res, time.Unix(days*secondsInDay, 0).UTC())
+			data = data[4:]
+		}
+	}
+	if len(data) != 0 {
+		t.Errorf("column %q: %d bytes left after %d values", c.Name, len(data), n)
+	}
+	return res
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowGroupSize int
+		rows         [][]any
+		wantGroups   []int64
+	}{
+		{name: "no rows", rowGroupSize: 2, rows: nil, wantGroups: []int64{}},
+		{name: "one row group", rowGroupSize: 0, rows: testRows, wantGroups: []int64{3}},
+		{name: "several row groups", rowGroupSize: 2, rows: testRows, wantGroups: []int64{2, 1}},
+		{name: "full row groups", rowGroupSize: 1, rows: testRows, wantGroups: []int64{1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := NewWriter(buf, testColumns, tt.rowGroupSize)
+			for _, row := range tt.rows {
+				if err := w.Write(row); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			file := buf.Bytes()
+			meta := readFooter(t, file)
+
+			if meta[1] != int64(1) {
+				t.Errorf("version = %v, want 1", meta[1])
+			}
+			if meta[3] != int64(len(tt.rows)) {
+				t.Errorf("num_rows = %v, want %d", meta[3], len(tt.rows))
+			}
+			schema := meta[2].([]any)
+			if len(schema) != len(testColumns)+1 {
+				t.Fatalf("schema has %d elements, want %d", len(schema), len(testColumns)+1)
+			}
+			root := schema[0].(map[int16]any)
+			if root[4] != "schema" || root[5] != int64(len(testColumns)) {
+				t.Errorf("schema root = %v", root)
+			}
+			for i, c := range testColumns {
+				element := schema[i+1].(map[int16]any)
+				if element[4] != c.Name || element[1] != int64(physicalType(c.Type)) || element[3] != int64(repetition_Required) {
+					t.Errorf("schema element %d = %v, want the column %+v", i, element, c)
+				}
+				converted, ok := convertedType(c.Type)
+				if got, exists := element[6]; exists != ok || (ok && got != int64(converted)) {
+					t.Errorf("schema element %d converted type = %v, want %v", i, got, converted)
+				}
+			}
+
+			groups := meta[4].([]any)
+			if len(groups) != len(tt.wantGroups) {
+				t.Fatalf("%d row groups, want %d", len(groups), len(tt.wantGroups))
+			}
+			var rows [][]any
+			for g, item := range groups {
+				group := item.(map[int16]any)
+				rowsNb := group[3].(int64)
+				if rowsNb != tt.wantGroups[g] {
+					t.Errorf("row group %d has %d rows, want %d", g, rowsNb, tt.wantGroups[g])
+				}
+				groupRows := make([][]any, rowsNb)
+				var groupSize int64
+				for i, chunkItem := range group[1].([]any) {
+					chunk := chunkItem.(map[int16]any)
+					chunkMeta := chunk[3].(map[int16]any)
+					offset := chunkMeta[9].(int64)
+					if chunk[2] != offset {
+						t.Errorf("row group %d column %d file_offset = %v, want %d", g, i, chunk[2], offset)
+					}
+					if !reflect.DeepEqual(chunkMeta[3], []any{testColumns[i].Name}) || chunkMeta[4] != int64(codec_Uncompressed) || chunkMeta[5] != rowsNb {
+						t.Errorf("row group %d column %d meta = %v", g, i, chunkMeta)
+					}
+
+					header, data := readPage(t, file, offset)
+					if header[1] != int64(pageType_Data) || header[2] != int64(len(data)) {
+						t.Errorf("row group %d column %d page header = %v", g, i, header)
+					}
+					if size := int64(len(data)) + int64(len(w.pageHeaderOf(int(rowsNb), len(data)))); chunkMeta[6] != size || chunkMeta[7] != size {
+						t.Errorf("row group %d column %d chunk size = %v, want %d", g, i, chunkMeta[6], size)
+					}
+					groupSize += chunkMeta[6].(int64)
+					dataHeader := header[5].(map[int16]any)
+					if dataHeader[1] != rowsNb || dataHeader[2] != int64(encoding_Plain) {
+						t.Errorf("row group %d column %d data page header = %v", g, i, dataHeader)
+					}
+					for r, v := range decodeValues(t, testColumns[i], data, int(rowsNb)) {
+						groupRows[r] = append(groupRows[r], v)
+					}
+				}
+				if group[2] != groupSize {
+					t.Errorf("row group %d total_byte_size = %v, want %d", g, group[2], groupSize)
+				}
+				rows = append(rows, groupRows...)
+			}
+
+			want := make([][]any, 0, len(tt.rows))
+			for _, row := range tt.rows {
+				wantRow := make([]any, len(row))
+				copy(wantRow, row)
+				wantRow[3] = row[3].(time.Time).Truncate(time.Microsecond)
+				want = append(want, wantRow)
+			}
+			if len(rows) != len(want) {
+				t.Fatalf("%d rows read, want %d", len(rows), len(want))
+			}
+			for i := range want {
+				if !reflect.DeepEqual(rows[i], want[i]) {
+					t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPageHeaderGolden(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{}, testColumns, 0)
+	w.rowsNb = 2
+	want := []byte{
+		0x15, 0x00, // type: DATA_PAGE
+		0x15, 0x20, // uncompressed_page_size: 16
+		0x15, 0x20, // compressed_page_size: 16
+		0x2c,       // data_page_header
+		0x15, 0x04, // num_values: 2
+		0x15, 0x00, // encoding: PLAIN
+		0x15, 0x06, // definition_level_encoding: RLE
+		0x15, 0x06, // repetition_level_encoding: RLE
+		0x00, // end of data_page_header
+		0x00, // end of PageHeader
+	}
+	if got := w.pageHeader(16); !bytes.Equal(got, want) {
+		t.Errorf("pageHeader() = % x, want % x", got, want)
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []Column
+		row     []any
+	}{
+		{name: "wrong number of values", columns: testColumns, row: []any{int64(1)}},
+		{name: "wrong value type", columns: []Column{{Name: "id", Type: Type_Int64}}, row: []any{1}},
+		{name: "unknown column type", columns: []Column{{Name: "id", Type: 100}}, row: []any{int64(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter(&bytes.Buffer{}, tt.columns, 0)
+			if err := w.Write(tt.row); err == nil {
+				t.Error("Write() error = nil, want the error")
+			}
+		})
+	}
+}
+
+// pageHeaderOf returns the page header of the page of the rows and the size.
+func (w *Writer) pageHeaderOf(rowsNb int, size int) []byte {
+	saved := w.rowsNb
+	w.rowsNb = rowsNb
+	defer func() { w.rowsNb = saved }()
+	return w.pageHeader(size)
+}
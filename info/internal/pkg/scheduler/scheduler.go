@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	logKey_Job = "job"
+)
+
+// Config is a schedule of a single job. A job with zero Interval is disabled.
+type Config struct {
+	Interval      time.Duration
+	InitialJitter time.Duration
+}
+
+type job struct {
+	name   string
+	config *Config
+	exec   func(ctx context.Context)
+}
+
+// Scheduler runs every added job periodically in its own goroutine.
+// A job is never started while its previous run is still going: a tick that comes during a run is dropped.
+type Scheduler struct {
+	logger *zap.Logger
+	jobs   []job
+}
+
+func New(logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		jobs:   make([]job, 0, 3),
+	}
+}
+
+func (s *Scheduler) Add(name string, config *Config, exec func(ctx context.Context)) *Scheduler {
+	s.jobs = append(s.jobs, job{
+		name:   name,
+		config: config,
+		exec:   exec,
+	})
+	return s
+}
+
+// Run blocks until ctx is done and all the running jobs are finished.
+func (s *Scheduler) Run(ctx context.Context) {
+	wg := &sync.WaitGroup{}
+	var item job
+
+	for _, item = range s.jobs {
+		if item.config == nil || item.config.Interval <= 0 {
+			s.logger.Info("scheduler: job is disabled", zap.String(logKey_Job, item.name))
+			continue
+		}
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			s.runJob(ctx, j)
+		}(item)
+	}
+
+	wg.Wait()
+}
+
+func (s *Scheduler) runJob(ctx context.Context, j job) {
+	if j.config.InitialJitter > 0 {
+		jitter := time.Duration(rand.Int63n(int64(j.config.InitialJitter)))
+		s.logger.Info("scheduler: job is delayed by initial jitter", zap.String(logKey_Job, j.name), zap.Duration("jitter", jitter))
+		timer := time.NewTimer(jitter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+
+	// тикер сбрасывает тики, пропущенные во время выполнения, но один тик остаётся в буфере канала:
+	// его вычитываем после запуска, чтобы долгий запуск не сменялся сразу следующим
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.exec(ctx, j)
+		select {
+		case <-ticker.C:
+		default:
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("scheduler: job is stopped", zap.String(logKey_Job, j.name))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) exec(ctx context.Context, j job) {
+	if ctx.Err() != nil {
+		return
+	}
+	start := time.Now()
+	s.logger.Info("scheduler: job run is started", zap.String(logKey_Job, j.name))
+	j.exec(ctx)
+	s.logger.Info("scheduler: job run is finished", zap.String(logKey_Job, j.name), zap.Duration("duration", time.Since(start)))
+}
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestScheduler_Run(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		runTime time.Duration // the duration of a run of the job
+		wantMin int32
+		wantMax int32
+	}{
+		{name: "nil config is disabled", config: nil, wantMin: 0, wantMax: 0},
+		{name: "zero interval is disabled", config: &Config{}, wantMin: 0, wantMax: 0},
+		{name: "job runs at once and by the interval", config: &Config{Interval: 20 * time.Millisecond}, wantMin: 3, wantMax: 7},
+		{name: "long runs drop the ticks", config: &Config{Interval: 10 * time.Millisecond}, runTime: 40 * time.Millisecond, wantMin: 2, wantMax: 4},
+		{name: "jitter longer than the run delays the job", config: &Config{Interval: time.Millisecond, InitialJitter: time.Hour}, wantMin: 0, wantMax: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			var runs, inProgress, overlaps int32
+			New(zap.NewNop()).Add("job", tt.config, func(ctx context.Context) {
+				if atomic.AddInt32(&inProgress, 1) > 1 {
+					atomic.AddInt32(&overlaps, 1)
+				}
+				atomic.AddInt32(&runs, 1)
+				time.Sleep(tt.runTime)
+				atomic.AddInt32(&inProgress, -1)
+			}).Run(ctx)
+
+			if runs < tt.wantMin || runs > tt.wantMax {
+				t.Fatalf("runs = %d, want from %d to %d", runs, tt.wantMin, tt.wantMax)
+			}
+			if overlaps != 0 {
+				t.Fatalf("overlapped runs = %d, want 0", overlaps)
+			}
+			if inProgress != 0 {
+				t.Fatal("Run returned before the job run is finished")
+			}
+		})
+	}
+}
+
+func TestScheduler_Run_jobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var a, b int32
+	s := New(zap.NewNop()).
+		Add("a", &Config{Interval: time.Hour}, func(ctx context.Context) { atomic.AddInt32(&a, 1) }).
+		Add("b", &Config{Interval: time.Hour}, func(ctx context.Context) {
+			atomic.AddInt32(&b, 1)
+			cancel()
+		})
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run is not stopped by the context")
+	}
+	if b != 1 || a > 1 {
+		t.Fatalf("runs a = %d, b = %d; want every job run at most once", a, b)
+	}
+}
+
+func TestScheduler_Run_waitsAfterLongRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	var finished time.Time
+	var minGap time.Duration = time.Hour
+	New(zap.NewNop()).Add("job", &Config{Interval: 20 * time.Millisecond}, func(ctx context.Context) {
+		if !finished.IsZero() {
+			if gap := time.Since(finished); gap < minGap {
+				minGap = gap
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		finished = time.Now()
+	}).Run(ctx)
+
+	// the tick missed during the run is dropped: the next run waits for the next tick, 10ms after the end of the run
+	if minGap < 5*time.Millisecond {
+		t.Fatalf("min gap between the runs = %v, want the wait for the next tick", minGap)
+	}
+}
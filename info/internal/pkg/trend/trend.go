@@ -0,0 +1,163 @@
+package trend
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	Direction_Rise = "rise"
+	Direction_Fall = "fall"
+)
+
+// Point is the value of the series at the time
+type Point struct {
+	T time.Time
+	V float64
+}
+
+// Series is the list of the points in any order
+type Series []Point
+
+// Config is the tuning of the detection
+type Config struct {
+	Period       time.Duration // Максимальный период времени, который смотрим (от now назад)
+	MaxBreak     time.Duration // Максимальный перерыв в тренде
+	MinMagnitude float64       // Минимальное изменение за тренд в процентах от начального значения
+}
+
+// Run is the trend from the point From to the point To
+type Run struct {
+	Direction string
+	From      Point
+	To        Point
+}
+
+type RunList []Run
+
+func (e *Run) Duration() time.Duration {
+	return e.To.T.Sub(e.From.T)
+}
+
+// Change returns the absolute change of the value: it is positive both for the rise and for the fall.
+func (e *Run) Change() float64 {
+	return math.Abs(e.To.V - e.From.V)
+}
+
+// ChangePercent returns the absolute change in percents of the From value; it is 0 if the From value is 0.
+func (e *Run) ChangePercent() float64 {
+	if e.From.V == 0 {
+		return 0
+	}
+	return math.Abs(e.Change() * 100 / e.From.V)
+}
+
+func DirectionValidate(direction string) error {
+	if direction != Direction_Rise && direction != Direction_Fall {
+		return fmt.Errorf("unknown trend direction: %q", direction)
+	}
+	return nil
+}
+
+// Detect returns the latest run of the direction within the period or nil if there is no such run.
+func Detect(series Series, direction string, cfg Config, now time.Time) *Run {
+	l := Runs(series, direction, cfg, now)
+	if len(l) == 0 {
+		return nil
+	}
+	return &l[0]
+}
+
+// Runs returns the runs of the direction within the period, the newest first.
+// Going back in time from the newest point, the run ends at the point after which the series goes in the direction
+// and starts at the oldest point which is still beyond the end value; the points which are not beyond the end value
+// are allowed inside the run for less than MaxBreak. The runs whose change is less than MinMagnitude are skipped.
+func Runs(series Series, direction string, cfg Config, now time.Time) RunList {
+	res := make(RunList, 0)
+	if len(series) < 2 || DirectionValidate(direction) != nil {
+		return res
+	}
+	points := slices.Clone(series)
+	// сортируем по убыванию времени, поэтому в цикле next идёт перед prev
+	slices.SortFunc(points, func(a, b Point) int {
+		return b.T.Compare(a.T)
+	})
+	minTime := now.Add(-cfg.Period)
+
+	var inRun bool
+	var prev, next, start, end *Point
+	for i := range points {
+		prev = &points[i]
+		// первую итерацию просто пропустим
+		if i == 0 {
+			next = prev
+			continue
+		}
+		// дальше minTime не смотрим
+		if prev.T.Before(minTime) {
+			break
+		}
+
+		if !inRun {
+			end = next
+		}
+
+		// если это не тренд, то пропустим
+		if !isBeyond(direction, prev, end) {
+			// если уже нашли тренд, то проверяем на MaxBreak
+			if inRun && start.T.Sub(prev.T) >= cfg.MaxBreak {
+				res = appendRun(res, direction, start, end, cfg)
+				inRun = false
+			}
+			next = prev
+			continue
+		}
+		inRun = true
+		start = prev
+		next = prev
+	}
+	if inRun {
+		res = appendRun(res, direction, start, end, cfg)
+	}
+	return res
+}
+
+// isBeyond returns true if the run of the direction may start at the point p and end at the point end.
+func isBeyond(direction string, p *Point, end *Point) bool {
+	if direction == Direction_Rise {
+		return p.V < end.V
+	}
+	return p.V > end.V
+}
+
+func appendRun(l RunList, direction string, start *Point, end *Point, cfg Config) RunList {
+	run := Run{
+		Direction: direction,
+		From:      *start,
+		To:        *end,
+	}
+	if run.ChangePercent() < cfg.MinMagnitude {
+		return l
+	}
+	return append(l, run)
+}
+
+// MaxDrawdown returns the biggest fall of the values from the running peak in percents of the peak (not rounded)
+// and the indexes of the peak and of the trough; the peaks which are not positive are skipped.
+func MaxDrawdown(values []float64) (percent float64, peak int, trough int) {
+	var maxIndex int
+	for i := range values {
+		if values[i] > values[maxIndex] {
+			maxIndex = i
+		}
+		if values[maxIndex] <= 0 {
+			continue
+		}
+		if p := (values[maxIndex] - values[i]) * 100 / values[maxIndex]; p > percent {
+			percent, peak, trough = p, maxIndex, i
+		}
+	}
+	return percent, peak, trough
+}
@@ -0,0 +1,228 @@
+package trend
+
+import (
+	"testing"
+	"time"
+)
+
+var day0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func day(i int) time.Time {
+	return day0.AddDate(0, 0, i)
+}
+
+// daily returns the series of the values by the days from day0.
+func daily(values ...float64) Series {
+	res := make(Series, 0, len(values))
+	for i, v := range values {
+		res = append(res, Point{T: day(i), V: v})
+	}
+	return res
+}
+
+func TestRuns(t *testing.T) {
+	cfg := Config{Period: 30 * 24 * time.Hour, MaxBreak: 5 * 24 * time.Hour}
+	tests := []struct {
+		name      string
+		series    Series
+		direction string
+		cfg       Config
+		now       time.Time
+		want      RunList
+	}{
+		{
+			name:      "no points",
+			series:    Series{},
+			direction: Direction_Fall,
+			cfg:       cfg,
+			now:       day(0),
+			want:      RunList{},
+		},
+		{
+			name:      "single point",
+			series:    daily(10),
+			direction: Direction_Fall,
+			cfg:       cfg,
+			now:       day(0),
+			want:      RunList{},
+		},
+		{
+			name:      "flat series",
+			series:    daily(10, 10, 10, 10),
+			direction: Direction_Rise,
+			cfg:       cfg,
+			now:       day(3),
+			want:      RunList{},
+		},
+		{
+			name:      "unknown direction",
+			series:    daily(10, 9, 8),
+			direction: "sideways",
+			cfg:       cfg,
+			now:       day(2),
+			want:      RunList{},
+		},
+		{
+			name:      "fall",
+			series:    daily(10, 9, 8),
+			direction: Direction_Fall,
+			cfg:       cfg,
+			now:       day(2),
+			want:      RunList{{Direction: Direction_Fall, From: Point{day(0), 10}, To: Point{day(2), 8}}},
+		},
+		{
+			name:      "rise",
+			series:    daily(8, 9, 10),
+			direction: Direction_Rise,
+			cfg:       cfg,
+			now:       day(2),
+			want:      RunList{{Direction: Direction_Rise, From: Point{day(0), 8}, To: Point{day(2), 10}}},
+		},
+		{
+			name:      "no rise in the fall",
+			series:    daily(10, 9, 8),
+			direction: Direction_Rise,
+			cfg:       cfg,
+			now:       day(2),
+			want:      RunList{},
+		},
+		{
+			name:      "the run ends at the last turn",
+			series:    daily(10, 8, 9),
+			direction: Direction_Fall,
+			cfg:       cfg,
+			now:       day(2),
+			want:      RunList{{Direction: Direction_Fall, From: Point{day(0), 10}, To: Point{day(1), 8}}},
+		},
+		{
+			name:      "points in any order",
+			series:    Series{{day(2), 8}, {day(0), 10}, {day(1), 9}},
+			direction: Direction_Fall,
+			cfg:       cfg,
+			now:       day(2),
+			want:      RunList{{Direction: Direction_Fall, From: Point{day(0), 10}, To: Point{day(2), 8}}},
+		},
+		{
+			name:      "gap in the data does not break the run",
+			series:    Series{{day(0), 10}, {day(10), 8}},
+			direction: Direction_Fall,
+			cfg:       cfg,
+			now:       day(10),
+			want:      RunList{{Direction: Direction_Fall, From: Point{day(0), 10}, To: Point{day(10), 8}}},
+		},
+		{
+			name:      "break shorter than MaxBreak is inside the run",
+			series:    daily(10, 9, 7.5, 8.5, 8),
+			direction: Direction_Fall,
+			cfg:       cfg,
+			now:       day(4),
+			want:      RunList{{Direction: Direction_Fall, From: Point{day(0), 10}, To: Point{day(4), 8}}},
+		},
+		{
+			name:      "break of MaxBreak splits the runs",
+			series:    daily(10, 9, 7.5, 8.5, 8),
+			direction: Direction_Fall,
+			cfg:       Config{Period: cfg.Period, MaxBreak: 24 * time.Hour},
+			now:       day(4),
+			want: RunList{
+				{Direction: Direction_Fall, From: Point{day(3), 8.5}, To: Point{day(4), 8}},
+				{Direction: Direction_Fall, From: Point{day(0), 10}, To: Point{day(2), 7.5}},
+			},
+		},
+		{
+			name:      "run below MinMagnitude is skipped",
+			series:    daily(10, 9, 7.5, 8.5, 8),
+			direction: Direction_Fall,
+			cfg:       Config{Period: cfg.Period, MaxBreak: 24 * time.Hour, MinMagnitude: 10},
+			now:       day(4),
+			want:      RunList{{Direction: Direction_Fall, From: Point{day(0), 10}, To: Point{day(2), 7.5}}},
+		},
+		{
+			name:      "points before the period are not looked at",
+			series:    daily(10, 9, 8),
+			direction: Direction_Fall,
+			cfg:       Config{Period: 36 * time.Hour, MaxBreak: cfg.MaxBreak},
+			now:       day(2),
+			want:      RunList{{Direction: Direction_Fall, From: Point{day(1), 9}, To: Point{day(2), 8}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Runs(tt.series, tt.direction, tt.cfg, tt.now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Runs() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Direction != tt.want[i].Direction || !got[i].From.T.Equal(tt.want[i].From.T) || got[i].From.V != tt.want[i].From.V ||
+					!got[i].To.T.Equal(tt.want[i].To.T) || got[i].To.V != tt.want[i].To.V {
+					t.Fatalf("run %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+
+			run := Detect(tt.series, tt.direction, tt.cfg, tt.now)
+			if len(tt.want) == 0 {
+				if run != nil {
+					t.Fatalf("Detect() = %+v, want nil", run)
+				}
+				return
+			}
+			if run == nil || !run.To.T.Equal(tt.want[0].To.T) {
+				t.Fatalf("Detect() = %+v, want the newest run %+v", run, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestRun_values(t *testing.T) {
+	tests := []struct {
+		name              string
+		run               Run
+		wantDuration      time.Duration
+		wantChange        float64
+		wantChangePercent float64
+	}{
+		{name: "fall", run: Run{Direction: Direction_Fall, From: Point{day(0), 10}, To: Point{day(2), 8}}, wantDuration: 48 * time.Hour, wantChange: 2, wantChangePercent: 20},
+		{name: "rise", run: Run{Direction: Direction_Rise, From: Point{day(0), 8}, To: Point{day(1), 10}}, wantDuration: 24 * time.Hour, wantChange: 2, wantChangePercent: 25},
+		{name: "zero from", run: Run{Direction: Direction_Rise, From: Point{day(0), 0}, To: Point{day(1), 5}}, wantDuration: 24 * time.Hour, wantChange: 5, wantChangePercent: 0},
+		{name: "zero duration", run: Run{Direction: Direction_Fall, From: Point{day(3), 5}, To: Point{day(3), 4}}, wantDuration: 0, wantChange: 1, wantChangePercent: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.run.Duration(); got != tt.wantDuration {
+				t.Fatalf("Duration() = %v, want %v", got, tt.wantDuration)
+			}
+			if got := tt.run.Change(); got != tt.wantChange {
+				t.Fatalf("Change() = %v, want %v", got, tt.wantChange)
+			}
+			if got := tt.run.ChangePercent(); got != tt.wantChangePercent {
+				t.Fatalf("ChangePercent() = %v, want %v", got, tt.wantChangePercent)
+			}
+		})
+	}
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      []float64
+		wantPercent float64
+		wantPeak    int
+		wantTrough  int
+	}{
+		{name: "empty", values: nil},
+		{name: "rising", values: []float64{100, 110, 120}},
+		{name: "single trough", values: []float64{100, 120, 90, 110}, wantPercent: 25, wantPeak: 1, wantTrough: 2},
+		{name: "deeper trough after recovery", values: []float64{100, 80, 130, 65, 140}, wantPercent: 50, wantPeak: 2, wantTrough: 3},
+		{name: "non-positive peak", values: []float64{0, -10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, peak, trough := MaxDrawdown(tt.values)
+			if percent != tt.wantPercent || peak != tt.wantPeak || trough != tt.wantTrough {
+				t.Errorf("MaxDrawdown() = %v, %d, %d, want %v, %d, %d", percent, peak, trough, tt.wantPercent, tt.wantPeak, tt.wantTrough)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Run puts the items to a queue and calls exec for every item from up to concurrency workers.
+// exec is called for every item even after ctx is done, so it has to check ctx itself.
+// Run returns when all the items have been processed.
+func Run[T any](ctx context.Context, concurrency uint, items []T, exec func(ctx context.Context, item T)) {
+	if len(items) == 0 {
+		return
+	}
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	if concurrency > uint(len(items)) {
+		concurrency = uint(len(items))
+	}
+
+	queue := make(chan T)
+	wg := &sync.WaitGroup{}
+	for i := uint(0); i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				exec(ctx, item)
+			}
+		}()
+	}
+
+	for _, item := range items {
+		queue <- item
+	}
+	close(queue)
+	wg.Wait()
+}
@@ -0,0 +1,80 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		concurrency  uint
+		itemsNb      int
+		wantParallel int32 // the max number of the items in progress at once
+	}{
+		{name: "no items", concurrency: 3, itemsNb: 0, wantParallel: 0},
+		{name: "zero concurrency is one worker", concurrency: 0, itemsNb: 5, wantParallel: 1},
+		{name: "one worker", concurrency: 1, itemsNb: 5, wantParallel: 1},
+		{name: "concurrency limits the workers", concurrency: 3, itemsNb: 10, wantParallel: 3},
+		{name: "workers are limited by the items", concurrency: 10, itemsNb: 2, wantParallel: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]int, tt.itemsNb)
+			for i := range items {
+				items[i] = i
+			}
+			var inProgress, maxInProgress int32
+			mu := sync.Mutex{}
+			done := make(map[int]int, tt.itemsNb)
+
+			Run(context.Background(), tt.concurrency, items, func(ctx context.Context, item int) {
+				n := atomic.AddInt32(&inProgress, 1)
+				for {
+					max := atomic.LoadInt32(&maxInProgress)
+					if n <= max || atomic.CompareAndSwapInt32(&maxInProgress, max, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&inProgress, -1)
+
+				mu.Lock()
+				done[item]++
+				mu.Unlock()
+			})
+
+			if len(done) != tt.itemsNb {
+				t.Fatalf("processed %d items, want %d", len(done), tt.itemsNb)
+			}
+			for item, nb := range done {
+				if nb != 1 {
+					t.Fatalf("item %d is processed %d times", item, nb)
+				}
+			}
+			if maxInProgress != tt.wantParallel {
+				t.Fatalf("max items in progress = %d, want %d", maxInProgress, tt.wantParallel)
+			}
+		})
+	}
+}
+
+func TestRun_canceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls, canceled int32
+	Run(ctx, 2, []int{1, 2, 3, 4}, func(ctx context.Context, item int) {
+		atomic.AddInt32(&calls, 1)
+		if ctx.Err() != nil {
+			atomic.AddInt32(&canceled, 1)
+		}
+	})
+	if calls != 4 || canceled != 4 {
+		t.Fatalf("calls = %d, canceled = %d; want every item called with the canceled context", calls, canceled)
+	}
+}
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table cmc.import_run
+(
+    id                          bigint                  generated always as identity,
+    kind                        text                    not null,
+    status                      text                    not null,
+    items_succeeded             bigint                  not null default 0,
+    items_failed                bigint                  not null default 0,
+    error                       text                    null,
+    started_at                  timestamp               not null,
+    finished_at                 timestamp               null,
+    CONSTRAINT import_run__id__pk PRIMARY KEY (id)
+);
+create index import_run__kind__started_at__ix ON cmc.import_run (kind, started_at desc);
+
+
+create table cmc.import_run_item
+(
+    id                          bigint                  generated always as identity,
+    import_run_id               bigint                  not null,
+    kind                        text                    not null,
+    key                         text                    not null,
+    currency_id                 bigint                  null,
+    rows_upserted               jsonb                   not null default '{}',
+    time_from                   timestamp               null,
+    time_to                     timestamp               null,
+    error                       text                    null,
+    started_at                  timestamp               not null,
+    finished_at                 timestamp               not null,
+    CONSTRAINT import_run_item__id__pk PRIMARY KEY (id),
+    CONSTRAINT import_run_item__import_run_id__fk FOREIGN KEY (import_run_id) REFERENCES cmc.import_run(id) ON DELETE CASCADE
+);
+create index import_run_item__import_run_id__ix ON cmc.import_run_item (import_run_id);
+create index import_run_item__kind__key__started_at__ix ON cmc.import_run_item (kind, key, started_at desc) include (error, finished_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table cmc.import_run_item;
+drop table cmc.import_run;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table cmc.discovery_state
+(
+    currency_id                 bigint                  not null,
+    slug                        text                    not null,
+    promoted_at                 timestamp               not null,
+    last_matched_at             timestamp               not null,
+    CONSTRAINT discovery_state__currency_id__pk PRIMARY KEY (currency_id)
+);
+
+
+create table cmc.discovery_event
+(
+    id                          bigint                  generated always as identity,
+    currency_id                 bigint                  not null,
+    slug                        text                    not null,
+    action                      text                    not null,
+    reason                      text                    not null,
+    cmc_rank                    bigint                  not null default 0,
+    market_cap                  double precision        not null default 0,
+    volume_24h                  double precision        not null default 0,
+    created_at                  timestamp               not null,
+    CONSTRAINT discovery_event__id__pk PRIMARY KEY (id)
+);
+create index discovery_event__created_at__ix ON cmc.discovery_event (created_at desc);
+create index discovery_event__slug__created_at__ix ON cmc.discovery_event (slug, created_at desc);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table cmc.discovery_event;
+drop table cmc.discovery_state;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table cmc.portfolio_item_history
+(
+    portfolio_source_id         text                    not null,
+    currency_id                 bigint                  not null,
+    amount                      double precision        not null,
+    current_price               double precision        not null,
+    crypto_holdings             double precision        not null,
+    holdings_percent            double precision        not null,
+    buy_avg_price               double precision        not null,
+    pl_percent_value            double precision        not null,
+    pl_value                    double precision        not null,
+    total_buy_spent             double precision        not null default 0,
+    updated_at                  timestamp               not null,
+    ts                          timestamp               not null
+);
+create unique index portfolio_item_history__portfolio_source_id__currency_id__ts__ux ON cmc.portfolio_item_history (portfolio_source_id, currency_id, ts);
+select public.create_hypertable('cmc.portfolio_item_history', 'ts', chunk_time_interval => INTERVAL '1 year');
+
+-- история до этой миграции не сохранялась: переносим текущее состояние как первый снимок;
+-- снимок портфеля - это один ts на все его монеты, поэтому берём последний updated_at портфеля
+insert into cmc.portfolio_item_history(portfolio_source_id, currency_id, amount, current_price, crypto_holdings, holdings_percent, buy_avg_price, pl_percent_value, pl_value, total_buy_spent, updated_at, ts)
+select portfolio_source_id, currency_id, amount, current_price, crypto_holdings, holdings_percent, buy_avg_price, pl_percent_value, pl_value, total_buy_spent, updated_at,
+       max(updated_at) over (partition by portfolio_source_id)
+from cmc.portfolio_item;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table cmc.portfolio_item_history;
+-- +goose StatementEnd
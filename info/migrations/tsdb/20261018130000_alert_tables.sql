@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table cmc.alert_rule
+(
+    id                          bigint                  generated always as identity,
+    name                        text                    not null,
+    kind                        text                    not null,
+    currency_id                 bigint                  not null default 0,
+    threshold                   double precision        not null default 0,
+    days                        bigint                  not null default 0,
+    direction                   text                    not null default '',
+    report                      text                    not null default '',
+    top_n                       bigint                  not null default 0,
+    cooldown_sec                bigint                  not null,
+    notifiers                   text[]                  not null default '{}',
+    is_enabled                  boolean                 not null default true,
+    matched_currency_ids        bigint[]                not null default '{}',
+    created_at                  timestamp               not null,
+    updated_at                  timestamp               not null,
+    CONSTRAINT alert_rule__id__pk PRIMARY KEY (id)
+);
+
+
+create table cmc.alert
+(
+    id                          bigint                  generated always as identity,
+    rule_id                     bigint                  not null,
+    rule_name                   text                    not null,
+    kind                        text                    not null,
+    currency_id                 bigint                  not null,
+    symbol                      text                    not null,
+    value                       double precision        not null,
+    message                     text                    not null,
+    notified                    text[]                  not null default '{}',
+    error                       text                    null,
+    fired_at                    timestamp               not null,
+    CONSTRAINT alert__id__pk PRIMARY KEY (id),
+    CONSTRAINT alert__rule_id__fk FOREIGN KEY (rule_id) REFERENCES cmc.alert_rule(id) ON DELETE CASCADE
+);
+create index alert__fired_at__ix ON cmc.alert (fired_at desc);
+create index alert__rule_id__currency_id__fired_at__ix ON cmc.alert (rule_id, currency_id, fired_at desc);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table cmc.alert;
+drop table cmc.alert_rule;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table cmc.screener_preset
+(
+    id                          bigint                  generated always as identity,
+    name                        text                    not null,
+    normalization               text                    not null,
+    criteria                    jsonb                   not null default '[]',
+    rank_limit                  bigint                  not null,
+    created_at                  timestamp               not null,
+    updated_at                  timestamp               not null,
+    CONSTRAINT screener_preset__id__pk PRIMARY KEY (id)
+);
+create unique index screener_preset__name__ux ON cmc.screener_preset (name);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table cmc.screener_preset;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+create table cmc.data_audit_run
+(
+    id                          bigint                  generated always as identity,
+    series                      text[]                  not null,
+    time_from                   timestamp               not null,
+    issues_nb                   bigint                  not null default 0,
+    quarantined_nb              bigint                  not null default 0,
+    started_at                  timestamp               not null,
+    finished_at                 timestamp               not null,
+    CONSTRAINT data_audit_run__id__pk PRIMARY KEY (id)
+);
+
+
+create table cmc.data_audit_issue
+(
+    id                          bigint                  generated always as identity,
+    run_id                      bigint                  not null,
+    series                      text                    not null,
+    currency_id                 bigint                  not null,
+    symbol                      text                    not null,
+    kind                        text                    not null,
+    field                       text                    not null default '',
+    ts                          timestamp               not null,
+    ts_to                       timestamp               null,
+    value                       double precision        not null default 0,
+    message                     text                    not null,
+    CONSTRAINT data_audit_issue__id__pk PRIMARY KEY (id),
+    CONSTRAINT data_audit_issue__run_id__fk FOREIGN KEY (run_id) REFERENCES cmc.data_audit_run(id) ON DELETE CASCADE
+);
+create index data_audit_issue__run_id__ix ON cmc.data_audit_issue (run_id);
+
+
+-- точки в карантине исключаются из чтения рядов
+create table cmc.data_quarantine
+(
+    series                      text                    not null,
+    currency_id                 bigint                  not null,
+    ts                          timestamp               not null,
+    reason                      text                    not null,
+    created_at                  timestamp               not null
+);
+create unique index data_quarantine__series__currency_id__ts__ux ON cmc.data_quarantine (series, currency_id, ts);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+drop table cmc.data_quarantine;
+drop table cmc.data_audit_issue;
+drop table cmc.data_audit_run;
+-- +goose StatementEnd
@@ -0,0 +1,143 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- изменения доли китов считает отчёт whale momentum (GET /api/v1/cmc/report/whale-momentum)
+DROP VIEW IF EXISTS cmc.cw1;
+DROP VIEW IF EXISTS cmc.cw2;
+DROP VIEW IF EXISTS cmc.cm1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+CREATE VIEW cmc.cw1 AS
+with m2 as (
+    select currency_id, max(d) as d
+    from cmc.concentration
+    where d <= (now() - interval '2 month')::date
+        group by currency_id),
+        m1 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '1 month')::date
+        group by currency_id),
+        w2 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '2 week')::date
+        group by currency_id),
+        w1 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '1 week')::date
+        group by currency_id),
+        n as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '2 day')::date		-- из-за глюков в данных
+        group by currency_id)
+select c.id, c.symbol, round(cast(((cn.whales - cw1.whales) * 100)/cw1.whales * 1000 AS numeric)) as bonus,
+       round(cast(((cn.whales - cw1.whales) * 100)/cw1.whales AS numeric), 4) as week1, round(cast(((cn.whales - cw2.whales) * 100)/cw2.whales AS numeric), 4) as week2,
+       round(cast(((cn.whales - cm1.whales) * 100)/cm1.whales AS numeric), 4) as month1, round(cast(((cn.whales - cm2.whales) * 100)/cm2.whales AS numeric), 4) as month2
+from cmc.currency c
+         inner join m2 on c.id = m2.currency_id
+         inner join m1 on c.id = m1.currency_id
+         inner join w2 on c.id = w2.currency_id
+         inner join w1 on c.id = w1.currency_id
+         inner join n on c.id = n.currency_id
+         inner join cmc.concentration cm2 on m2.currency_id = cm2.currency_id and m2.d = cm2.d
+         inner join cmc.concentration cm1 on m1.currency_id = cm1.currency_id and m1.d = cm1.d
+         inner join cmc.concentration cw2 on w2.currency_id = cw2.currency_id and w2.d = cw2.d
+         inner join cmc.concentration cw1 on w1.currency_id = cw1.currency_id and w1.d = cw1.d
+         inner join cmc.concentration cn on n.currency_id = cn.currency_id and n.d = cn.d
+where c.is_for_observing = true
+order by week1, week2, month1, month2;
+
+CREATE VIEW cmc.cw2 AS
+with m2 as (
+    select currency_id, max(d) as d
+    from cmc.concentration
+    where d <= (now() - interval '2 month')::date
+        group by currency_id),
+        m1 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '1 month')::date
+        group by currency_id),
+        w2 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '2 week')::date
+        group by currency_id),
+        w1 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '1 week')::date
+        group by currency_id),
+        n as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '2 day')::date		-- из-за глюков в данных
+        group by currency_id)
+select c.id, c.symbol, round(cast(((cn.whales - cw2.whales) * 100)/cw2.whales * 1000 AS numeric)) as bonus,
+       round(cast(((cn.whales - cw1.whales) * 100)/cw1.whales AS numeric), 4) as week1, round(cast(((cn.whales - cw2.whales) * 100)/cw2.whales AS numeric), 4) as week2,
+       round(cast(((cn.whales - cm1.whales) * 100)/cm1.whales AS numeric), 4) as month1, round(cast(((cn.whales - cm2.whales) * 100)/cm2.whales AS numeric), 4) as month2
+from cmc.currency c
+         inner join m2 on c.id = m2.currency_id
+         inner join m1 on c.id = m1.currency_id
+         inner join w2 on c.id = w2.currency_id
+         inner join w1 on c.id = w1.currency_id
+         inner join n on c.id = n.currency_id
+         inner join cmc.concentration cm2 on m2.currency_id = cm2.currency_id and m2.d = cm2.d
+         inner join cmc.concentration cm1 on m1.currency_id = cm1.currency_id and m1.d = cm1.d
+         inner join cmc.concentration cw2 on w2.currency_id = cw2.currency_id and w2.d = cw2.d
+         inner join cmc.concentration cw1 on w1.currency_id = cw1.currency_id and w1.d = cw1.d
+         inner join cmc.concentration cn on n.currency_id = cn.currency_id and n.d = cn.d
+where c.is_for_observing = true
+order by week2, week1, month1, month2;
+
+CREATE VIEW cmc.cm1 AS
+with m2 as (
+    select currency_id, max(d) as d
+    from cmc.concentration
+    where d <= (now() - interval '2 month')::date
+        group by currency_id),
+        m1 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '1 month')::date
+        group by currency_id),
+        w2 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '2 week')::date
+        group by currency_id),
+        w1 as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '1 week')::date
+        group by currency_id),
+        n as (
+        select currency_id, max(d) as d
+        from cmc.concentration
+        where d <= (now() - interval '2 day')::date		-- из-за глюков в данных
+        group by currency_id)
+select c.id, c.symbol, round(cast(((cn.whales - cm1.whales) * 100)/cm1.whales * 1000 AS numeric), 2) as bonus,
+       round(cast(((cn.whales - cw1.whales) * 100)/cw1.whales AS numeric), 4) as week1, round(cast(((cn.whales - cw2.whales) * 100)/cw2.whales AS numeric), 4) as week2,
+       round(cast(((cn.whales - cm1.whales) * 100)/cm1.whales AS numeric), 4) as month1, round(cast(((cn.whales - cm2.whales) * 100)/cm2.whales AS numeric), 4) as month2
+from cmc.currency c
+         inner join m2 on c.id = m2.currency_id
+         inner join m1 on c.id = m1.currency_id
+         inner join w2 on c.id = w2.currency_id
+         inner join w1 on c.id = w1.currency_id
+         inner join n on c.id = n.currency_id
+         inner join cmc.concentration cm2 on m2.currency_id = cm2.currency_id and m2.d = cm2.d
+         inner join cmc.concentration cm1 on m1.currency_id = cm1.currency_id and m1.d = cm1.d
+         inner join cmc.concentration cw2 on w2.currency_id = cw2.currency_id and w2.d = cw2.d
+         inner join cmc.concentration cw1 on w1.currency_id = cw1.currency_id and w1.d = cw1.d
+         inner join cmc.concentration cn on n.currency_id = cn.currency_id and n.d = cn.d
+where c.is_for_observing = true
+order by month1, week1, week2, month2;
+-- +goose StatementEnd